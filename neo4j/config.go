@@ -40,6 +40,17 @@ type Config struct {
 	//
 	// default: No Op Logger
 	Log Logging
+	// Logging target the driver will send its log outputs to along with fields describing
+	// their context, such as the connection id, the session id, the server address and the
+	// statement. It takes precedence over Log when set.
+	//
+	// default: nil
+	Logger StructuredLogger
+	// Logging target that receives every message exchanged with the server, e.g. to debug
+	// protocol issues. It can be overridden per session through SessionConfig.
+	//
+	// default: nil
+	BoltLogger BoltLogger
 	// Resolver that would be used to resolve initial router address. This may
 	// be useful if you want to provide more than one URL for initial router.
 	// If not specified, the provided bolt+routing URL is used as the initial
@@ -47,11 +58,25 @@ type Config struct {
 	//
 	// default: nil
 	AddressResolver ServerAddressResolver
+	// Strategy that selects the cluster member serving a connection of a routing driver
+	// among the readers or writers of the routing table. It can be one of RoundRobin(),
+	// LeastConnected() and LatencyWeighted() or a custom implementation, for instance one
+	// that prefers members in the same zone. When nil, LeastConnected() is used.
+	//
+	// default: LeastConnected()
+	LoadBalancingStrategy LoadBalancingStrategy
 	// Maximum amount of time a retriable operation would continue retrying. It
 	// cannot be specified as a negative value.
 	//
 	// default: 30 * time.Second
 	MaxTransactionRetryTime time.Duration
+	// Policy that decides whether and when a transaction function is attempted again after it
+	// failed. It can be built from ExponentialRetry() and ConstantRetry(), restricted through
+	// MaxAttempts(), MaxDelay() and RetryIf(), or be a custom implementation. It can be
+	// overridden per transaction function through WithRetryPolicy.
+	//
+	// default: DefaultRetryPolicy()
+	RetryPolicy RetryPolicy
 	// Maximum number of connections per URL to allow on this driver. It
 	// cannot be specified as 0 and negative values are interpreted as
 	// math.MaxInt32.
@@ -63,6 +88,25 @@ type Config struct {
 	//
 	// default: 1 * time.Hour
 	MaxConnectionLifetime time.Duration
+	// Maximum amount of time a pooled connection may stay idle before it is closed.
+	// Values less than or equal to 0 keep idle connections open.
+	//
+	// default: 0
+	MaxConnectionIdleTime time.Duration
+	// Number of idle connections per server that are opened when the driver is created, or
+	// when a cluster member joins the routing table, and that are kept open regardless of
+	// MaxConnectionIdleTime. Connections in use do not count, the pool opens new ones to keep
+	// the minimum idle as long as MaxConnectionPoolSize allows. It cannot be negative nor
+	// exceed MaxConnectionPoolSize.
+	//
+	// default: 0
+	MinIdleConnections int
+	// Pooled connections that have been idle for longer than this are checked to be alive
+	// with a round trip to the server before they are used. Values less than or equal to 0
+	// turn the check off.
+	//
+	// default: 0
+	ConnectionLivenessCheckTimeout time.Duration
 	// Maximum amount of time to either acquire an idle connection from the pool
 	// or create a new connection (when the pool is not full). Negative values
 	// result in an infinite wait time where 0 value results in no timeout which
@@ -79,20 +123,70 @@ type Config struct {
 	//
 	// default: true
 	SocketKeepalive bool
+	// Number of records that are requested at once while iterating over a result, the next
+	// batch is only requested once the current one is consumed. The value FetchAll turns
+	// off fetching in batches and receives all records at once. Fetching in batches requires
+	// Neo4j 4.0 or later, all records are received at once from earlier versions. It can
+	// be overridden per session through SessionConfig and per transaction through
+	// WithFetchSize.
+	//
+	// default: 1000
+	FetchSize int
+	// Handlers for additional struct signatures and Go types, they take precedence over the
+	// handlers of the driver for the signatures and types they register.
+	//
+	// default: nil
+	ValueHandlers []ValueHandler
+	// Function the driver calls with a snapshot of its metrics every MetricsInterval, e.g.
+	// to export them to a monitoring system. It is called from a goroutine of its own until
+	// the driver is closed and should return quickly.
+	//
+	// default: nil
+	MetricsListener func(metrics Metrics)
+	// Interval at which MetricsListener is called. It must be greater than 0 when a
+	// listener is set.
+	//
+	// default: 10 * time.Second
+	MetricsInterval time.Duration
+	// Observers that are notified about completed statements and retried transaction
+	// functions, e.g. to collect metrics about them.
+	//
+	// default: nil
+	Observers []Observer
+	// Tracer that creates spans around statements, explicit transactions, transaction
+	// functions and each of their attempts. The spans are annotated with the statement,
+	// the database, the server address, the counters and the codes of errors.
+	//
+	// default: nil
+	Tracer Tracer
+	// Function that redacts the text of statements before it is added to spans and log
+	// messages, e.g. to remove literals holding sensitive data. Statements are added as is
+	// when not set.
+	//
+	// default: nil
+	RedactStatement func(statement string) string
 }
 
+// defaultFetchSize is the number of records that are requested at once unless configured
+// otherwise
+const defaultFetchSize = 1000
+
 func defaultConfig() *Config {
 	return &Config{
 		Encrypted:                    true,
 		TrustStrategy:                TrustAny(false),
 		Log:                          NoOpLogger(),
 		AddressResolver:              nil,
+		LoadBalancingStrategy:        LeastConnected(),
 		MaxTransactionRetryTime:      30 * time.Second,
+		RetryPolicy:                  DefaultRetryPolicy(),
 		MaxConnectionPoolSize:        100,
 		MaxConnectionLifetime:        1 * time.Hour,
 		ConnectionAcquisitionTimeout: 1 * time.Minute,
 		SocketConnectTimeout:         5 * time.Second,
 		SocketKeepalive:              true,
+		FetchSize:                    defaultFetchSize,
+		MetricsInterval:              10 * time.Second,
 	}
 }
 
@@ -116,6 +210,25 @@ func validateAndNormaliseConfig(config *Config) error {
 		config.MaxConnectionLifetime = 0
 	}
 
+	// Max Connection Idle Time
+	if config.MaxConnectionIdleTime < 0 {
+		config.MaxConnectionIdleTime = 0
+	}
+
+	// Min Idle Connections
+	if config.MinIdleConnections < 0 {
+		return newDriverError("minimum idle connections cannot be smaller than 0, but was %d", config.MinIdleConnections)
+	}
+
+	if config.MinIdleConnections > config.MaxConnectionPoolSize {
+		return newDriverError("minimum idle connections cannot exceed the maximum connection pool size of %d, but was %d", config.MaxConnectionPoolSize, config.MinIdleConnections)
+	}
+
+	// Connection Liveness Check Timeout
+	if config.ConnectionLivenessCheckTimeout < 0 {
+		config.ConnectionLivenessCheckTimeout = 0
+	}
+
 	// Connection Acquisition Timeout
 	if config.ConnectionAcquisitionTimeout < 0 {
 		config.ConnectionAcquisitionTimeout = -1
@@ -126,5 +239,19 @@ func validateAndNormaliseConfig(config *Config) error {
 		config.SocketConnectTimeout = 0
 	}
 
+	// Fetch Size
+	if err := validateFetchSize(config.FetchSize); err != nil {
+		return err
+	}
+
+	if config.FetchSize == FetchDefault {
+		config.FetchSize = defaultFetchSize
+	}
+
+	// Metrics Interval
+	if config.MetricsListener != nil && config.MetricsInterval <= 0 {
+		return newDriverError("metrics interval must be greater than 0 when a metrics listener is set, but was %v", config.MetricsInterval)
+	}
+
 	return nil
 }
@@ -0,0 +1,141 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+// ServerLoad describes a cluster member that is a candidate for serving a connection.
+type ServerLoad struct {
+	// Address of the cluster member.
+	Address ServerAddress
+	// InUse is the number of connections to the member that are currently in use.
+	InUse int
+	// Latency is the average time it took to establish connections to the member, it is 0
+	// as long as the driver has not connected to it.
+	Latency time.Duration
+}
+
+// LoadBalancingStrategy selects the cluster member that serves the next connection of a
+// routing driver among the readers or writers of the routing table.
+type LoadBalancingStrategy interface {
+	// Select returns the index of the member in servers that serves a connection with the
+	// given access mode. It is called for every acquired connection and must not block.
+	Select(mode AccessMode, servers []ServerLoad) int
+}
+
+// RoundRobin returns a load balancing strategy that selects the members in turn.
+func RoundRobin() LoadBalancingStrategy {
+	return &roundRobinStrategy{}
+}
+
+// LeastConnected returns a load balancing strategy that selects the member with the fewest
+// connections in use, members with the same number of connections are selected in turn.
+func LeastConnected() LoadBalancingStrategy {
+	return &leastConnectedStrategy{}
+}
+
+// LatencyWeighted returns a load balancing strategy that selects the member with the lowest
+// latency weighted by its connections in use. Members the driver has not connected to yet are
+// preferred so that their latency gets known.
+func LatencyWeighted() LoadBalancingStrategy {
+	return &latencyWeightedStrategy{}
+}
+
+type roundRobinStrategy struct {
+	readers int64
+	writers int64
+}
+
+// next returns the index to start from for the given number of servers, it advances on every
+// call for each access mode separately
+func (strategy *roundRobinStrategy) next(mode AccessMode, servers int) int {
+	counter := &strategy.writers
+	if mode == AccessModeRead {
+		counter = &strategy.readers
+	}
+
+	return int(uint64(atomic.AddInt64(counter, 1)) % uint64(servers))
+}
+
+func (strategy *roundRobinStrategy) Select(mode AccessMode, servers []ServerLoad) int {
+	return strategy.next(mode, len(servers))
+}
+
+type leastConnectedStrategy struct {
+	roundRobinStrategy
+}
+
+func (strategy *leastConnectedStrategy) Select(mode AccessMode, servers []ServerLoad) int {
+	return selectLowest(strategy.next(mode, len(servers)), servers, func(server ServerLoad) int64 {
+		return int64(server.InUse)
+	})
+}
+
+type latencyWeightedStrategy struct {
+	roundRobinStrategy
+}
+
+func (strategy *latencyWeightedStrategy) Select(mode AccessMode, servers []ServerLoad) int {
+	return selectLowest(strategy.next(mode, len(servers)), servers, func(server ServerLoad) int64 {
+		return int64(server.Latency) * int64(server.InUse+1)
+	})
+}
+
+// selectLowest returns the index of the server with the lowest score, searching from start
+// on so that ties are broken in turn
+func selectLowest(start int, servers []ServerLoad, score func(server ServerLoad) int64) int {
+	selected, lowest := start, score(servers[start])
+	for i := 1; i < len(servers); i++ {
+		index := (start + i) % len(servers)
+		if current := score(servers[index]); current < lowest {
+			selected, lowest = index, current
+		}
+	}
+
+	return selected
+}
+
+// wrapLoadBalancingStrategy adapts the strategy to the connector, which always needs one, a nil
+// strategy selects the least connected member like the default configuration does
+func wrapLoadBalancingStrategy(strategy LoadBalancingStrategy) bolt.LoadBalancer {
+	if strategy == nil {
+		strategy = LeastConnected()
+	}
+
+	return func(mode bolt.AccessMode, servers []bolt.ServerLoad) int {
+		accessMode := AccessModeWrite
+		if mode == bolt.AccessModeRead {
+			accessMode = AccessModeRead
+		}
+
+		loads := make([]ServerLoad, len(servers))
+		for i, server := range servers {
+			loads[i] = ServerLoad{Address: &url.URL{Host: server.Address}, InUse: server.InUse, Latency: server.Latency}
+		}
+
+		return strategy.Select(accessMode, loads)
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Load balancing", func() {
+	servers := func(loads ...ServerLoad) []ServerLoad {
+		return loads
+	}
+
+	selectTimes := func(strategy LoadBalancingStrategy, mode AccessMode, candidates []ServerLoad, times int) []int {
+		var selected []int
+		for i := 0; i < times; i++ {
+			selected = append(selected, strategy.Select(mode, candidates))
+		}
+		return selected
+	}
+
+	Context("RoundRobin", func() {
+		It("should select servers in turn", func() {
+			candidates := servers(ServerLoad{}, ServerLoad{InUse: 5}, ServerLoad{})
+
+			Expect(selectTimes(RoundRobin(), AccessModeRead, candidates, 4)).To(Equal([]int{1, 2, 0, 1}))
+		})
+
+		It("should keep turns per access mode", func() {
+			strategy := RoundRobin()
+			candidates := servers(ServerLoad{}, ServerLoad{})
+
+			Expect(strategy.Select(AccessModeRead, candidates)).To(Equal(1))
+			Expect(strategy.Select(AccessModeWrite, candidates)).To(Equal(1))
+			Expect(strategy.Select(AccessModeRead, candidates)).To(Equal(0))
+		})
+	})
+
+	Context("LeastConnected", func() {
+		It("should select the server with the fewest connections in use", func() {
+			candidates := servers(ServerLoad{InUse: 3}, ServerLoad{InUse: 1}, ServerLoad{InUse: 2})
+
+			Expect(selectTimes(LeastConnected(), AccessModeWrite, candidates, 3)).To(Equal([]int{1, 1, 1}))
+		})
+
+		It("should select servers with the same number of connections in turn", func() {
+			candidates := servers(ServerLoad{InUse: 1}, ServerLoad{InUse: 1}, ServerLoad{InUse: 2})
+
+			Expect(selectTimes(LeastConnected(), AccessModeRead, candidates, 3)).To(Equal([]int{1, 0, 0}))
+		})
+	})
+
+	Context("LatencyWeighted", func() {
+		It("should select the server with the lowest latency", func() {
+			candidates := servers(ServerLoad{Latency: 30 * time.Millisecond}, ServerLoad{Latency: 10 * time.Millisecond})
+
+			Expect(selectTimes(LatencyWeighted(), AccessModeRead, candidates, 2)).To(Equal([]int{1, 1}))
+		})
+
+		It("should weigh the latency by the connections in use", func() {
+			candidates := servers(ServerLoad{Latency: 30 * time.Millisecond}, ServerLoad{Latency: 10 * time.Millisecond, InUse: 3})
+
+			Expect(LatencyWeighted().Select(AccessModeRead, candidates)).To(Equal(0))
+		})
+
+		It("should prefer servers without known latency", func() {
+			candidates := servers(ServerLoad{Latency: time.Millisecond}, ServerLoad{}, ServerLoad{Latency: time.Millisecond})
+
+			Expect(LatencyWeighted().Select(AccessModeWrite, candidates)).To(Equal(1))
+		})
+	})
+
+	Context("wrapLoadBalancingStrategy", func() {
+		It("should select the least connected server without strategy", func() {
+			balancer := wrapLoadBalancingStrategy(nil)
+			candidates := []bolt.ServerLoad{{Address: "a:7687", InUse: 1}, {Address: "b:7687", InUse: 1}, {Address: "c:7687", InUse: 2}}
+
+			Expect(balancer(bolt.AccessModeRead, candidates)).To(Equal(1))
+			Expect(balancer(bolt.AccessModeRead, candidates)).To(Equal(0))
+			Expect(balancer(bolt.AccessModeRead, candidates)).To(Equal(0))
+		})
+
+		It("should pass the access mode and the loads of the servers", func() {
+			var mode AccessMode
+			var loads []ServerLoad
+			balancer := wrapLoadBalancingStrategy(strategyFunc(func(m AccessMode, servers []ServerLoad) int {
+				mode, loads = m, servers
+				return 1
+			}))
+
+			selected := balancer(bolt.AccessModeRead, []bolt.ServerLoad{
+				{Address: "reader1:7687"},
+				{Address: "[::1]:7688", InUse: 2, Latency: time.Second},
+			})
+
+			Expect(selected).To(Equal(1))
+			Expect(mode).To(Equal(AccessModeRead))
+			Expect(loads).To(HaveLen(2))
+			Expect(loads[0].Address.Hostname()).To(Equal("reader1"))
+			Expect(loads[0].Address.Port()).To(Equal("7687"))
+			Expect(loads[1].Address.Hostname()).To(Equal("::1"))
+			Expect(loads[1].Address.Port()).To(Equal("7688"))
+			Expect(loads[1].InUse).To(Equal(2))
+			Expect(loads[1].Latency).To(Equal(time.Second))
+		})
+	})
+})
+
+type strategyFunc func(mode AccessMode, servers []ServerLoad) int
+
+func (f strategyFunc) Select(mode AccessMode, servers []ServerLoad) int {
+	return f(mode, servers)
+}
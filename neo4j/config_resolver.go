@@ -22,7 +22,7 @@ package neo4j
 import (
 	"net/url"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 // ServerAddress represents a host and port. Host can either be an IP address or a DNS name.
@@ -56,7 +56,7 @@ func NewServerAddress(hostname string, port string) ServerAddress {
 	return newServerAddressURL(hostname, port)
 }
 
-func wrapAddressResolverOrNil(addressResolver ServerAddressResolver) gobolt.URLAddressResolver {
+func wrapAddressResolverOrNil(addressResolver ServerAddressResolver) bolt.URLAddressResolver {
 	if addressResolver == nil {
 		return nil
 	}
@@ -0,0 +1,128 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy decides whether a transaction function is attempted again after it failed, and
+// how long the driver waits before doing so. Retries stop once MaxTransactionRetryTime has
+// elapsed since the first failure, regardless of the policy.
+type RetryPolicy interface {
+	// ShouldRetry returns whether the transaction function is attempted again after the
+	// given attempt, counted from 1, failed with err.
+	ShouldRetry(err error, attempt int) bool
+	// Delay returns how long to wait before the attempt following the given one.
+	Delay(attempt int) time.Duration
+}
+
+// DefaultRetryPolicy returns the policy the driver uses unless configured otherwise, it
+// retries transient errors, unavailable servers and writes sent to servers that no longer
+// accept them after an initial delay of 1 second that doubles on every attempt, with a
+// jitter of 20%.
+func DefaultRetryPolicy() RetryPolicy {
+	return ExponentialRetry(1*time.Second, 2.0, 0.2)
+}
+
+// ExponentialRetry returns a policy that retries the errors retried by default, waiting
+// initialDelay before the second attempt and multiplying the delay by multiplier on each
+// further attempt. Every delay is randomly varied by up to the jitter fraction of it in
+// either direction, so that concurrent retries spread out.
+func ExponentialRetry(initialDelay time.Duration, multiplier float64, jitter float64) RetryPolicy {
+	return &exponentialRetryPolicy{initialDelay: initialDelay, multiplier: multiplier, jitter: jitter}
+}
+
+// ConstantRetry returns a policy that retries the errors retried by default, always waiting
+// the given delay between attempts.
+func ConstantRetry(delay time.Duration) RetryPolicy {
+	return &exponentialRetryPolicy{initialDelay: delay, multiplier: 1}
+}
+
+// MaxAttempts returns a policy that stops retrying after the given number of attempts and
+// otherwise defers to policy.
+func MaxAttempts(attempts int, policy RetryPolicy) RetryPolicy {
+	return &retryPolicyDecorator{RetryPolicy: policy, shouldRetry: func(err error, attempt int) bool {
+		return attempt < attempts && policy.ShouldRetry(err, attempt)
+	}}
+}
+
+// MaxDelay returns a policy that waits at most maxDelay between attempts and otherwise
+// defers to policy.
+func MaxDelay(maxDelay time.Duration, policy RetryPolicy) RetryPolicy {
+	return &retryPolicyDecorator{RetryPolicy: policy, delay: func(attempt int) time.Duration {
+		if delay := policy.Delay(attempt); delay < maxDelay {
+			return delay
+		}
+		return maxDelay
+	}}
+}
+
+// RetryIf returns a policy that decides with shouldRetry which errors are retried and takes
+// the delays from policy, e.g. to retry deadlocks but not leader switches.
+func RetryIf(shouldRetry func(err error, attempt int) bool, policy RetryPolicy) RetryPolicy {
+	return &retryPolicyDecorator{RetryPolicy: policy, shouldRetry: shouldRetry}
+}
+
+type exponentialRetryPolicy struct {
+	initialDelay time.Duration
+	multiplier   float64
+	jitter       float64
+}
+
+func (policy *exponentialRetryPolicy) ShouldRetry(err error, attempt int) bool {
+	return isRetriableError(err)
+}
+
+func (policy *exponentialRetryPolicy) Delay(attempt int) time.Duration {
+	delay := float64(policy.initialDelay) * math.Pow(policy.multiplier, float64(attempt-1))
+	if policy.jitter > 0 {
+		delay += delay * policy.jitter * (2*rand.Float64() - 1)
+	}
+
+	// a large number of attempts would overflow the duration
+	if delay >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(delay)
+}
+
+// retryPolicyDecorator replaces the methods of a policy it has a function for
+type retryPolicyDecorator struct {
+	RetryPolicy
+	shouldRetry func(err error, attempt int) bool
+	delay       func(attempt int) time.Duration
+}
+
+func (decorator *retryPolicyDecorator) ShouldRetry(err error, attempt int) bool {
+	if decorator.shouldRetry != nil {
+		return decorator.shouldRetry(err, attempt)
+	}
+	return decorator.RetryPolicy.ShouldRetry(err, attempt)
+}
+
+func (decorator *retryPolicyDecorator) Delay(attempt int) time.Duration {
+	if decorator.delay != nil {
+		return decorator.delay(attempt)
+	}
+	return decorator.RetryPolicy.Delay(attempt)
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry policies", func() {
+	transient := newDatabaseError("TransientError", "Neo.TransientError.Transaction.DeadlockDetected", "deadlock")
+	unavailable := newConnectorError(0, 0, "", "connection reset", "unable to read message")
+	clientError := newDatabaseError("ClientError", "Neo.ClientError.Statement.SyntaxError", "invalid syntax")
+
+	delays := func(policy RetryPolicy, attempts int) []time.Duration {
+		var delays []time.Duration
+		for attempt := 1; attempt <= attempts; attempt++ {
+			delays = append(delays, policy.Delay(attempt))
+		}
+		return delays
+	}
+
+	Context("ExponentialRetry", func() {
+		It("should multiply the delay on every attempt", func() {
+			Expect(delays(ExponentialRetry(100*time.Millisecond, 2, 0), 4)).To(Equal([]time.Duration{
+				100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond,
+			}))
+		})
+
+		It("should vary the delay by the jitter", func() {
+			policy := ExponentialRetry(time.Second, 2, 0.2)
+
+			for i := 0; i < 100; i++ {
+				Expect(policy.Delay(2)).To(BeNumerically("~", 2*time.Second, 400*time.Millisecond))
+			}
+		})
+
+		It("should not overflow after many attempts", func() {
+			Expect(ExponentialRetry(time.Second, 2, 0).Delay(1000)).To(BeNumerically(">", 0))
+		})
+
+		It("should retry the errors that are retried by default", func() {
+			policy := ExponentialRetry(time.Second, 2, 0)
+
+			Expect(policy.ShouldRetry(transient, 1)).To(BeTrue())
+			Expect(policy.ShouldRetry(clientError, 1)).To(BeFalse())
+			Expect(policy.ShouldRetry(fmt.Errorf("some error"), 1)).To(BeFalse())
+		})
+	})
+
+	Context("ConstantRetry", func() {
+		It("should wait the same delay on every attempt", func() {
+			Expect(delays(ConstantRetry(50*time.Millisecond), 3)).To(Equal([]time.Duration{
+				50 * time.Millisecond, 50 * time.Millisecond, 50 * time.Millisecond,
+			}))
+		})
+	})
+
+	Context("MaxAttempts", func() {
+		It("should stop retrying after the given number of attempts", func() {
+			policy := MaxAttempts(3, ConstantRetry(time.Second))
+
+			Expect(policy.ShouldRetry(transient, 1)).To(BeTrue())
+			Expect(policy.ShouldRetry(transient, 2)).To(BeTrue())
+			Expect(policy.ShouldRetry(transient, 3)).To(BeFalse())
+			Expect(policy.ShouldRetry(clientError, 1)).To(BeFalse())
+			Expect(policy.Delay(2)).To(Equal(time.Second))
+		})
+	})
+
+	Context("MaxDelay", func() {
+		It("should cap the delays", func() {
+			Expect(delays(MaxDelay(300*time.Millisecond, ExponentialRetry(100*time.Millisecond, 2, 0)), 4)).To(Equal([]time.Duration{
+				100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond,
+			}))
+		})
+	})
+
+	Context("RetryIf", func() {
+		It("should decide with the given predicate", func() {
+			policy := RetryIf(func(err error, attempt int) bool {
+				return IsTransientError(err) && attempt < 5
+			}, MaxAttempts(2, ConstantRetry(time.Second)))
+
+			Expect(policy.ShouldRetry(transient, 4)).To(BeTrue())
+			Expect(policy.ShouldRetry(transient, 5)).To(BeFalse())
+			Expect(policy.ShouldRetry(unavailable, 1)).To(BeFalse())
+			Expect(policy.Delay(1)).To(Equal(time.Second))
+		})
+	})
+})
@@ -59,10 +59,33 @@ var _ = Describe("Config", func() {
 			Expect(config.SocketConnectTimeout).To(BeIdenticalTo(5 * time.Second))
 		})
 
+		It("should keep idle connections open", func() {
+			Expect(config.MaxConnectionIdleTime).To(BeZero())
+			Expect(config.MinIdleConnections).To(BeZero())
+			Expect(config.ConnectionLivenessCheckTimeout).To(BeZero())
+		})
+
 		It("should have socket keep alive enabled", func() {
 			Expect(config.SocketKeepalive).To(BeTrue())
 		})
 
+		It("should have fetch size set to 1000", func() {
+			Expect(config.FetchSize).To(BeIdenticalTo(1000))
+		})
+
+		It("should have least connected load balancing", func() {
+			Expect(config.LoadBalancingStrategy).To(Equal(LeastConnected()))
+		})
+
+		It("should retry with the default policy", func() {
+			Expect(config.RetryPolicy).To(Equal(DefaultRetryPolicy()))
+		})
+
+		It("should report metrics every 10s once a listener is set", func() {
+			Expect(config.MetricsListener).To(BeNil())
+			Expect(config.MetricsInterval).To(Equal(10 * time.Second))
+		})
+
 		It("should have non-nil logger", func() {
 			Expect(config.Log).NotTo(BeNil())
 		})
@@ -104,6 +127,45 @@ var _ = Describe("Config", func() {
 			Expect(config.MaxConnectionPoolSize).To(Equal(math.MaxInt32))
 		})
 
+		It("should normalize MaxConnectionIdleTime to 0 when negative", func() {
+			config := defaultConfig()
+			config.MaxConnectionIdleTime = -1 * time.Second
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).To(BeNil())
+
+			Expect(config.MaxConnectionIdleTime).To(Equal(0 * time.Nanosecond))
+		})
+
+		It("should return error when MinIdleConnections is less than 0", func() {
+			config := defaultConfig()
+			config.MinIdleConnections = -1
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("minimum idle connections cannot be smaller than 0"))
+		})
+
+		It("should return error when MinIdleConnections exceeds MaxConnectionPoolSize", func() {
+			config := defaultConfig()
+			config.MaxConnectionPoolSize = 5
+			config.MinIdleConnections = 6
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("minimum idle connections cannot exceed the maximum connection pool size of 5"))
+		})
+
+		It("should normalize ConnectionLivenessCheckTimeout to 0 when negative", func() {
+			config := defaultConfig()
+			config.ConnectionLivenessCheckTimeout = -1 * time.Second
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).To(BeNil())
+
+			Expect(config.ConnectionLivenessCheckTimeout).To(Equal(0 * time.Nanosecond))
+		})
+
 		It("should normalize ConnectionAcquisitionTimeout to -1ns when negative", func() {
 			config := defaultConfig()
 			config.ConnectionAcquisitionTimeout = -1 * time.Second
@@ -123,6 +185,35 @@ var _ = Describe("Config", func() {
 
 			Expect(config.SocketConnectTimeout).To(Equal(0 * time.Nanosecond))
 		})
+
+		It("should return error when FetchSize is smaller than FetchAll", func() {
+			config := defaultConfig()
+			config.FetchSize = -2
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("fetch size cannot be smaller than -1"))
+		})
+
+		It("should normalize FetchSize to default when set to FetchDefault", func() {
+			config := defaultConfig()
+			config.FetchSize = FetchDefault
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).To(BeNil())
+
+			Expect(config.FetchSize).To(Equal(1000))
+		})
+
+		It("should return error when MetricsInterval is not positive and a listener is set", func() {
+			config := defaultConfig()
+			config.MetricsListener = func(metrics Metrics) {}
+			config.MetricsInterval = 0
+
+			err := validateAndNormaliseConfig(config)
+			Expect(err).NotTo(BeNil())
+			Expect(err.Error()).To(ContainSubstring("metrics interval must be greater than 0"))
+		})
 	})
 
 })
@@ -21,6 +21,7 @@
 package neo4j
 
 import (
+	"context"
 	"net/url"
 )
 
@@ -41,6 +42,21 @@ type Driver interface {
 	// The url this driver is bootstrapped
 	Target() url.URL
 	Session(accessMode AccessMode, bookmarks ...string) (Session, error)
+	// SessionContext creates a session whose work is bound to ctx unless another context is
+	// passed explicitly, e.g. through RunContext
+	SessionContext(ctx context.Context, accessMode AccessMode, bookmarks ...string) (Session, error)
+	// NewSession creates a session with the provided configuration, i.e. to execute work
+	// against a specific database
+	NewSession(config SessionConfig) (Session, error)
+	// VerifyConnectivity opens and authenticates a connection to the server and, for routing
+	// URLs, retrieves a routing table. It returns a *ConnectivityError telling why the server
+	// could not be reached.
+	VerifyConnectivity() error
+	// ServerInfo connects to the server, or for routing URLs to a member serving reads, and
+	// returns the information about it without running a statement
+	ServerInfo() (ServerInfo, error)
+	// Metrics returns a snapshot of the connection pools of the driver
+	Metrics() Metrics
 	// Close the driver and all underlying connections
 	Close() error
 }
@@ -84,5 +100,5 @@ func NewDriver(target string, auth AuthToken, configurers ...func(*Config)) (Dri
 		return nil, err
 	}
 
-	return newGoboltDriver(parsed, auth, config)
+	return newNeoDriver(parsed, auth, config)
 }
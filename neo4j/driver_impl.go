@@ -0,0 +1,203 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+type neoDriver struct {
+	config     *Config
+	target     url.URL
+	connector  bolt.Connector
+	marshaller *paramMarshaller
+	log        *logger
+
+	open int32
+	// stopMetrics stops calling the metrics listener, it is nil when none is configured
+	stopMetrics chan struct{}
+}
+
+func configToConnectorConfig(target *url.URL, config *Config) *bolt.Config {
+	return &bolt.Config{
+		Encryption:               config.Encrypted,
+		TLSCertificates:          config.TrustStrategy.certificates,
+		TLSSkipVerify:            config.TrustStrategy.skipVerify,
+		TLSSkipVerifyHostname:    config.TrustStrategy.skipVerifyHostname,
+		Log:                      wrapLoggerOrNil(newLogger(config)),
+		BoltLogger:               config.BoltLogger,
+		AddressResolver:          wrapAddressResolverOrNil(config.AddressResolver),
+		LoadBalancer:             wrapLoadBalancingStrategy(config.LoadBalancingStrategy),
+		MaxPoolSize:              config.MaxConnectionPoolSize,
+		MaxConnLifetime:          config.MaxConnectionLifetime,
+		MaxConnIdleTime:          config.MaxConnectionIdleTime,
+		MinIdleConns:             config.MinIdleConnections,
+		ConnLivenessCheckTimeout: config.ConnectionLivenessCheckTimeout,
+		ConnAcquisitionTimeout:   config.ConnectionAcquisitionTimeout,
+		SockConnectTimeout:       config.SocketConnectTimeout,
+		SockKeepalive:            config.SocketKeepalive,
+		ValueHandlers:            valueHandlers(config.ValueHandlers),
+		GenericErrorFactory:      newDriverError,
+		ConnectorErrorFactory:    connectorErrorFactory(target.Host),
+		DatabaseErrorFactory:     newDatabaseError,
+	}
+}
+
+func newNeoDriver(target *url.URL, token AuthToken, config *Config) (*neoDriver, error) {
+	if config == nil {
+		config = defaultConfig()
+	}
+
+	connector, err := bolt.NewConnector(target, token.tokens, configToConnectorConfig(target, config))
+	if err != nil {
+		return nil, err
+	}
+
+	driver := neoDriver{
+		config:     config,
+		target:     *target,
+		connector:  connector,
+		marshaller: newParamMarshaller(config.ValueHandlers),
+		log:        newLogger(config),
+		open:       1,
+	}
+
+	if config.MetricsListener != nil {
+		driver.stopMetrics = make(chan struct{})
+		go driver.reportMetrics(config.MetricsListener, config.MetricsInterval, driver.stopMetrics)
+	}
+
+	return &driver, nil
+}
+
+func assertDriverOpen(driver *neoDriver) error {
+	if atomic.LoadInt32(&driver.open) == 0 {
+		return newDriverError("cannot acquire a session on a closed driver")
+	}
+
+	return nil
+}
+
+func (driver *neoDriver) Target() url.URL {
+	return driver.target
+}
+
+func (driver *neoDriver) Session(accessMode AccessMode, bookmarks ...string) (Session, error) {
+	if err := assertDriverOpen(driver); err != nil {
+		return nil, err
+	}
+
+	return newSession(context.Background(), driver, SessionConfig{AccessMode: accessMode, Bookmarks: bookmarks}), nil
+}
+
+func (driver *neoDriver) SessionContext(ctx context.Context, accessMode AccessMode, bookmarks ...string) (Session, error) {
+	if err := assertDriverOpen(driver); err != nil {
+		return nil, err
+	}
+
+	return newSession(ctx, driver, SessionConfig{AccessMode: accessMode, Bookmarks: bookmarks}), nil
+}
+
+func (driver *neoDriver) NewSession(config SessionConfig) (Session, error) {
+	if err := assertDriverOpen(driver); err != nil {
+		return nil, err
+	}
+
+	if err := validateFetchSize(config.FetchSize); err != nil {
+		return nil, err
+	}
+
+	return newSession(context.Background(), driver, config), nil
+}
+
+func (driver *neoDriver) VerifyConnectivity() error {
+	if err := assertDriverOpen(driver); err != nil {
+		return err
+	}
+
+	if err := driver.connector.VerifyConnectivity(context.Background()); err != nil {
+		return newConnectivityError(driver.target.Host, err)
+	}
+
+	return nil
+}
+
+func (driver *neoDriver) ServerInfo() (ServerInfo, error) {
+	connection, err := driver.acquire(context.Background(), AccessModeRead, "")
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	return newServerInfo(driver, connection), nil
+}
+
+func (driver *neoDriver) Metrics() Metrics {
+	return newMetrics(driver.connector.Metrics())
+}
+
+// reportMetrics calls the listener with the metrics of the driver at every interval until
+// stop is closed
+func (driver *neoDriver) reportMetrics(listener func(Metrics), interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			listener(driver.Metrics())
+		}
+	}
+}
+
+func (driver *neoDriver) Close() error {
+	if atomic.CompareAndSwapInt32(&driver.open, 1, 0) {
+		if driver.stopMetrics != nil {
+			close(driver.stopMetrics)
+		}
+		return driver.connector.Close()
+	}
+
+	return nil
+}
+
+func (driver *neoDriver) configuration() *Config {
+	return driver.config
+}
+
+func (driver *neoDriver) acquire(ctx context.Context, mode AccessMode, database string) (bolt.Connection, error) {
+	if err := assertDriverOpen(driver); err != nil {
+		return nil, err
+	}
+
+	boltMode := bolt.AccessModeWrite
+	if mode == AccessModeRead {
+		boltMode = bolt.AccessModeRead
+	}
+
+	return driver.connector.Acquire(ctx, boltMode, database)
+}
@@ -0,0 +1,297 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/neo4j/neo4j-go-driver/neo4j/utils/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/utils"
+)
+
+var _ = Describe("Driver", func() {
+	Context("Creation", func() {
+		Context("Authentication Token", func() {
+			driverUrl, _ := url.Parse("bolt://localhost")
+
+			It("should fail when an invalid type is passed", func() {
+				type unsupportedType struct {
+				}
+
+				var token = AuthToken{
+					tokens: map[string]interface{}{
+						"unsupported_type": unsupportedType{},
+					},
+				}
+
+				driver, err := newNeoDriver(driverUrl, token, nil)
+				Expect(err).To(BeGenericError(ContainSubstring("unable to convert authentication token:")))
+				Expect(driver).To(BeNil())
+			})
+		})
+
+		Context("Routing Context", func() {
+			DescribeTable("should not fail",
+				func(uri string) {
+					driverUrl, _ := url.Parse(uri)
+
+					driver, err := newNeoDriver(driverUrl, NoAuth(), nil)
+					Expect(err).To(BeNil())
+					Expect(driver).NotTo(BeNil())
+				},
+				Entry("empty query string", "bolt://localhost"),
+				Entry("query string with empty value", "bolt://localhost?abc"),
+				Entry("query string with value", "bolt://localhost?abc=def"),
+				Entry("query string with values", "bolt://localhost?abc=def&def=gef"),
+			)
+
+			DescribeTable("should fail",
+				func(uri string) {
+					driverUrl, _ := url.Parse(uri)
+
+					driver, err := newNeoDriver(driverUrl, NoAuth(), nil)
+					Expect(err).To(BeGenericError(ContainSubstring("unable to extract routing context")))
+					Expect(driver).To(BeNil())
+				},
+				Entry("incorrect query string 1", "bolt://localhost?a%b"),
+				Entry("incorrect query string 2", "bolt://localhost?abc%d=ef"),
+				Entry("duplicate values", "bolt://localhost?abc=def&def=gef&abc=hij"),
+			)
+		})
+	})
+
+	Context("VerifyConnectivity", func() {
+		var mockCtrl *gomock.Controller
+		var connector *MockConnector
+		var driver *neoDriver
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			connector = NewMockConnector(mockCtrl)
+			driver = newDriverWithConnector("bolt+routing://cluster:7687", connector)
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("should succeed when the connector can connect", func() {
+			connector.EXPECT().VerifyConnectivity(gomock.Any()).Return(nil)
+
+			Expect(driver.VerifyConnectivity()).To(Succeed())
+		})
+
+		It("should fail when the driver is closed", func() {
+			connector.EXPECT().Close().Return(nil)
+			Expect(driver.Close()).To(Succeed())
+
+			Expect(driver.VerifyConnectivity()).To(BeGenericError(ContainSubstring("closed driver")))
+		})
+
+		DescribeTable("should tell why the server could not be reached",
+			func(cause error, expected ConnectivityFailure) {
+				connector.EXPECT().VerifyConnectivity(gomock.Any()).Return(cause)
+
+				err := driver.VerifyConnectivity()
+				Expect(err).To(BeAssignableToTypeOf(&ConnectivityError{}))
+				Expect(err.(*ConnectivityError).Target).To(Equal("cluster:7687"))
+				Expect(err.(*ConnectivityError).Failure).To(Equal(expected))
+				Expect(err.(*ConnectivityError).Unwrap()).To(BeIdenticalTo(cause))
+				Expect(err.Error()).To(HavePrefix("unable to connect to cluster:7687: "))
+			},
+			Entry("unresolved address", newConnectorError(1, bolt.ErrorAddressNotResolved, "", "", ""), ConnectivityFailureAddress),
+			Entry("refused connection", newConnectorError(1, bolt.ErrorConnectionRefused, "", "", ""), ConnectivityFailureAddress),
+			Entry("connect timeout", newConnectorError(1, bolt.ErrorTimedOut, "", "", ""), ConnectivityFailureAddress),
+			Entry("untrusted certificate", newConnectorError(1, bolt.ErrorTLS, "", "", ""), ConnectivityFailureTLS),
+			Entry("rejected credentials", newConnectorError(1, bolt.ErrorPermissionDenied, "", "", ""), ConnectivityFailureAuthentication),
+			Entry("unauthorized", newDatabaseError("ClientError", "Neo.ClientError.Security.Unauthorized", "invalid credentials"), ConnectivityFailureAuthentication),
+			Entry("unsupported protocol", newConnectorError(1, bolt.ErrorProtocolUnsupported, "", "", ""), ConnectivityFailureProtocol),
+			Entry("closed connection", newConnectorError(1, bolt.ErrorEndOfTransmission, "", "", ""), ConnectivityFailureOther),
+		)
+
+		It("should keep the classification helpers working", func() {
+			connector.EXPECT().VerifyConnectivity(gomock.Any()).Return(newConnectorError(1, bolt.ErrorTLS, "", "", ""))
+
+			err := driver.VerifyConnectivity()
+			Expect(IsSecurityError(err)).To(BeTrue())
+			Expect(IsServiceUnavailable(err)).To(BeTrue())
+		})
+	})
+
+	Context("Metrics", func() {
+		It("should key the metrics of the connection pools by address", func() {
+			mockCtrl := gomock.NewController(GinkgoT())
+			defer mockCtrl.Finish()
+
+			connector := NewMockConnector(mockCtrl)
+			connector.EXPECT().Metrics().Return([]bolt.PoolMetrics{
+				{Address: "reader:7687", InUse: 2, Idle: 1, Created: 3},
+				{Address: "writer:7687", FailedToCreate: 1, AcquisitionTimeouts: 4, AcquisitionLatency: bolt.Histogram{
+					Bounds: []time.Duration{time.Millisecond},
+					Counts: []int64{5, 1},
+					Count:  6,
+					Sum:    10 * time.Millisecond,
+				}},
+			})
+			driver := newDriverWithConnector("bolt+routing://cluster:7687", connector)
+
+			metrics := driver.Metrics()
+			Expect(metrics.Time).NotTo(BeZero())
+			Expect(metrics.ConnectionPools).To(HaveLen(2))
+			Expect(metrics.ConnectionPools["reader:7687"]).To(Equal(ConnectionPoolMetrics{
+				Address: "reader:7687",
+				InUse:   2,
+				Idle:    1,
+				Created: 3,
+			}))
+			Expect(metrics.ConnectionPools["writer:7687"]).To(Equal(ConnectionPoolMetrics{
+				Address:             "writer:7687",
+				FailedToCreate:      1,
+				AcquisitionTimeouts: 4,
+				AcquisitionLatency: LatencyHistogram{
+					Bounds: []time.Duration{time.Millisecond},
+					Counts: []int64{5, 1},
+					Count:  6,
+					Sum:    10 * time.Millisecond,
+				},
+			}))
+		})
+
+		It("should call the metrics listener periodically until the driver is closed", func() {
+			reported := make(chan Metrics, 1)
+			driver, err := NewDriver("bolt://localhost:7687", NoAuth(), func(config *Config) {
+				config.MetricsInterval = 10 * time.Millisecond
+				config.MetricsListener = func(metrics Metrics) {
+					select {
+					case reported <- metrics:
+					default:
+					}
+				}
+			})
+			Expect(err).To(BeNil())
+
+			var metrics Metrics
+			Eventually(reported).Should(Receive(&metrics))
+			Expect(metrics.ConnectionPools).To(HaveKey("localhost:7687"))
+
+			Expect(driver.Close()).To(Succeed())
+			time.Sleep(20 * time.Millisecond)
+			select {
+			case <-reported:
+			default:
+			}
+			Consistently(reported, 50*time.Millisecond).ShouldNot(Receive())
+		})
+	})
+
+	Context("ServerInfo", func() {
+		var mockCtrl *gomock.Controller
+		var connector *MockConnector
+		var connection *MockConnection
+		var driver *neoDriver
+
+		BeforeEach(func() {
+			mockCtrl = gomock.NewController(GinkgoT())
+			connector = NewMockConnector(mockCtrl)
+			connection = NewMockConnection(mockCtrl)
+			driver = newDriverWithConnector("bolt+routing://cluster:7687", connector)
+
+			connection.EXPECT().RemoteAddress().Return("reader:7687", nil).AnyTimes()
+			connection.EXPECT().Server().Return("Neo4j/3.5.12", nil).AnyTimes()
+			connection.EXPECT().ProtocolVersion().Return(3, nil).AnyTimes()
+			connection.EXPECT().ServerConnectionId().Return("bolt-42", nil).AnyTimes()
+		})
+
+		AfterEach(func() {
+			mockCtrl.Finish()
+		})
+
+		It("should describe a server serving reads", func() {
+			gomock.InOrder(
+				connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeRead, "").Return(connection, nil),
+				connection.EXPECT().Close().Return(nil),
+			)
+
+			info, err := driver.ServerInfo()
+			Expect(err).To(BeNil())
+			Expect(info.Address()).To(Equal("reader:7687"))
+			Expect(info.Agent()).To(Equal("Neo4j/3.5.12"))
+			Expect(info.Version()).To(Equal("Neo4j/3.5.12"))
+			Expect(info.ProtocolVersion()).To(Equal(3))
+			Expect(info.ConnectionId()).To(Equal("bolt-42"))
+			Expect(info.ParsedVersion().GreaterThanOrEqual(utils.VersionOf("3.5.0"))).To(BeTrue())
+			Expect(info.ParsedVersion().LessThan(utils.VersionOf("4.0.0"))).To(BeTrue())
+		})
+
+		It("should fail when no connection can be acquired", func() {
+			connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeRead, "").Return(nil, newConnectorError(1, bolt.ErrorConnectionRefused, "", "", ""))
+
+			info, err := driver.ServerInfo()
+			Expect(info).To(BeNil())
+			Expect(IsServiceUnavailable(err)).To(BeTrue())
+		})
+
+		It("should connect a session for its access mode and database", func() {
+			gomock.InOrder(
+				connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeWrite, "movies").Return(connection, nil),
+				connection.EXPECT().Close().Return(nil),
+			)
+			session, err := driver.NewSession(SessionConfig{AccessMode: AccessModeWrite, DatabaseName: "movies"})
+			Expect(err).To(BeNil())
+
+			info, err := session.ServerInfo()
+			Expect(err).To(BeNil())
+			Expect(info.ConnectionId()).To(Equal("bolt-42"))
+		})
+
+		It("should describe the connection a session is bound to", func() {
+			session := newSession(context.Background(), driver, SessionConfig{AccessMode: AccessModeRead}).(*neoSession)
+			session.runner = newRunner(driver, AccessModeRead, "", true)
+			session.runner.connection = connection
+
+			info, err := session.ServerInfo()
+			Expect(err).To(BeNil())
+			Expect(info.Address()).To(Equal("reader:7687"))
+		})
+	})
+})
+
+func newDriverWithConnector(target string, connector bolt.Connector) *neoDriver {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil
+	}
+
+	return &neoDriver{
+		config:     defaultConfig(),
+		target:     *targetURL,
+		connector:  connector,
+		marshaller: newParamMarshaller(nil),
+		open:       1,
+	}
+}
@@ -19,20 +19,58 @@
 
 package neo4j
 
-import "C"
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
-type databaseError struct {
-	classification string
+// Status codes of errors reported by the server that applications commonly handle.
+const (
+	StatusConstraintValidationFailed  = "Neo.ClientError.Schema.ConstraintValidationFailed"
+	StatusDeadlockDetected            = "Neo.TransientError.Transaction.DeadlockDetected"
+	StatusNotALeader                  = "Neo.ClientError.Cluster.NotALeader"
+	StatusForbiddenOnReadOnlyDatabase = "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"
+	StatusUnauthorized                = "Neo.ClientError.Security.Unauthorized"
+	StatusTokenExpired                = "Neo.ClientError.Security.TokenExpired"
+)
+
+// Neo4jError is returned when the server fails a request, its status code has the form
+// Neo.<Classification>.<Category>.<Title>, e.g. Neo.ClientError.Schema.ConstraintValidationFailed.
+type Neo4jError struct {
 	code           string
+	classification string
+	category       string
+	title          string
 	message        string
 }
 
+// TokenExpiredError is returned when the server rejects a request because the authentication
+// token has expired, the driver has to be recreated with a new token. It can also be inspected
+// as a *Neo4jError with errors.As.
+type TokenExpiredError struct {
+	Neo4jError
+}
+
+// UsageError is returned when the driver is used incorrectly, e.g. when a closed session is
+// used or a parameter can not be sent to the server.
+type UsageError struct {
+	message string
+}
+
+// SessionExpiredError is returned when a session no longer satisfies the criteria under which
+// it was acquired, e.g. the server no longer accepts writes. The error reported by the server,
+// if any, can be inspected with errors.As.
+type SessionExpiredError struct {
+	message string
+	cause   error
+}
+
 type connectorError struct {
+	target      string
 	state       int
 	code        int
 	codeText    string
@@ -40,34 +78,90 @@ type connectorError struct {
 	description string
 }
 
-type driverError struct {
-	message string
+// ConnectivityFailure tells why the driver was unable to connect to the server.
+type ConnectivityFailure int
+
+const (
+	// ConnectivityFailureOther is any failure not covered by the other kinds, e.g. the
+	// server closing the connection unexpectedly.
+	ConnectivityFailureOther ConnectivityFailure = iota
+	// ConnectivityFailureAddress means the address could not be resolved or no server
+	// accepted connections on it in time.
+	ConnectivityFailureAddress
+	// ConnectivityFailureTLS means a secure connection could not be established, e.g.
+	// because the server certificate is not trusted.
+	ConnectivityFailureTLS
+	// ConnectivityFailureAuthentication means the server rejected the authentication token.
+	ConnectivityFailureAuthentication
+	// ConnectivityFailureProtocol means the server does not speak a protocol version that
+	// is supported by the driver, e.g. because the address points to the HTTP port.
+	ConnectivityFailureProtocol
+)
+
+// ConnectivityError is returned by Driver.VerifyConnectivity when the server can not be
+// reached. Errors of other operations that failed to reach the server can be inspected as a
+// *ConnectivityError with errors.As.
+type ConnectivityError struct {
+	// Target is the host of the URL the driver was created for.
+	Target string
+	// Failure tells why the server could not be reached.
+	Failure ConnectivityFailure
+	cause   error
 }
 
-type sessionExpiredError struct {
-	message string
+// RetryExhaustedError is returned by a transaction function that was attempted more than
+// once without success, or that failed and was stopped from retrying by its context. The errors
+// of the attempts can be inspected with errors.Is and errors.As.
+type RetryExhaustedError struct {
+	// Attempts is the number of times the transaction function was attempted.
+	Attempts int
+	// Elapsed is the time from the start of the first attempt until the last one failed.
+	Elapsed time.Duration
+	// Errors holds the errors the attempts failed with, the last one is that of the final
+	// attempt, or the error of the context when it was done while waiting for the next attempt.
+	Errors       []error
+	connectionId string
 }
 
-func (failure *databaseError) BoltError() bool {
+func (failure *Neo4jError) BoltError() bool {
 	return true
 }
 
-func (failure *databaseError) Classification() string {
+// Code returns the status code reported by the server.
+func (failure *Neo4jError) Code() string {
+	return failure.code
+}
+
+// Classification returns the classification part of the status code, e.g. ClientError,
+// TransientError or DatabaseError.
+func (failure *Neo4jError) Classification() string {
 	return failure.classification
 }
 
-func (failure *databaseError) Code() string {
-	return failure.code
+// Category returns the category part of the status code, e.g. Schema or Transaction.
+func (failure *Neo4jError) Category() string {
+	return failure.category
 }
 
-func (failure *databaseError) Message() string {
+// Title returns the title part of the status code, e.g. ConstraintValidationFailed.
+func (failure *Neo4jError) Title() string {
+	return failure.title
+}
+
+// Message returns the message reported by the server.
+func (failure *Neo4jError) Message() string {
 	return failure.message
 }
 
-func (failure *databaseError) Error() string {
+func (failure *Neo4jError) Error() string {
 	return fmt.Sprintf("database returned error [%s]: %s", failure.code, failure.message)
 }
 
+// Unwrap returns the error as reported by the server.
+func (failure *TokenExpiredError) Unwrap() error {
+	return &failure.Neo4jError
+}
+
 func (failure *connectorError) BoltError() bool {
 	return true
 }
@@ -88,6 +182,17 @@ func (failure *connectorError) Description() string {
 	return failure.description
 }
 
+// As lets errors caused by the server being unreachable be inspected as a *ConnectivityError.
+func (failure *connectorError) As(target interface{}) bool {
+	connectivityErr, ok := target.(**ConnectivityError)
+	if !ok || !isConnectivityFailure(failure) {
+		return false
+	}
+
+	*connectivityErr = newConnectivityError(failure.target, failure)
+	return true
+}
+
 func (failure *connectorError) Error() string {
 	if failure.description != "" {
 		return fmt.Sprintf("%s: error: [%d] %s, state: %d, context: %s", failure.description, failure.code, failure.codeText, failure.state, failure.context)
@@ -96,82 +201,228 @@ func (failure *connectorError) Error() string {
 	}
 }
 
-func (failure *driverError) BoltError() bool {
+func (failure *UsageError) BoltError() bool {
 	return true
 }
 
-func (failure *driverError) Message() string {
+func (failure *UsageError) Message() string {
 	return failure.message
 }
 
-func (failure *driverError) Error() string {
+func (failure *UsageError) Error() string {
 	return failure.message
 }
 
-func (failure *sessionExpiredError) BoltError() bool {
+func (failure *ConnectivityError) BoltError() bool {
+	return true
+}
+
+// Unwrap returns the error that caused the verification to fail.
+func (failure *ConnectivityError) Unwrap() error {
+	return failure.cause
+}
+
+func (failure *ConnectivityError) Error() string {
+	return fmt.Sprintf("unable to connect to %s: %v", failure.Target, failure.cause)
+}
+
+func (failure *RetryExhaustedError) BoltError() bool {
 	return true
 }
 
-func (failure *sessionExpiredError) Error() string {
+// Unwrap returns the error of the final attempt.
+func (failure *RetryExhaustedError) Unwrap() error {
+	return failure.Errors[len(failure.Errors)-1]
+}
+
+// Is reports whether any of the attempts failed with an error matching target.
+func (failure *RetryExhaustedError) Is(target error) bool {
+	for _, err := range failure.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first error of the attempts that matches target and sets target to it.
+func (failure *RetryExhaustedError) As(target interface{}) bool {
+	for _, err := range failure.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (failure *RetryExhaustedError) Error() string {
+	messages := make([]string, len(failure.Errors))
+	for i, err := range failure.Errors {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("[%s]: retryable operation failed to complete after %d tries in %v, suppressed errors: [%s]",
+		failure.connectionId, failure.Attempts, failure.Elapsed, strings.Join(messages, ", "))
+}
+
+func (failure *SessionExpiredError) BoltError() bool {
+	return true
+}
+
+// Unwrap returns the error reported by the server that caused the session to expire, if any.
+func (failure *SessionExpiredError) Unwrap() error {
+	return failure.cause
+}
+
+func (failure *SessionExpiredError) Error() string {
 	return failure.message
 }
 
-func newDriverError(format string, args ...interface{}) gobolt.GenericError {
-	return &driverError{message: fmt.Sprintf(format, args...)}
+func newDriverError(format string, args ...interface{}) bolt.GenericError {
+	return &UsageError{message: fmt.Sprintf(format, args...)}
+}
+
+func newSessionExpiredError(cause error, format string, args ...interface{}) error {
+	return &SessionExpiredError{message: fmt.Sprintf(format, args...), cause: cause}
+}
+
+func newDatabaseError(classification, code, message string) bolt.DatabaseError {
+	failure := Neo4jError{code: code, classification: classification, message: message}
+	failure.category, failure.title = parseStatusCode(code)
+
+	if code == StatusTokenExpired {
+		return &TokenExpiredError{Neo4jError: failure}
+	}
+
+	return &failure
 }
 
-func newSessionExpiredError(format string, args ...interface{}) error {
-	return &sessionExpiredError{message: fmt.Sprintf(format, args...)}
+// parseStatusCode returns the category and title of a status code of the form
+// Neo.<Classification>.<Category>.<Title>, they are empty if the code has another form
+func parseStatusCode(code string) (category, title string) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 4 || parts[0] != "Neo" {
+		return "", ""
+	}
+
+	return parts[2], parts[3]
 }
 
-func newDatabaseError(classification, code, message string) gobolt.DatabaseError {
-	return &databaseError{code: code, message: message, classification: classification}
+// hasStatusCode checks whether the error was reported by the server with one of the given
+// status codes
+func hasStatusCode(err error, codes ...string) bool {
+	var neo4jErr *Neo4jError
+	if !errors.As(err, &neo4jErr) {
+		return false
+	}
+
+	for _, code := range codes {
+		if neo4jErr.code == code {
+			return true
+		}
+	}
+	return false
 }
 
-func newConnectorError(state int, code int, codeText, context, description string) gobolt.ConnectorError {
+func newConnectorError(state int, code int, codeText, context, description string) bolt.ConnectorError {
 	return &connectorError{state: state, code: code, codeText: codeText, context: context, description: description}
 }
 
+// connectorErrorFactory returns a factory of connector errors which are reported as
+// connectivity errors of the given target
+func connectorErrorFactory(target string) bolt.ConnectorErrorFactory {
+	return func(state int, code int, codeText, context, description string) bolt.ConnectorError {
+		return &connectorError{target: target, state: state, code: code, codeText: codeText, context: context, description: description}
+	}
+}
+
+// isConnectivityFailure checks whether the connector error was caused by the server being
+// unreachable, rejecting the credentials or not speaking a supported protocol version
+func isConnectivityFailure(failure *connectorError) bool {
+	switch failure.code {
+	case bolt.ErrorPermissionDenied:
+		return true
+	case bolt.ErrorProtocolUnsupported:
+		return failure.state == bolt.StateDefunct
+	}
+
+	return bolt.IsServiceUnavailable(failure)
+}
+
+func newConnectivityError(target string, cause error) *ConnectivityError {
+	failure := ConnectivityFailureOther
+	var connErr bolt.ConnectorError
+	if errors.As(cause, &connErr) {
+		switch connErr.Code() {
+		case bolt.ErrorAddressNotResolved, bolt.ErrorNoValidAddress, bolt.ErrorConnectionRefused, bolt.ErrorNetworkUnreachable, bolt.ErrorTimedOut:
+			failure = ConnectivityFailureAddress
+		case bolt.ErrorTLS:
+			failure = ConnectivityFailureTLS
+		case bolt.ErrorPermissionDenied:
+			failure = ConnectivityFailureAuthentication
+		case bolt.ErrorProtocolUnsupported:
+			failure = ConnectivityFailureProtocol
+		}
+	} else if bolt.IsAuthenticationError(cause) {
+		failure = ConnectivityFailureAuthentication
+	}
+
+	return &ConnectivityError{Target: target, Failure: failure, cause: cause}
+}
+
 func isRetriableError(err error) bool {
-	return gobolt.IsServiceUnavailable(err) || gobolt.IsTransientError(err) || gobolt.IsWriteError(err)
+	return bolt.IsServiceUnavailable(err) || bolt.IsTransientError(err) || bolt.IsWriteError(err)
 }
 
 // IsSecurityError is a utility method to check if the provided error is related with any
 // TLS failure or authentication issues.
 func IsSecurityError(err error) bool {
-	return gobolt.IsSecurityError(err)
+	return bolt.IsSecurityError(err)
 }
 
 // IsAuthenticationError is a utility method to check if the provided error is related with any
 // authentication issues.
 func IsAuthenticationError(err error) bool {
-	return gobolt.IsAuthenticationError(err)
+	return bolt.IsAuthenticationError(err)
 }
 
 // IsClientError is a utility method to check if the provided error is related with the client
 // carrying out an invalid operation.
 func IsClientError(err error) bool {
-	return gobolt.IsClientError(err)
+	return bolt.IsClientError(err)
 }
 
 // IsTransientError is a utility method to check if the provided error is related with a temporary
 // failure that may be worked around by retrying.
 func IsTransientError(err error) bool {
-	return gobolt.IsTransientError(err)
+	return bolt.IsTransientError(err)
 }
 
 // IsSessionExpired is a utility method to check if the session no longer satisfy the criteria
 // under which it was acquired, e.g. a server no longer accepts write requests.
 func IsSessionExpired(err error) bool {
-	if _, ok := err.(*sessionExpiredError); ok {
+	var sessionExpiredErr *SessionExpiredError
+	if errors.As(err, &sessionExpiredErr) {
 		return true
 	}
 
-	return gobolt.IsSessionExpired(err)
+	return bolt.IsSessionExpired(err)
 }
 
 // IsServiceUnavailable is a utility method to check if the provided error can be classified
 // to be in service unavailable category.
 func IsServiceUnavailable(err error) bool {
-	return gobolt.IsServiceUnavailable(err)
+	return bolt.IsServiceUnavailable(err)
+}
+
+// IsConstraintViolation is a utility method to check if the provided error was reported by the
+// server because a statement violated a constraint, e.g. a uniqueness constraint.
+func IsConstraintViolation(err error) bool {
+	return hasStatusCode(err, StatusConstraintValidationFailed)
+}
+
+// IsDeadlock is a utility method to check if the provided error was reported by the server
+// because the transaction was involved in a deadlock, the transaction can be retried.
+func IsDeadlock(err error) bool {
+	return hasStatusCode(err, StatusDeadlockDetected)
 }
@@ -20,6 +20,9 @@
 package neo4j
 
 import (
+	goerrors "errors"
+	"fmt"
+
 	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo"
@@ -241,8 +244,8 @@ var _ = Describe("Error", func() {
 	})
 
 	Context("IsSessionExpired", func() {
-		When("provided with a sessionExpiredError", func() {
-			err := &sessionExpiredError{message: "error"}
+		When("provided with a SessionExpiredError", func() {
+			err := &SessionExpiredError{message: "error"}
 
 			It("should return true", func() {
 				Expect(IsSessionExpired(err)).To(BeTrue())
@@ -370,4 +373,147 @@ var _ = Describe("Error", func() {
 			})
 		})
 	})
+
+	Context("IsConstraintViolation", func() {
+		When("provided with a DatabaseError with Neo.ClientError.Schema.ConstraintValidationFailed code", func() {
+			err := newDatabaseError("ClientError", StatusConstraintValidationFailed, "already exists")
+
+			It("should return true", func() {
+				Expect(IsConstraintViolation(err)).To(BeTrue())
+			})
+
+			It("should return true when wrapped", func() {
+				Expect(IsConstraintViolation(&RetryExhaustedError{Attempts: 2, Errors: []error{newDriverError("some error"), err}})).To(BeTrue())
+			})
+		})
+
+		When("provided with a DatabaseError with random code", func() {
+			err := newDatabaseError("ClientError", "Neo.ClientError.Statement.SyntaxError", "syntax error")
+
+			It("should return false", func() {
+				Expect(IsConstraintViolation(err)).To(BeFalse())
+			})
+		})
+
+		When("provided with another error type", func() {
+			err := errors.New("Neo.ClientError.Schema.ConstraintValidationFailed")
+
+			It("should return false", func() {
+				Expect(IsConstraintViolation(err)).To(BeFalse())
+			})
+		})
+	})
+
+	Context("IsDeadlock", func() {
+		When("provided with a DatabaseError with Neo.TransientError.Transaction.DeadlockDetected code", func() {
+			err := newDatabaseError("TransientError", StatusDeadlockDetected, "deadlock")
+
+			It("should return true", func() {
+				Expect(IsDeadlock(err)).To(BeTrue())
+				Expect(IsTransientError(err)).To(BeTrue())
+			})
+		})
+
+		When("provided with a DatabaseError with random code", func() {
+			err := newDatabaseError("TransientError", "Neo.TransientError.Transaction.Terminated", "terminated")
+
+			It("should return false", func() {
+				Expect(IsDeadlock(err)).To(BeFalse())
+			})
+		})
+
+		When("provided with a ConnectorError", func() {
+			err := newConnectorError(4, 4, "BOLT_CONNECTION_RESET", "some context", "some description")
+
+			It("should return false", func() {
+				Expect(IsDeadlock(err)).To(BeFalse())
+			})
+		})
+	})
+
+	Context("Error types", func() {
+		It("should expose the parts of the status code of a Neo4jError", func() {
+			var neo4jErr *Neo4jError
+			err := fmt.Errorf("wrapped: %w", newDatabaseError("ClientError", "Neo.ClientError.Schema.ConstraintValidationFailed", "already exists"))
+
+			Expect(goerrors.As(err, &neo4jErr)).To(BeTrue())
+			Expect(neo4jErr.Code()).To(Equal("Neo.ClientError.Schema.ConstraintValidationFailed"))
+			Expect(neo4jErr.Classification()).To(Equal("ClientError"))
+			Expect(neo4jErr.Category()).To(Equal("Schema"))
+			Expect(neo4jErr.Title()).To(Equal("ConstraintValidationFailed"))
+			Expect(neo4jErr.Message()).To(Equal("already exists"))
+		})
+
+		It("should leave the category and title of a malformed status code empty", func() {
+			var neo4jErr *Neo4jError
+			err := newDatabaseError("", "Some.Error", "some error")
+
+			Expect(goerrors.As(err, &neo4jErr)).To(BeTrue())
+			Expect(neo4jErr.Category()).To(BeEmpty())
+			Expect(neo4jErr.Title()).To(BeEmpty())
+		})
+
+		It("should return a TokenExpiredError that unwraps to a Neo4jError", func() {
+			var tokenExpiredErr *TokenExpiredError
+			var neo4jErr *Neo4jError
+			err := newDatabaseError("ClientError", "Neo.ClientError.Security.TokenExpired", "token expired")
+
+			Expect(goerrors.As(err, &tokenExpiredErr)).To(BeTrue())
+			Expect(goerrors.As(err, &neo4jErr)).To(BeTrue())
+			Expect(neo4jErr.Code()).To(Equal("Neo.ClientError.Security.TokenExpired"))
+			Expect(IsSecurityError(err)).To(BeTrue())
+		})
+
+		It("should return a UsageError for driver errors", func() {
+			var usageErr *UsageError
+
+			Expect(goerrors.As(newDriverError("session is already closed"), &usageErr)).To(BeTrue())
+			Expect(usageErr.Message()).To(Equal("session is already closed"))
+		})
+
+		It("should unwrap a SessionExpiredError to its cause", func() {
+			var sessionExpiredErr *SessionExpiredError
+			var neo4jErr *Neo4jError
+			cause := newDatabaseError("ClientError", "Neo.ClientError.Cluster.NotALeader", "not a leader")
+			err := newSessionExpiredError(cause, "server at %s no longer accepts writes", "localhost:7687")
+
+			Expect(goerrors.As(err, &sessionExpiredErr)).To(BeTrue())
+			Expect(goerrors.As(err, &neo4jErr)).To(BeTrue())
+			Expect(goerrors.Is(err, cause)).To(BeTrue())
+			Expect(IsSessionExpired(fmt.Errorf("wrapped: %w", err))).To(BeTrue())
+		})
+
+		DescribeTable("should let connector errors be inspected as a ConnectivityError",
+			func(state, code int, expected bool, failure ConnectivityFailure) {
+				var connectivityErr *ConnectivityError
+				err := connectorErrorFactory("localhost:7687")(state, code, "some text", "some context", "some description")
+
+				Expect(goerrors.As(err, &connectivityErr)).To(Equal(expected))
+				if expected {
+					Expect(connectivityErr.Target).To(Equal("localhost:7687"))
+					Expect(connectivityErr.Failure).To(Equal(failure))
+					Expect(connectivityErr.Unwrap()).To(BeIdenticalTo(err))
+				}
+			},
+			Entry("BOLT_CONNECTION_REFUSED", 0, 11, true, ConnectivityFailureAddress),
+			Entry("BOLT_END_OF_TRANSMISSION", 4, 15, true, ConnectivityFailureOther),
+			Entry("BOLT_TLS_ERROR", 4, 13, true, ConnectivityFailureTLS),
+			Entry("BOLT_PERMISSION_DENIED", 4, 7, true, ConnectivityFailureAuthentication),
+			Entry("BOLT_PROTOCOL_UNSUPPORTED during the handshake", 4, 0x504, true, ConnectivityFailureProtocol),
+			Entry("BOLT_PROTOCOL_UNSUPPORTED of a feature", 2, 0x504, false, ConnectivityFailureOther),
+			Entry("BOLT_POOL_ACQUISITION_TIMED_OUT", 0, 0x601, false, ConnectivityFailureOther),
+		)
+
+		It("should find typed errors through a RetryExhaustedError", func() {
+			var neo4jErr *Neo4jError
+			var connectivityErr *ConnectivityError
+			transient := newDatabaseError("TransientError", "Neo.TransientError.Transaction.DeadlockDetected", "deadlock")
+			err := &RetryExhaustedError{Attempts: 2, Errors: []error{connectorErrorFactory("localhost:7687")(4, 4, "", "", ""), transient}}
+
+			Expect(goerrors.As(err, &neo4jErr)).To(BeTrue())
+			Expect(neo4jErr).To(BeIdenticalTo(transient))
+			Expect(goerrors.As(err, &connectivityErr)).To(BeTrue())
+			Expect(goerrors.Is(err, transient)).To(BeTrue())
+		})
+	})
 })
@@ -0,0 +1,79 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"crypto/x509"
+	"net/url"
+	"time"
+)
+
+const defaultUserAgent = "neo4j-go/1.8"
+
+// URLAddressResolver resolves the initial router address of a routing connector into
+// one or more addresses
+type URLAddressResolver func(address *url.URL) []*url.URL
+
+// ServerLoad describes a server that is a candidate for a connection
+type ServerLoad struct {
+	Address string
+	// InUse is the number of connections to the server that are currently handed out
+	InUse int
+	// Latency is the average time it took to establish connections to the server, it is 0
+	// as long as no connection was established
+	Latency time.Duration
+}
+
+// LoadBalancer picks one of the candidate servers for a connection of the given access mode
+// and returns its index, it is called with the routing table locked and must not block.
+// Routing connectors require one.
+type LoadBalancer func(mode AccessMode, servers []ServerLoad) int
+
+// Config holds the settings that are used to create a connector
+type Config struct {
+	Encryption               bool
+	TLSCertificates          []*x509.Certificate
+	TLSSkipVerify            bool
+	TLSSkipVerifyHostname    bool
+	UserAgent                string
+	Log                      Logger
+	BoltLogger               BoltLogger
+	AddressResolver          URLAddressResolver
+	LoadBalancer             LoadBalancer
+	MaxPoolSize              int
+	MaxConnLifetime          time.Duration
+	MaxConnIdleTime          time.Duration
+	MinIdleConns             int
+	ConnLivenessCheckTimeout time.Duration
+	ConnAcquisitionTimeout   time.Duration
+	SockConnectTimeout       time.Duration
+	SockKeepalive            bool
+	ValueHandlers            []ValueHandler
+	GenericErrorFactory      GenericErrorFactory
+	ConnectorErrorFactory    ConnectorErrorFactory
+	DatabaseErrorFactory     DatabaseErrorFactory
+}
+
+func (config *Config) userAgent() string {
+	if config.UserAgent == "" {
+		return defaultUserAgent
+	}
+	return config.UserAgent
+}
@@ -0,0 +1,251 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	handshakeMagic    = []byte{0x60, 0x60, 0xB0, 0x17}
+	handshakeVersions = []uint32{4, 3, 2, 1}
+
+	connectionCounter int64
+)
+
+// dialer opens sockets to servers, it is replaced in tests
+type dialer func(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error)
+
+func dialTCP(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error) {
+	d := net.Dialer{Timeout: timeout}
+	if !keepAlive {
+		d.KeepAlive = -1
+	}
+	return d.Dial(network, address)
+}
+
+// connect opens a new connection to the given address, negotiates a protocol version
+// and authenticates with the provided token
+func connect(dial dialer, address string, mode AccessMode, authToken map[string]interface{}, config *Config, values *valueSystem) (*boltConnection, error) {
+	id := fmt.Sprintf("conn-%d", atomic.AddInt64(&connectionCounter, 1))
+
+	config.debug("opening connection", connectionField(id), addressField(address))
+	conn, err := dial("tcp", address, config.SockConnectTimeout, config.SockKeepalive)
+	if err != nil {
+		return nil, config.newConnectorError(StateDisconnected, errorCodeOf(err), err.Error(), fmt.Sprintf("unable to connect to %s", address))
+	}
+
+	if config.Encryption {
+		if conn, err = secure(conn, address, config); err != nil {
+			return nil, err
+		}
+	}
+
+	connection := &boltConnection{
+		id:        id,
+		address:   address,
+		mode:      mode,
+		config:    config,
+		values:    values,
+		createdAt: time.Now(),
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		state:     StateConnected,
+	}
+
+	// a server that accepts the socket but never answers must not hold up the connection
+	// for longer than it may take to be established
+	if config.SockConnectTimeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(config.SockConnectTimeout))
+	}
+
+	if err = connection.handshake(); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if err = connection.authenticate(authToken); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	config.debug("connected", connectionField(id), addressField(address), LogField{Key: "protocol_version", Value: connection.version})
+	return connection, nil
+}
+
+// secure wraps the socket into a TLS session and verifies the server certificate
+// according to the configured trust settings
+func secure(conn net.Conn, address string, config *Config) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+
+	tlsConfig := &tls.Config{ServerName: host}
+	if len(config.TLSCertificates) > 0 {
+		tlsConfig.RootCAs = x509.NewCertPool()
+		for _, certificate := range config.TLSCertificates {
+			tlsConfig.RootCAs.AddCert(certificate)
+		}
+	}
+
+	if config.TLSSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	} else if config.TLSSkipVerifyHostname {
+		// the chain is still verified, only the host name check is left out
+		roots := tlsConfig.RootCAs
+		tlsConfig.InsecureSkipVerify = true
+		tlsConfig.VerifyPeerCertificate = func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			return verifyChain(rawCerts, roots)
+		}
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if config.SockConnectTimeout > 0 {
+		_ = tlsConn.SetDeadline(time.Now().Add(config.SockConnectTimeout))
+	}
+	if err := tlsConn.Handshake(); err != nil {
+		_ = conn.Close()
+
+		code := errorCodeOf(err)
+		if code == ErrorUnknown {
+			code = ErrorTLS
+		}
+		return nil, config.newConnectorError(StateDefunct, code, err.Error(), fmt.Sprintf("unable to establish a secure connection to %s", address))
+	}
+	_ = tlsConn.SetDeadline(time.Time{})
+
+	return tlsConn, nil
+}
+
+func verifyChain(rawCerts [][]byte, roots *x509.CertPool) error {
+	if len(rawCerts) == 0 {
+		return x509.CertificateInvalidError{Reason: x509.NotAuthorizedToSign}
+	}
+
+	certificates := make([]*x509.Certificate, len(rawCerts))
+	for i, raw := range rawCerts {
+		certificate, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certificates[i] = certificate
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+	return err
+}
+
+func (connection *boltConnection) handshake() error {
+	request := make([]byte, 0, 20)
+	request = append(request, handshakeMagic...)
+	for _, version := range handshakeVersions {
+		request = append(request, byte(version>>24), byte(version>>16), byte(version>>8), byte(version))
+	}
+
+	if _, err := connection.conn.Write(request); err != nil {
+		return connection.markDefunct(err, "unable to send handshake")
+	}
+
+	response := make([]byte, 4)
+	if _, err := io.ReadFull(connection.reader, response); err != nil {
+		return connection.markDefunct(err, "unable to read handshake response")
+	}
+
+	if bytes.Equal(response, []byte("HTTP")) {
+		connection.state = StateDefunct
+		return connection.config.newConnectorError(StateDefunct, ErrorProtocolUnsupported, "server responded with HTTP", "make sure you are not trying to connect to the HTTP port")
+	}
+
+	version := binary.BigEndian.Uint32(response)
+	for _, proposed := range handshakeVersions {
+		if proposed != 0 && proposed == version {
+			connection.version = int(version)
+			connection.packer = connection.values.newPacker(&connection.message, connection.version >= 2)
+			return nil
+		}
+	}
+
+	connection.state = StateDefunct
+	return connection.config.newConnectorError(StateDefunct, ErrorProtocolUnsupported, fmt.Sprintf("server selected protocol version %d", version), "no supported protocol version could be agreed upon")
+}
+
+// authenticate sends INIT (protocol versions 1 and 2) or HELLO (version 3 onwards) and
+// waits for the server to accept the credentials
+func (connection *boltConnection) authenticate(authToken map[string]interface{}) error {
+	var err error
+	if connection.version < 3 {
+		err = connection.queueMessage(msgHello, connection.config.userAgent(), authToken)
+	} else {
+		hello := map[string]interface{}{"user_agent": connection.config.userAgent()}
+		for key, value := range authToken {
+			hello[key] = value
+		}
+		err = connection.queueMessage(msgHello, hello)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = connection.Flush(); err != nil {
+		return err
+	}
+
+	signature, fields, err := connection.receive()
+	if err != nil {
+		return err
+	}
+
+	switch signature {
+	case msgSuccess:
+		metadata := fields[0].(map[string]interface{})
+		connection.server, _ = metadata["server"].(string)
+		connection.serverId, _ = metadata["connection_id"].(string)
+		connection.state = StateReady
+		return nil
+	case msgFailure:
+		connection.onFailure(fields[0])
+		connection.state = StateDefunct
+
+		if dbErr, ok := connection.failure.(DatabaseError); ok && strings.HasPrefix(dbErr.Code(), "Neo.ClientError.Security.") {
+			return connection.config.newConnectorError(StateDefunct, ErrorPermissionDenied, dbErr.Message(), "authentication failed")
+		}
+		return connection.failure
+	}
+
+	connection.state = StateDefunct
+	return connection.config.newConnectorError(StateDefunct, ErrorProtocolViolation, "unexpected response to authentication", "unexpected message received")
+}
@@ -0,0 +1,809 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/packstream"
+)
+
+// RequestHandle identifies a request queued on a connection
+type RequestHandle int64
+
+// FetchType identifies the kind of response that was fetched for a request
+type FetchType int
+
+const (
+	// FetchTypeError is returned when a request failed or was ignored by the server
+	FetchTypeError FetchType = -1
+	// FetchTypeMetadata is returned when the summary of a request was received
+	FetchTypeMetadata FetchType = 0
+	// FetchTypeRecord is returned when a record of a request was received
+	FetchTypeRecord FetchType = 1
+)
+
+// Connection represents a single connection to a server, requests are queued on the
+// connection, sent over with Flush and their responses are consumed through Fetch
+type Connection interface {
+	Id() (string, error)
+	RemoteAddress() (string, error)
+	Server() (string, error)
+	ProtocolVersion() (int, error)
+	ServerConnectionId() (string, error)
+	// SetBoltLogger sets the logger that receives the messages of the connection until it
+	// is released, nil restores the one of the connector
+	SetBoltLogger(logger BoltLogger)
+
+	Begin(bookmarks []string, txTimeout time.Duration, txMetadata map[string]interface{}) (RequestHandle, error)
+	Commit() (RequestHandle, error)
+	Rollback() (RequestHandle, error)
+	Run(cypher string, params map[string]interface{}, bookmarks []string, txTimeout time.Duration, txMetadata map[string]interface{}) (RequestHandle, error)
+	PullAll() (RequestHandle, error)
+	DiscardAll() (RequestHandle, error)
+	Pull(n int, qid int64) (RequestHandle, error)
+	Discard(n int, qid int64) (RequestHandle, error)
+	Reset() (RequestHandle, error)
+	Flush() error
+	Fetch(request RequestHandle) (FetchType, error)
+	FetchContext(ctx context.Context, request RequestHandle) (FetchType, error)
+	FetchSummary(request RequestHandle) (int, error)
+
+	LastBookmark() (string, error)
+	Fields() ([]string, error)
+	Metadata() (map[string]interface{}, error)
+	Data() ([]interface{}, error)
+
+	Close() error
+}
+
+const (
+	msgHello      byte = 0x01
+	msgGoodbye    byte = 0x02
+	msgAckFailure byte = 0x0E
+	msgReset      byte = 0x0F
+	msgRun        byte = 0x10
+	msgBegin      byte = 0x11
+	msgCommit     byte = 0x12
+	msgRollback   byte = 0x13
+	msgDiscardAll byte = 0x2F
+	msgPullAll    byte = 0x3F
+	msgSuccess    byte = 0x70
+	msgRecord     byte = 0x71
+	msgIgnored    byte = 0x7E
+	msgFailure    byte = 0x7F
+)
+
+var messageNames = map[byte]string{
+	msgHello:      "HELLO",
+	msgGoodbye:    "GOODBYE",
+	msgAckFailure: "ACK_FAILURE",
+	msgReset:      "RESET",
+	msgRun:        "RUN",
+	msgBegin:      "BEGIN",
+	msgCommit:     "COMMIT",
+	msgRollback:   "ROLLBACK",
+	msgDiscardAll: "DISCARD_ALL",
+	msgPullAll:    "PULL_ALL",
+	msgSuccess:    "SUCCESS",
+	msgRecord:     "RECORD",
+	msgIgnored:    "IGNORED",
+	msgFailure:    "FAILURE",
+}
+
+const maxChunkSize = 0xFFFF
+
+// resetMessage is a RESET that is already chunked, it is written to the socket directly when
+// a fetch is interrupted
+var resetMessage = []byte{0x00, 0x02, 0xB0, msgReset, 0x00, 0x00}
+
+// request tracks a message that has been queued on the connection and whose response
+// has not yet been consumed
+type request struct {
+	handle    RequestHandle
+	signature byte
+	// failure is set on requests that are resolved locally without being sent
+	failure error
+}
+
+type boltConnection struct {
+	id        string
+	address   string
+	server    string
+	serverId  string
+	version   int
+	mode      AccessMode
+	database  string
+	config    *Config
+	values    *valueSystem
+	pool      *pool
+	createdAt time.Time
+	idleSince time.Time
+
+	// boltLogger overrides the bolt logger of the connector while the connection is in use
+	boltLogger BoltLogger
+
+	conn    net.Conn
+	reader  *bufio.Reader
+	packer  *packstream.Packer
+	message bytes.Buffer
+	out     []byte
+	in      []byte
+
+	state      int
+	err        error
+	failure    error
+	inTx       bool
+	nextHandle RequestHandle
+	pending    []*request
+
+	fields   []string
+	metadata map[string]interface{}
+	data     []interface{}
+	bookmark string
+}
+
+func (connection *boltConnection) Id() (string, error) {
+	return connection.id, nil
+}
+
+func (connection *boltConnection) RemoteAddress() (string, error) {
+	return connection.address, nil
+}
+
+func (connection *boltConnection) Server() (string, error) {
+	return connection.server, nil
+}
+
+func (connection *boltConnection) ProtocolVersion() (int, error) {
+	return connection.version, nil
+}
+
+func (connection *boltConnection) ServerConnectionId() (string, error) {
+	return connection.serverId, nil
+}
+
+func (connection *boltConnection) Begin(bookmarks []string, txTimeout time.Duration, txMetadata map[string]interface{}) (RequestHandle, error) {
+	if err := connection.assertDatabaseSupported(); err != nil {
+		return -1, err
+	}
+
+	if connection.version < 3 {
+		if err := connection.assertTransactionConfigSupported(txTimeout, txMetadata); err != nil {
+			return -1, err
+		}
+
+		params := map[string]interface{}{}
+		if len(bookmarks) > 0 {
+			params["bookmark"] = bookmarks[len(bookmarks)-1]
+			params["bookmarks"] = bookmarks
+		}
+
+		if _, err := connection.queueRequest(msgRun, "BEGIN", params); err != nil {
+			return -1, err
+		}
+		handle, err := connection.queueRequest(msgDiscardAll)
+		connection.inTx = err == nil
+		return handle, err
+	}
+
+	handle, err := connection.queueRequest(msgBegin, connection.transactionMetadata(bookmarks, txTimeout, txMetadata))
+	connection.inTx = err == nil
+	return handle, err
+}
+
+func (connection *boltConnection) Commit() (RequestHandle, error) {
+	defer func() { connection.inTx = false }()
+
+	if connection.state == StateFailed {
+		return connection.queueFailedRequest(msgCommit), nil
+	}
+
+	if connection.version < 3 {
+		if _, err := connection.queueRequest(msgRun, "COMMIT", map[string]interface{}{}); err != nil {
+			return -1, err
+		}
+		return connection.queueRequest(msgDiscardAll)
+	}
+
+	return connection.queueRequest(msgCommit)
+}
+
+func (connection *boltConnection) Rollback() (RequestHandle, error) {
+	defer func() { connection.inTx = false }()
+
+	// a RESET rolls back any open transaction and brings a failed connection back to a
+	// usable state
+	if connection.state == StateFailed {
+		return connection.queueRequest(msgReset)
+	}
+
+	if connection.version < 3 {
+		if _, err := connection.queueRequest(msgRun, "ROLLBACK", map[string]interface{}{}); err != nil {
+			return -1, err
+		}
+		return connection.queueRequest(msgDiscardAll)
+	}
+
+	return connection.queueRequest(msgRollback)
+}
+
+func (connection *boltConnection) Run(cypher string, params map[string]interface{}, bookmarks []string, txTimeout time.Duration, txMetadata map[string]interface{}) (RequestHandle, error) {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+
+	if err := connection.assertDatabaseSupported(); err != nil {
+		return -1, err
+	}
+
+	if connection.version < 3 {
+		if err := connection.assertTransactionConfigSupported(txTimeout, txMetadata); err != nil {
+			return -1, err
+		}
+
+		return connection.queueRequest(msgRun, cypher, params)
+	}
+
+	// statements of an explicit transaction inherit its settings
+	if connection.inTx {
+		return connection.queueRequest(msgRun, cypher, params, map[string]interface{}{})
+	}
+
+	return connection.queueRequest(msgRun, cypher, params, connection.transactionMetadata(bookmarks, txTimeout, txMetadata))
+}
+
+func (connection *boltConnection) PullAll() (RequestHandle, error) {
+	return connection.Pull(-1, -1)
+}
+
+func (connection *boltConnection) DiscardAll() (RequestHandle, error) {
+	return connection.Discard(-1, -1)
+}
+
+// Pull asks for the next n records, or all of them when n is -1, of the statement identified
+// by qid or of the last statement when qid is -1. The metadata of the summary has has_more
+// set when records are left. Servers before protocol version 4 always send all records.
+func (connection *boltConnection) Pull(n int, qid int64) (RequestHandle, error) {
+	if connection.version < 4 {
+		return connection.queueRequest(msgPullAll)
+	}
+
+	return connection.queueRequest(msgPullAll, streamMetadata(n, qid))
+}
+
+// Discard skips the next n records, or all of them when n is -1, of the statement identified
+// by qid or of the last statement when qid is -1. Servers before protocol version 4 always
+// skip all records.
+func (connection *boltConnection) Discard(n int, qid int64) (RequestHandle, error) {
+	if connection.version < 4 {
+		return connection.queueRequest(msgDiscardAll)
+	}
+
+	return connection.queueRequest(msgDiscardAll, streamMetadata(n, qid))
+}
+
+func (connection *boltConnection) Reset() (RequestHandle, error) {
+	connection.inTx = false
+	return connection.queueRequest(msgReset)
+}
+
+func (connection *boltConnection) Flush() error {
+	if connection.state == StateDefunct {
+		return connection.err
+	}
+
+	if len(connection.out) == 0 {
+		return nil
+	}
+
+	if _, err := connection.conn.Write(connection.out); err != nil {
+		return connection.markDefunct(err, "unable to send queued messages")
+	}
+	connection.out = connection.out[:0]
+
+	return nil
+}
+
+func (connection *boltConnection) Fetch(handle RequestHandle) (FetchType, error) {
+	for {
+		if len(connection.pending) == 0 || connection.pending[0].handle > handle {
+			return FetchTypeError, connection.config.newGenericError("request %d is not pending on connection %s", handle, connection.id)
+		}
+
+		head := connection.pending[0]
+		if head.failure != nil {
+			connection.pending = connection.pending[1:]
+			if head.handle == handle {
+				return FetchTypeError, head.failure
+			}
+			continue
+		}
+
+		if connection.state == StateDefunct {
+			return FetchTypeError, connection.err
+		}
+
+		if err := connection.Flush(); err != nil {
+			return FetchTypeError, err
+		}
+
+		signature, fields, err := connection.receive()
+		if err != nil {
+			return FetchTypeError, err
+		}
+
+		switch signature {
+		case msgRecord:
+			if head.handle != handle {
+				// records of requests that are not asked for are discarded
+				continue
+			}
+
+			data, err := connection.values.hydrate(fields[0])
+			if err != nil {
+				return FetchTypeError, connection.config.newGenericError("unable to convert record received on connection %s: %v", connection.id, err)
+			}
+			connection.data, _ = data.([]interface{})
+			return FetchTypeRecord, nil
+		case msgSuccess:
+			connection.pending = connection.pending[1:]
+			connection.onSuccess(head, fields[0])
+			if head.handle == handle {
+				return FetchTypeMetadata, nil
+			}
+		case msgFailure:
+			connection.pending = connection.pending[1:]
+			connection.onFailure(fields[0])
+			if head.handle == handle {
+				return FetchTypeError, connection.failure
+			}
+		case msgIgnored:
+			connection.pending = connection.pending[1:]
+			if head.handle == handle {
+				if connection.failure != nil {
+					return FetchTypeError, connection.failure
+				}
+				return FetchTypeError, connection.config.newGenericError("request %d was ignored by the server", handle)
+			}
+		}
+	}
+}
+
+// FetchContext is like Fetch but stops waiting once ctx is done. The server is then asked to
+// terminate the outstanding requests with a RESET, all their responses are consumed so that
+// the connection can be reused, and the error of the context is returned.
+func (connection *boltConnection) FetchContext(ctx context.Context, handle RequestHandle) (FetchType, error) {
+	if ctx.Done() == nil {
+		return connection.Fetch(handle)
+	}
+
+	// nothing may be written to the socket while the fetch can be interrupted
+	if err := connection.Flush(); err != nil {
+		return FetchTypeError, err
+	}
+
+	stop := make(chan struct{})
+	interrupted := make(chan bool, 1)
+	go connection.interruptOnDone(ctx, stop, interrupted)
+
+	fetched, err := connection.Fetch(handle)
+	close(stop)
+	if !<-interrupted {
+		return fetched, err
+	}
+
+	connection.config.debug("connection interrupted", connectionField(connection.id), addressField(connection.address), errorField(ctx.Err()))
+
+	// the server answers the RESET only after all requests that were sent before it
+	reset := connection.newRequest(msgReset)
+	connection.pending = append(connection.pending, reset)
+	connection.inTx = false
+	if _, err = connection.Fetch(reset.handle); err != nil {
+		connection.config.warning("unable to reset connection after interruption", connectionField(connection.id), addressField(connection.address), errorField(err))
+	}
+
+	return FetchTypeError, ctx.Err()
+}
+
+// interruptOnDone writes a RESET to the socket when ctx is done before stop is closed and
+// reports on interrupted whether it did so
+func (connection *boltConnection) interruptOnDone(ctx context.Context, stop <-chan struct{}, interrupted chan<- bool) {
+	select {
+	case <-stop:
+		interrupted <- false
+	case <-ctx.Done():
+		connection.logMessage(true, msgReset, nil)
+		if _, err := connection.conn.Write(resetMessage); err != nil {
+			// the pending read would never complete, make it fail instead
+			_ = connection.conn.SetReadDeadline(time.Now())
+		}
+		interrupted <- true
+	}
+}
+
+func (connection *boltConnection) FetchSummary(handle RequestHandle) (int, error) {
+	records := 0
+	for {
+		fetched, err := connection.Fetch(handle)
+		if err != nil {
+			return records, err
+		}
+
+		if fetched != FetchTypeRecord {
+			return records, nil
+		}
+		records++
+	}
+}
+
+func (connection *boltConnection) LastBookmark() (string, error) {
+	return connection.bookmark, nil
+}
+
+func (connection *boltConnection) Fields() ([]string, error) {
+	return connection.fields, nil
+}
+
+func (connection *boltConnection) Metadata() (map[string]interface{}, error) {
+	return connection.metadata, nil
+}
+
+func (connection *boltConnection) Data() ([]interface{}, error) {
+	return connection.data, nil
+}
+
+func (connection *boltConnection) Close() error {
+	if connection.pool != nil {
+		return connection.pool.release(connection)
+	}
+
+	return connection.destroy()
+}
+
+// assertTransactionConfigSupported fails when a transaction timeout or metadata is passed
+// to a server that predates protocol version 3
+func (connection *boltConnection) assertTransactionConfigSupported(txTimeout time.Duration, txMetadata map[string]interface{}) error {
+	if txTimeout > 0 || len(txMetadata) > 0 {
+		return connection.config.newConnectorError(connection.state, ErrorProtocolUnsupported, fmt.Sprintf("protocol version %d", connection.version), "transaction configuration is not supported by the server")
+	}
+
+	return nil
+}
+
+func streamMetadata(n int, qid int64) map[string]interface{} {
+	metadata := map[string]interface{}{"n": int64(n)}
+	if qid >= 0 {
+		metadata["qid"] = qid
+	}
+
+	return metadata
+}
+
+// assertDatabaseSupported fails when a database is selected on a server that predates
+// protocol version 4
+func (connection *boltConnection) assertDatabaseSupported() error {
+	if connection.database != "" && connection.version < 4 {
+		return connection.config.newConnectorError(connection.state, ErrorProtocolUnsupported, fmt.Sprintf("protocol version %d", connection.version), "database selection is not supported by the server")
+	}
+
+	return nil
+}
+
+func (connection *boltConnection) transactionMetadata(bookmarks []string, txTimeout time.Duration, txMetadata map[string]interface{}) map[string]interface{} {
+	metadata := map[string]interface{}{}
+
+	if len(bookmarks) > 0 {
+		metadata["bookmarks"] = bookmarks
+	}
+
+	if txTimeout > 0 {
+		metadata["tx_timeout"] = int64(txTimeout / time.Millisecond)
+	}
+
+	if len(txMetadata) > 0 {
+		metadata["tx_metadata"] = txMetadata
+	}
+
+	if connection.mode == AccessModeRead {
+		metadata["mode"] = "r"
+	}
+
+	if connection.database != "" {
+		metadata["db"] = connection.database
+	}
+
+	return metadata
+}
+
+// queueRequest packs the message into the outgoing buffer and registers a request for
+// its response
+func (connection *boltConnection) queueRequest(signature byte, fields ...interface{}) (RequestHandle, error) {
+	if connection.state == StateDefunct {
+		return -1, connection.err
+	}
+
+	if connection.state == StateFailed && signature != msgReset {
+		if connection.inTx {
+			return connection.queueFailedRequest(signature), nil
+		}
+
+		// outside of a transaction, the failure is acknowledged and the connection is
+		// reused for the new request
+		ackSignature := msgReset
+		if connection.version < 3 {
+			ackSignature = msgAckFailure
+		}
+		if err := connection.queueMessage(ackSignature); err != nil {
+			return -1, err
+		}
+		connection.state = StateReady
+		connection.pending = append(connection.pending, connection.newRequest(ackSignature))
+	}
+
+	if err := connection.queueMessage(signature, fields...); err != nil {
+		return -1, err
+	}
+
+	request := connection.newRequest(signature)
+	connection.pending = append(connection.pending, request)
+	return request.handle, nil
+}
+
+// queueFailedRequest registers a request that is resolved with the current failure
+// without being sent to the server
+func (connection *boltConnection) queueFailedRequest(signature byte) RequestHandle {
+	request := connection.newRequest(signature)
+	request.failure = connection.failure
+	connection.pending = append(connection.pending, request)
+	return request.handle
+}
+
+func (connection *boltConnection) newRequest(signature byte) *request {
+	connection.nextHandle++
+	return &request{handle: connection.nextHandle, signature: signature}
+}
+
+func (connection *boltConnection) queueMessage(signature byte, fields ...interface{}) error {
+	connection.message.Reset()
+
+	if err := connection.packer.PackStructHeader(len(fields), signature); err != nil {
+		return connection.config.newConnectorError(connection.state, ErrorProtocolUnsupportedType, err.Error(), "unable to generate "+messageDescription(signature))
+	}
+
+	for _, field := range fields {
+		if err := connection.packer.Pack(field); err != nil {
+			return connection.config.newConnectorError(connection.state, ErrorProtocolUnsupportedType, err.Error(), "unable to generate "+messageDescription(signature))
+		}
+	}
+
+	connection.logMessage(true, signature, fields)
+
+	for data := connection.message.Bytes(); len(data) > 0; {
+		size := len(data)
+		if size > maxChunkSize {
+			size = maxChunkSize
+		}
+		connection.out = append(connection.out, byte(size>>8), byte(size))
+		connection.out = append(connection.out, data[:size]...)
+		data = data[size:]
+	}
+	connection.out = append(connection.out, 0x00, 0x00)
+
+	return nil
+}
+
+// receive reads the next message, i.e. a sequence of chunks terminated by an empty chunk
+func (connection *boltConnection) receive() (byte, []interface{}, error) {
+	header := make([]byte, 2)
+	message := connection.in[:0]
+
+	for {
+		if _, err := io.ReadFull(connection.reader, header); err != nil {
+			return 0, nil, connection.markDefunct(err, "unable to read message")
+		}
+
+		size := int(binary.BigEndian.Uint16(header))
+		if size == 0 {
+			if len(message) == 0 {
+				// no-op chunk
+				continue
+			}
+			break
+		}
+
+		start := len(message)
+		message = append(message, make([]byte, size)...)
+		if _, err := io.ReadFull(connection.reader, message[start:]); err != nil {
+			return 0, nil, connection.markDefunct(err, "unable to read message")
+		}
+	}
+	connection.in = message
+
+	unpacker := packstream.NewUnpacker(bytes.NewReader(message))
+	size, signature, err := unpacker.UnpackStructHeader()
+	if err != nil {
+		return 0, nil, connection.markProtocolViolation(err.Error())
+	}
+
+	fields := make([]interface{}, size)
+	for i := range fields {
+		if fields[i], err = unpacker.Unpack(); err != nil {
+			return 0, nil, connection.markProtocolViolation(err.Error())
+		}
+	}
+	connection.logMessage(false, signature, fields)
+
+	switch signature {
+	case msgRecord:
+		if size != 1 {
+			return 0, nil, connection.markProtocolViolation("expected RECORD message to have exactly one field")
+		}
+		if _, ok := fields[0].([]interface{}); !ok {
+			return 0, nil, connection.markProtocolViolation("expected RECORD message to have a list of values")
+		}
+	case msgSuccess, msgFailure:
+		if size != 1 {
+			return 0, nil, connection.markProtocolViolation("expected " + messageDescription(signature) + " to have exactly one field")
+		}
+		if _, ok := fields[0].(map[string]interface{}); !ok {
+			return 0, nil, connection.markProtocolViolation("expected " + messageDescription(signature) + " to have a map of metadata")
+		}
+	case msgIgnored:
+	default:
+		return 0, nil, connection.markProtocolViolation("unexpected response message " + messageDescription(signature))
+	}
+
+	return signature, fields, nil
+}
+
+func (connection *boltConnection) onSuccess(request *request, metadata interface{}) {
+	connection.metadata, _ = metadata.(map[string]interface{})
+
+	if fields, ok := connection.metadata["fields"].([]interface{}); ok {
+		connection.fields = make([]string, len(fields))
+		for i := range fields {
+			connection.fields[i], _ = fields[i].(string)
+		}
+	}
+
+	if bookmark, ok := connection.metadata["bookmark"].(string); ok {
+		connection.bookmark = bookmark
+	}
+
+	switch request.signature {
+	case msgReset, msgAckFailure:
+		connection.failure = nil
+		connection.state = StateReady
+	}
+}
+
+func (connection *boltConnection) onFailure(metadata interface{}) {
+	failure, _ := metadata.(map[string]interface{})
+	code, _ := failure["code"].(string)
+	message, _ := failure["message"].(string)
+
+	connection.metadata = failure
+	connection.failure = connection.config.newDatabaseError(code, message)
+	connection.state = StateFailed
+	connection.reportFailure(connection.failure)
+}
+
+func (connection *boltConnection) markDefunct(err error, description string) error {
+	if connection.state != StateDefunct {
+		connection.state = StateDefunct
+		connection.err = connection.config.newConnectorError(StateDefunct, errorCodeOf(err), err.Error(), description)
+		connection.config.error("connection is defunct", connectionField(connection.id), addressField(connection.address), errorField(err))
+		_ = connection.conn.Close()
+		connection.reportFailure(connection.err)
+	}
+
+	return connection.err
+}
+
+// reportFailure passes an error of the connection on to its pool, which lets the routing
+// connector leave out servers that fail
+func (connection *boltConnection) reportFailure(err error) {
+	if connection.pool != nil && connection.pool.onFailure != nil {
+		connection.pool.onFailure(connection, err)
+	}
+}
+
+func (connection *boltConnection) markProtocolViolation(context string) error {
+	if connection.state != StateDefunct {
+		connection.state = StateDefunct
+		connection.err = connection.config.newConnectorError(StateDefunct, ErrorProtocolViolation, context, "unexpected message received")
+		connection.config.error("connection is defunct", connectionField(connection.id), addressField(connection.address), LogField{Key: LogFieldError, Value: context})
+		_ = connection.conn.Close()
+	}
+
+	return connection.err
+}
+
+// needsReset checks whether the connection carries any state that should be cleared before
+// it is handed out again
+func (connection *boltConnection) needsReset() bool {
+	return len(connection.pending) > 0 || connection.state != StateReady || connection.inTx
+}
+
+// reset sends a RESET and consumes all outstanding responses
+func (connection *boltConnection) reset() error {
+	connection.inTx = false
+
+	handle, err := connection.queueRequest(msgReset)
+	if err != nil {
+		return err
+	}
+
+	if _, err = connection.Fetch(handle); err != nil {
+		return err
+	}
+
+	connection.bookmark = ""
+	connection.fields = nil
+	connection.metadata = nil
+	connection.data = nil
+	return nil
+}
+
+// destroy closes the underlying socket, sending a GOODBYE first if the connection is still
+// usable and the protocol supports it
+func (connection *boltConnection) destroy() error {
+	if connection.state == StateDefunct || connection.state == StateDisconnected {
+		return nil
+	}
+
+	if connection.version >= 3 && connection.state == StateReady {
+		connection.out = connection.out[:0]
+		if err := connection.queueMessage(msgGoodbye); err == nil {
+			_ = connection.Flush()
+		}
+	}
+
+	connection.state = StateDisconnected
+	connection.config.debug("connection closed", connectionField(connection.id), addressField(connection.address))
+	return connection.conn.Close()
+}
+
+// probe checks that an idle connection is still alive by sending a RESET, the server has to
+// respond within the socket connect timeout when one is configured
+func (connection *boltConnection) probe() error {
+	if timeout := connection.config.SockConnectTimeout; timeout > 0 {
+		_ = connection.conn.SetDeadline(time.Now().Add(timeout))
+		defer connection.conn.SetDeadline(time.Time{})
+	}
+
+	return connection.reset()
+}
+
+func messageDescription(signature byte) string {
+	if name, ok := messageNames[signature]; ok {
+		return strings.ToLower(name) + " message"
+	}
+	return "unknown message"
+}
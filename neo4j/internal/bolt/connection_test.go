@@ -0,0 +1,746 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/packstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testServer plays the server side of a connection over an in-memory pipe
+type testServer struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	values *valueSystem
+}
+
+// testDialer returns a dialer that hands every dialed address to the given function running
+// as the server
+func testDialer(t *testing.T, serve func(address string, server *testServer)) dialer {
+	return func(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error) {
+		client, server := net.Pipe()
+		go func() {
+			defer server.Close()
+			serve(address, &testServer{
+				t:      t,
+				conn:   server,
+				reader: bufio.NewReader(server),
+				values: newValueSystem([]ValueHandler{&testPointHandler{}}),
+			})
+		}()
+		return client, nil
+	}
+}
+
+func (server *testServer) handshake(version uint32) bool {
+	request := make([]byte, 20)
+	if _, err := io.ReadFull(server.reader, request); err != nil {
+		server.t.Errorf("unable to read handshake: %v", err)
+		return false
+	}
+	assert.Equal(server.t, handshakeMagic, request[:4])
+
+	response := make([]byte, 4)
+	binary.BigEndian.PutUint32(response, version)
+	_, err := server.conn.Write(response)
+	return err == nil
+}
+
+// accept negotiates the given protocol version and accepts the authentication request
+func (server *testServer) accept(version uint32) bool {
+	if !server.handshake(version) {
+		return false
+	}
+
+	if _, ok := server.expect(msgHello); !ok {
+		return false
+	}
+
+	agent := "Neo4j/3.5.0"
+	if version >= 4 {
+		agent = "Neo4j/4.0.0"
+	}
+	return server.send(msgSuccess, map[string]interface{}{"server": agent})
+}
+
+func (server *testServer) receive() (byte, []interface{}, bool) {
+	header := make([]byte, 2)
+	var message []byte
+	for {
+		if _, err := io.ReadFull(server.reader, header); err != nil {
+			return 0, nil, false
+		}
+		size := int(binary.BigEndian.Uint16(header))
+		if size == 0 {
+			break
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(server.reader, chunk); err != nil {
+			return 0, nil, false
+		}
+		message = append(message, chunk...)
+	}
+
+	unpacker := packstream.NewUnpacker(bytes.NewReader(message))
+	size, signature, err := unpacker.UnpackStructHeader()
+	if err != nil {
+		server.t.Errorf("unable to read message: %v", err)
+		return 0, nil, false
+	}
+	fields := make([]interface{}, size)
+	for i := range fields {
+		if fields[i], err = unpacker.Unpack(); err != nil {
+			server.t.Errorf("unable to read message: %v", err)
+			return 0, nil, false
+		}
+	}
+	return signature, fields, true
+}
+
+func (server *testServer) expect(signature byte) ([]interface{}, bool) {
+	received, fields, ok := server.receive()
+	if !ok {
+		server.t.Errorf("expected %s but connection was closed", messageNames[signature])
+		return nil, false
+	}
+	if received != signature {
+		server.t.Errorf("expected %s but received %s", messageNames[signature], messageNames[received])
+		return nil, false
+	}
+	return fields, true
+}
+
+func (server *testServer) send(signature byte, fields ...interface{}) bool {
+	var buf bytes.Buffer
+	packer := server.values.newPacker(&buf, true)
+	require.NoError(server.t, packer.PackStructHeader(len(fields), signature))
+	for _, field := range fields {
+		require.NoError(server.t, packer.Pack(field))
+	}
+
+	message := []byte{byte(buf.Len() >> 8), byte(buf.Len())}
+	message = append(message, buf.Bytes()...)
+	message = append(message, 0x00, 0x00)
+	_, err := server.conn.Write(message)
+	return err == nil
+}
+
+func TestConnection(t *testing.T) {
+	values := newValueSystem([]ValueHandler{&testPointHandler{}})
+	token := map[string]interface{}{"scheme": "basic", "principal": "neo4j", "credentials": "pass"}
+
+	t.Run("should authenticate with hello on version 3", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.handshake(3) {
+				return
+			}
+			fields, ok := server.expect(msgHello)
+			if !ok {
+				return
+			}
+			hello := fields[0].(map[string]interface{})
+			assert.Equal(t, "neo4j-go/1.8", hello["user_agent"])
+			assert.Equal(t, "neo4j", hello["principal"])
+			server.send(msgSuccess, map[string]interface{}{"server": "Neo4j/3.5.0", "connection_id": "bolt-42"})
+			server.expect(msgGoodbye)
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		server, _ := connection.Server()
+		assert.Equal(t, "Neo4j/3.5.0", server)
+		version, _ := connection.ProtocolVersion()
+		assert.Equal(t, 3, version)
+		serverId, _ := connection.ServerConnectionId()
+		assert.Equal(t, "bolt-42", serverId)
+		assert.NoError(t, connection.Close())
+	})
+
+	t.Run("should authenticate with init on version 1", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.handshake(1) {
+				return
+			}
+			fields, ok := server.expect(msgHello)
+			if !ok {
+				return
+			}
+			assert.Equal(t, "neo4j-go/1.8", fields[0])
+			assert.Equal(t, token, fields[1])
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+		assert.Equal(t, 1, connection.version)
+	})
+
+	t.Run("should fail when no protocol version is agreed upon", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.handshake(0)
+		})
+
+		_, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.Error(t, err)
+		assert.Equal(t, ErrorProtocolUnsupported, err.(ConnectorError).Code())
+	})
+
+	t.Run("should time out when the server does not answer while connecting", func(t *testing.T) {
+		for name, serve := range map[string]func(server *testServer){
+			"handshake": func(server *testServer) {
+				_, _ = io.ReadFull(server.reader, make([]byte, 20))
+			},
+			"hello": func(server *testServer) {
+				if server.handshake(3) {
+					server.expect(msgHello)
+				}
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				dial := testDialer(t, func(address string, server *testServer) {
+					serve(server)
+					// keep the socket open without answering until the client gives up
+					_, _ = server.reader.ReadByte()
+				})
+
+				_, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{SockConnectTimeout: 20 * time.Millisecond}, values)
+				require.Error(t, err)
+				assert.Equal(t, ErrorTimedOut, err.(ConnectorError).Code())
+			})
+		}
+	})
+
+	t.Run("should clear the connect deadline once connected", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			if _, ok := server.expect(msgReset); ok {
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{SockConnectTimeout: 20 * time.Millisecond}, values)
+		require.NoError(t, err)
+		time.Sleep(40 * time.Millisecond)
+
+		handle, err := connection.Reset()
+		require.NoError(t, err)
+		require.NoError(t, connection.Flush())
+		fetched, err := connection.Fetch(handle)
+		assert.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+	})
+
+	t.Run("should fail with permission denied when credentials are rejected", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.handshake(3) {
+				return
+			}
+			if _, ok := server.expect(msgHello); ok {
+				server.send(msgFailure, map[string]interface{}{"code": "Neo.ClientError.Security.Unauthorized", "message": "invalid credentials"})
+			}
+		})
+
+		_, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.Error(t, err)
+		assert.Equal(t, StateDefunct, err.(ConnectorError).State())
+		assert.Equal(t, ErrorPermissionDenied, err.(ConnectorError).Code())
+		assert.True(t, IsAuthenticationError(err))
+	})
+
+	t.Run("should stream records of a statement", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			fields, ok := server.expect(msgRun)
+			if !ok {
+				return
+			}
+			assert.Equal(t, "UNWIND $xs AS x RETURN x", fields[0])
+			assert.Equal(t, map[string]interface{}{"xs": []interface{}{int64(1), int64(2)}}, fields[1])
+			assert.Equal(t, map[string]interface{}{"bookmarks": []interface{}{"bm1"}, "tx_timeout": int64(5000), "mode": "r"}, fields[2])
+			if _, ok := server.expect(msgPullAll); !ok {
+				return
+			}
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"x"}})
+			server.send(msgRecord, []interface{}{int64(1)})
+			server.send(msgRecord, []interface{}{int64(2)})
+			server.send(msgSuccess, map[string]interface{}{"bookmark": "bm2", "type": "r"})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeRead, token, &Config{}, values)
+		require.NoError(t, err)
+
+		runHandle, err := connection.Run("UNWIND $xs AS x RETURN x", map[string]interface{}{"xs": []int{1, 2}}, []string{"bm1"}, 5*time.Second, nil)
+		require.NoError(t, err)
+		pullHandle, err := connection.PullAll()
+		require.NoError(t, err)
+		require.NoError(t, connection.Flush())
+
+		fetched, err := connection.Fetch(runHandle)
+		require.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+		fields, _ := connection.Fields()
+		assert.Equal(t, []string{"x"}, fields)
+
+		for _, expected := range []int64{1, 2} {
+			fetched, err = connection.Fetch(pullHandle)
+			require.NoError(t, err)
+			assert.Equal(t, FetchTypeRecord, fetched)
+			data, _ := connection.Data()
+			assert.Equal(t, []interface{}{expected}, data)
+		}
+
+		fetched, err = connection.Fetch(pullHandle)
+		require.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+		bookmark, _ := connection.LastBookmark()
+		assert.Equal(t, "bm2", bookmark)
+		metadata, _ := connection.Metadata()
+		assert.Equal(t, "r", metadata["type"])
+	})
+
+	t.Run("should report failures and reset before the next request", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgFailure, map[string]interface{}{"code": "Neo.ClientError.Statement.SyntaxError", "message": "invalid syntax"})
+			server.send(msgIgnored)
+
+			server.expect(msgReset)
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{}})
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		runHandle, _ := connection.Run("RETURN", nil, nil, 0, nil)
+		pullHandle, _ := connection.PullAll()
+		require.NoError(t, connection.Flush())
+
+		_, err = connection.Fetch(runHandle)
+		require.Error(t, err)
+		assert.Equal(t, "Neo.ClientError.Statement.SyntaxError", err.(DatabaseError).Code())
+		assert.True(t, IsClientError(err))
+
+		_, err = connection.Fetch(pullHandle)
+		assert.Error(t, err)
+		assert.Equal(t, StateFailed, connection.state)
+
+		runHandle, _ = connection.Run("RETURN 1", nil, nil, 0, nil)
+		pullHandle, _ = connection.PullAll()
+		require.NoError(t, connection.Flush())
+
+		fetched, err := connection.FetchSummary(pullHandle)
+		require.NoError(t, err)
+		assert.Equal(t, 0, fetched)
+		assert.Equal(t, StateReady, connection.state)
+	})
+
+	t.Run("should fail requests in a failed transaction without sending them", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			server.expect(msgBegin)
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgFailure, map[string]interface{}{"code": "Neo.TransientError.Transaction.DeadlockDetected", "message": "deadlock"})
+			server.send(msgIgnored)
+
+			server.expect(msgReset)
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		connection.Begin(nil, 0, nil)
+		_, _ = connection.Run("RETURN 1", nil, nil, 0, nil)
+		pullHandle, _ := connection.PullAll()
+		require.NoError(t, connection.Flush())
+
+		_, err = connection.FetchSummary(pullHandle)
+		require.Error(t, err)
+		assert.True(t, IsTransientError(err))
+
+		runHandle, err := connection.Run("RETURN 2", nil, nil, 0, nil)
+		require.NoError(t, err)
+		_, err = connection.Fetch(runHandle)
+		assert.True(t, IsTransientError(err))
+
+		commitHandle, err := connection.Commit()
+		require.NoError(t, err)
+		_, err = connection.Fetch(commitHandle)
+		assert.True(t, IsTransientError(err))
+
+		rollbackHandle, err := connection.Rollback()
+		require.NoError(t, err)
+		require.NoError(t, connection.Flush())
+		fetched, err := connection.Fetch(rollbackHandle)
+		require.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+	})
+
+	t.Run("should begin and commit transactions through statements on version 1", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(1) {
+				return
+			}
+			fields, _ := server.expect(msgRun)
+			assert.Equal(t, []interface{}{"BEGIN", map[string]interface{}{"bookmark": "bm2", "bookmarks": []interface{}{"bm1", "bm2"}}}, fields)
+			server.expect(msgDiscardAll)
+			fields, _ = server.expect(msgRun)
+			assert.Equal(t, []interface{}{"COMMIT", map[string]interface{}{}}, fields)
+			server.expect(msgDiscardAll)
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgSuccess, map[string]interface{}{"bookmark": "bm3"})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		_, err = connection.Begin([]string{"bm1", "bm2"}, 0, nil)
+		require.NoError(t, err)
+		commitHandle, err := connection.Commit()
+		require.NoError(t, err)
+		require.NoError(t, connection.Flush())
+
+		_, err = connection.Fetch(commitHandle)
+		require.NoError(t, err)
+		bookmark, _ := connection.LastBookmark()
+		assert.Equal(t, "bm3", bookmark)
+	})
+
+	t.Run("should not send structs on version 1", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.accept(1)
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		_, err = connection.Run("RETURN $p", map[string]interface{}{"p": testPoint{}}, nil, 0, nil)
+		require.Error(t, err)
+		assert.Equal(t, ErrorProtocolUnsupportedType, err.(ConnectorError).Code())
+		assert.Equal(t, "unable to generate run message", err.(ConnectorError).Description())
+	})
+
+	t.Run("should reset the connection when a fetch is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"x"}})
+			server.send(msgRecord, []interface{}{int64(1)})
+
+			// the statement keeps running until the client gives up
+			cancel()
+			server.expect(msgReset)
+			server.send(msgFailure, map[string]interface{}{"code": "Neo.TransientError.Transaction.Terminated", "message": "terminated"})
+			server.send(msgSuccess, map[string]interface{}{})
+
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{}})
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		_, _ = connection.Run("UNWIND range(1, 1000000000) AS x RETURN x", nil, nil, 0, nil)
+		pullHandle, _ := connection.PullAll()
+
+		fetched, err := connection.FetchContext(ctx, pullHandle)
+		for err == nil && fetched == FetchTypeRecord {
+			fetched, err = connection.FetchContext(ctx, pullHandle)
+		}
+		assert.Equal(t, context.Canceled, err)
+		assert.Equal(t, StateReady, connection.state)
+		assert.Empty(t, connection.pending)
+
+		_, _ = connection.Run("RETURN 1", nil, nil, 0, nil)
+		pullHandle, _ = connection.PullAll()
+		fetched, err = connection.FetchContext(context.Background(), pullHandle)
+		require.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+	})
+
+	t.Run("should surface an expired deadline of a fetch", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.expect(msgReset)
+			server.send(msgIgnored)
+			server.send(msgIgnored)
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		runHandle, _ := connection.Run("CALL apoc.util.sleep(60000)", nil, nil, 0, nil)
+		_, _ = connection.PullAll()
+
+		_, err = connection.FetchContext(ctx, runHandle)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		assert.Equal(t, StateReady, connection.state)
+		assert.False(t, connection.needsReset())
+	})
+
+	t.Run("should select the database on version 4", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(4) {
+				return
+			}
+			fields, _ := server.expect(msgBegin)
+			assert.Equal(t, map[string]interface{}{"db": "movies", "mode": "r"}, fields[0])
+			fields, _ = server.expect(msgRun)
+			assert.Equal(t, map[string]interface{}{}, fields[2])
+			fields, _ = server.expect(msgPullAll)
+			assert.Equal(t, []interface{}{map[string]interface{}{"n": int64(-1)}}, fields)
+			server.send(msgSuccess, map[string]interface{}{})
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{}})
+			server.send(msgSuccess, map[string]interface{}{"has_more": false})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeRead, token, &Config{}, values)
+		require.NoError(t, err)
+		assert.Equal(t, 4, connection.version)
+		connection.database = "movies"
+
+		_, err = connection.Begin(nil, 0, nil)
+		require.NoError(t, err)
+		_, _ = connection.Run("RETURN 1", nil, nil, 0, nil)
+		pullHandle, _ := connection.PullAll()
+
+		fetched, err := connection.FetchSummary(pullHandle)
+		require.NoError(t, err)
+		assert.Equal(t, 0, fetched)
+	})
+
+	t.Run("should pull and discard records in batches on version 4", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(4) {
+				return
+			}
+			server.expect(msgRun)
+			fields, _ := server.expect(msgPullAll)
+			assert.Equal(t, []interface{}{map[string]interface{}{"n": int64(2)}}, fields)
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"x"}, "qid": int64(0)})
+			server.send(msgRecord, []interface{}{int64(1)})
+			server.send(msgRecord, []interface{}{int64(2)})
+			server.send(msgSuccess, map[string]interface{}{"has_more": true})
+
+			fields, _ = server.expect(msgDiscardAll)
+			assert.Equal(t, []interface{}{map[string]interface{}{"n": int64(-1), "qid": int64(0)}}, fields)
+			server.send(msgSuccess, map[string]interface{}{"type": "r"})
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		runHandle, _ := connection.Run("UNWIND range(1, 10) AS x RETURN x", nil, nil, 0, nil)
+		pullHandle, err := connection.Pull(2, -1)
+		require.NoError(t, err)
+
+		fetched, err := connection.Fetch(runHandle)
+		require.NoError(t, err)
+		assert.Equal(t, FetchTypeMetadata, fetched)
+
+		records, err := connection.FetchSummary(pullHandle)
+		require.NoError(t, err)
+		assert.Equal(t, 2, records)
+		assert.Equal(t, true, connection.metadata["has_more"])
+
+		discardHandle, err := connection.Discard(-1, 0)
+		require.NoError(t, err)
+		records, err = connection.FetchSummary(discardHandle)
+		require.NoError(t, err)
+		assert.Equal(t, 0, records)
+		assert.Equal(t, "r", connection.metadata["type"])
+	})
+
+	t.Run("should not select a database before version 4", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.accept(3)
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+		connection.database = "movies"
+
+		_, err = connection.Run("RETURN 1", nil, nil, 0, nil)
+		require.Error(t, err)
+		assert.Equal(t, ErrorProtocolUnsupported, err.(ConnectorError).Code())
+		assert.Equal(t, "database selection is not supported by the server", err.(ConnectorError).Description())
+	})
+
+	t.Run("should become defunct when the server goes away", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if server.accept(3) {
+				server.expect(msgRun)
+			}
+		})
+
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{}, values)
+		require.NoError(t, err)
+
+		runHandle, _ := connection.Run("RETURN 1", nil, nil, 0, nil)
+		_, err = connection.Fetch(runHandle)
+		require.Error(t, err)
+		assert.Equal(t, StateDefunct, connection.state)
+		assert.True(t, IsServiceUnavailable(err))
+	})
+
+	t.Run("should log the messages in boltstub syntax", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(4) {
+				return
+			}
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"x"}})
+			server.send(msgRecord, []interface{}{"<a & b>"})
+			server.send(msgSuccess, map[string]interface{}{"has_more": false})
+			server.expect(msgGoodbye)
+		})
+
+		logger := &recordingBoltLogger{}
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{BoltLogger: logger}, values)
+		require.NoError(t, err)
+
+		_, _ = connection.Run("RETURN $x AS x", map[string]interface{}{"x": 1}, nil, 0, nil)
+		pullHandle, _ := connection.Pull(1000, -1)
+		_, err = connection.FetchSummary(pullHandle)
+		require.NoError(t, err)
+		require.NoError(t, connection.destroy())
+
+		assert.Equal(t, []string{
+			`C: HELLO {"credentials":"*******","principal":"neo4j","scheme":"basic","user_agent":"neo4j-go/1.8"}`,
+			`S: SUCCESS {"server":"Neo4j/4.0.0"}`,
+			`C: RUN "RETURN $x AS x" {"x":1} {}`,
+			`C: PULL {"n":1000}`,
+			`S: SUCCESS {"fields":["x"]}`,
+			`S: RECORD ["<a & b>"]`,
+			`S: SUCCESS {"has_more":false}`,
+			`C: GOODBYE`,
+		}, logger.messages)
+		assert.Equal(t, []string{connection.id}, logger.connections())
+		assert.Equal(t, "pass", token["credentials"])
+	})
+
+	t.Run("should log init messages before version 3", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.accept(1)
+		})
+
+		logger := &recordingBoltLogger{}
+		_, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{BoltLogger: logger}, values)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, logger.messages)
+		assert.Equal(t, `C: INIT "neo4j-go/1.8" {"credentials":"*******","principal":"neo4j","scheme":"basic"}`, logger.messages[0])
+	})
+
+	t.Run("should log to the bolt logger set on the connection", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if server.accept(3) {
+				server.expect(msgReset)
+				server.send(msgSuccess, map[string]interface{}{})
+				server.expect(msgReset)
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connectorLogger, sessionLogger := &recordingBoltLogger{}, &recordingBoltLogger{}
+		connection, err := connect(dial, "localhost:7687", AccessModeWrite, token, &Config{BoltLogger: connectorLogger}, values)
+		require.NoError(t, err)
+
+		connection.SetBoltLogger(sessionLogger)
+		require.NoError(t, connection.reset())
+		connection.SetBoltLogger(nil)
+		require.NoError(t, connection.reset())
+
+		assert.Equal(t, []string{"C: RESET", "S: SUCCESS {}"}, sessionLogger.messages)
+		assert.Equal(t, []string{"C: RESET", "S: SUCCESS {}"}, connectorLogger.messages[2:])
+	})
+}
+
+// recordingBoltLogger records the messages it receives
+type recordingBoltLogger struct {
+	mutex      sync.Mutex
+	messages   []string
+	connection map[string]bool
+}
+
+func (logger *recordingBoltLogger) LogMessage(connectionId string, timestamp time.Time, message string) {
+	logger.mutex.Lock()
+	defer logger.mutex.Unlock()
+
+	if logger.connection == nil {
+		logger.connection = map[string]bool{}
+	}
+	logger.connection[connectionId] = true
+	logger.messages = append(logger.messages, message)
+}
+
+func (logger *recordingBoltLogger) connections() []string {
+	var ids []string
+	for id := range logger.connection {
+		ids = append(ids, id)
+	}
+	return ids
+}
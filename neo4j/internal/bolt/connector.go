@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+)
+
+const defaultPort = "7687"
+
+// AccessMode defines whether a connection is acquired for reading or writing
+type AccessMode int
+
+const (
+	// AccessModeWrite asks for a connection to a server that accepts writes
+	AccessModeWrite AccessMode = 0
+	// AccessModeRead asks for a connection to a server that accepts reads
+	AccessModeRead AccessMode = 1
+)
+
+// Connector hands out connections to the server or the cluster it was created for, ctx
+// bounds the time spent waiting for a connection. Statements run on an acquired connection
+// target the given database, an empty name selects the default database of the server.
+// VerifyConnectivity checks that a connection can be established and authenticated and, for
+// a cluster, that a routing table can be retrieved. Metrics returns a snapshot of the
+// connection pools of the servers the connector currently keeps connections to.
+type Connector interface {
+	Acquire(ctx context.Context, mode AccessMode, database string) (Connection, error)
+	VerifyConnectivity(ctx context.Context) error
+	Metrics() []PoolMetrics
+	Close() error
+}
+
+type directConnector struct {
+	pool *pool
+}
+
+// NewConnector creates a connector for the given target, no connections are opened until
+// one is acquired unless a minimum of idle connections is configured
+func NewConnector(target *url.URL, authToken map[string]interface{}, config *Config) (Connector, error) {
+	return newConnector(target, authToken, config, dialTCP)
+}
+
+func newConnector(target *url.URL, authToken map[string]interface{}, config *Config, dial dialer) (Connector, error) {
+	if config == nil {
+		config = &Config{}
+	}
+
+	values := newValueSystem(config.ValueHandlers)
+	if err := values.newPacker(ioutil.Discard, false).Pack(authToken); err != nil {
+		return nil, config.newGenericError("unable to convert authentication token: %v", err)
+	}
+
+	context, err := routingContextOf(target)
+	if err != nil {
+		return nil, config.newGenericError("unable to extract routing context: %v", err)
+	}
+
+	switch target.Scheme {
+	case "bolt":
+		return &directConnector{
+			pool: newPool(addressOf(target), authToken, config, values, dial),
+		}, nil
+	case "bolt+routing", "neo4j":
+		if config.LoadBalancer == nil {
+			return nil, config.newGenericError("a load balancer is required to route connections to %s", target.Host)
+		}
+
+		connector := newRoutingConnector(target, context, authToken, config, values, dial)
+		if config.MinIdleConns > 0 {
+			go connector.warmUp()
+		}
+		return connector, nil
+	}
+
+	return nil, config.newGenericError("unsupported URL scheme: %s", target.Scheme)
+}
+
+func (connector *directConnector) Acquire(ctx context.Context, mode AccessMode, database string) (Connection, error) {
+	connection, err := connector.pool.acquire(ctx, mode, database)
+	if err != nil {
+		return nil, err
+	}
+
+	return connection, nil
+}
+
+func (connector *directConnector) VerifyConnectivity(ctx context.Context) error {
+	connection, err := connector.pool.acquire(ctx, AccessModeWrite, "")
+	if err != nil {
+		return err
+	}
+
+	// pooled connections may have been opened long ago, a round trip proves the server is
+	// still there
+	if err = connection.probe(); err != nil {
+		_ = connection.Close()
+		return err
+	}
+
+	return connection.Close()
+}
+
+func (connector *directConnector) Metrics() []PoolMetrics {
+	return []PoolMetrics{connector.pool.metrics()}
+}
+
+func (connector *directConnector) Close() error {
+	return connector.pool.close()
+}
+
+// addressOf returns the host:port of the target, using the default port when none is given
+func addressOf(target *url.URL) string {
+	port := target.Port()
+	if port == "" {
+		port = defaultPort
+	}
+
+	return net.JoinHostPort(target.Hostname(), port)
+}
+
+// routingContextOf extracts the routing context from the query string of the target, keys
+// are only allowed to appear once
+func routingContextOf(target *url.URL) (map[string]string, error) {
+	query, err := url.ParseQuery(target.RawQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	context := make(map[string]string, len(query))
+	for key, values := range query {
+		if len(values) > 1 {
+			return nil, fmt.Errorf("duplicate key '%s' in routing context", key)
+		}
+		context[key] = values[0]
+	}
+
+	return context, nil
+}
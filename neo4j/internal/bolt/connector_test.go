@@ -0,0 +1,914 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnector(t *testing.T) {
+	token := map[string]interface{}{"scheme": "none"}
+
+	newTestConnector := func(t *testing.T, target string, config *Config, dial dialer) Connector {
+		targetURL, err := url.Parse(target)
+		require.NoError(t, err)
+
+		if config.LoadBalancer == nil {
+			// routing connectors need a load balancer, this one selects the candidates in turn
+			var next int64
+			config.LoadBalancer = func(mode AccessMode, servers []ServerLoad) int {
+				return int((atomic.AddInt64(&next, 1) - 1) % int64(len(servers)))
+			}
+		}
+
+		connector, err := newConnector(targetURL, token, config, dial)
+		require.NoError(t, err)
+		return connector
+	}
+
+	// acceptAndServe accepts the connection and answers every request with an empty success
+	acceptAndServe := func(address string, server *testServer) {
+		if !server.accept(3) {
+			return
+		}
+		for {
+			signature, _, ok := server.receive()
+			if !ok || signature == msgGoodbye {
+				return
+			}
+			server.send(msgSuccess, map[string]interface{}{})
+		}
+	}
+
+	t.Run("should not connect when created", func(t *testing.T) {
+		var dialed int32
+		newTestConnector(t, "bolt://localhost:7687", &Config{}, func(network, address string, timeout time.Duration, keepAlive bool) (conn net.Conn, err error) {
+			atomic.AddInt32(&dialed, 1)
+			return nil, nil
+		})
+
+		assert.Equal(t, int32(0), atomic.LoadInt32(&dialed))
+	})
+
+	t.Run("should fail on invalid routing context", func(t *testing.T) {
+		for _, target := range []string{"bolt://localhost?a%b", "neo4j://localhost?a=b&a=c"} {
+			targetURL, err := url.Parse(target)
+			require.NoError(t, err)
+
+			_, err = newConnector(targetURL, token, &Config{}, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "unable to extract routing context")
+		}
+	})
+
+	t.Run("should fail on unsupported authentication token values", func(t *testing.T) {
+		targetURL, _ := url.Parse("bolt://localhost")
+
+		_, err := newConnector(targetURL, map[string]interface{}{"value": struct{}{}}, &Config{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unable to convert authentication token")
+	})
+
+	t.Run("should reuse released connections", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		first, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		address, _ := first.RemoteAddress()
+		assert.Equal(t, "localhost:7687", address)
+		require.NoError(t, first.Close())
+
+		second, err := connector.Acquire(context.Background(), AccessModeRead, "")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+	})
+
+	t.Run("should restore the bolt logger of the connector on released connections", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		connection, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		connection.SetBoltLogger(&recordingBoltLogger{})
+		require.NoError(t, connection.Close())
+
+		assert.Nil(t, connection.(*boltConnection).boltLogger)
+	})
+
+	t.Run("should fail when pool is full and no acquisition timeout is set", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		_, err = connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.Error(t, err)
+		assert.Equal(t, ErrorPoolFull, err.(ConnectorError).Code())
+	})
+
+	t.Run("should time out when no connection is released in time", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1, ConnAcquisitionTimeout: 10 * time.Millisecond}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		_, err = connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.Error(t, err)
+		assert.Equal(t, ErrorPoolAcquisitionTimedOut, err.(ConnectorError).Code())
+	})
+
+	t.Run("should hand over released connections to waiting acquirers", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1, ConnAcquisitionTimeout: time.Minute}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		first, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			first.Close()
+		}()
+
+		second, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+	})
+
+	t.Run("should stop waiting for a connection when the context is done", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1, ConnAcquisitionTimeout: -1}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err = connector.Acquire(ctx, AccessModeWrite, "")
+		assert.Equal(t, context.Canceled, err)
+	})
+
+	t.Run("should serve waiting acquirers in the order they arrived", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1, ConnAcquisitionTimeout: time.Minute}, testDialer(t, acceptAndServe))
+		defer connector.Close()
+		pool := connector.(*directConnector).pool
+
+		first, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		served := make(chan int, 3)
+		for i := 1; i <= 3; i++ {
+			go func(i int) {
+				connection, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+				if !assert.NoError(t, err) {
+					served <- 0
+					return
+				}
+				served <- i
+				assert.NoError(t, connection.Close())
+			}(i)
+			require.Eventually(t, func() bool { return waitersOf(pool) == i }, time.Second, time.Millisecond)
+		}
+
+		require.NoError(t, first.Close())
+		for i := 1; i <= 3; i++ {
+			assert.Equal(t, i, <-served)
+		}
+	})
+
+	t.Run("should close connections that are idle for longer than the maximum idle time", func(t *testing.T) {
+		config := &Config{MaxConnIdleTime: 20 * time.Millisecond, MinIdleConns: 1}
+		connector := newTestConnector(t, "bolt://localhost", config, testDialer(t, acceptAndServe))
+		defer connector.Close()
+		pool := connector.(*directConnector).pool
+
+		var connections []Connection
+		for i := 0; i < 3; i++ {
+			connection, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+			require.NoError(t, err)
+			connections = append(connections, connection)
+		}
+		for _, connection := range connections {
+			require.NoError(t, connection.Close())
+		}
+
+		require.Eventually(t, func() bool {
+			total, idle := sizeOf(pool)
+			return total == 1 && idle == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should keep the minimum of idle connections while others are in use", func(t *testing.T) {
+		config := &Config{MaxConnIdleTime: 20 * time.Millisecond, MinIdleConns: 1}
+		connector := newTestConnector(t, "bolt://localhost", config, testDialer(t, acceptAndServe))
+		defer connector.Close()
+		pool := connector.(*directConnector).pool
+
+		require.Eventually(t, func() bool {
+			_, idle := sizeOf(pool)
+			return idle == 1
+		}, time.Second, time.Millisecond)
+		connection, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		defer connection.Close()
+
+		require.Eventually(t, func() bool {
+			total, idle := sizeOf(pool)
+			return total == 2 && idle == 1
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("should open the minimum of idle connections when created", func(t *testing.T) {
+		var dialed int32
+		dial := testDialer(t, func(address string, server *testServer) {
+			atomic.AddInt32(&dialed, 1)
+			acceptAndServe(address, server)
+		})
+
+		connector := newTestConnector(t, "bolt://localhost", &Config{MinIdleConns: 2}, dial)
+		defer connector.Close()
+
+		require.Eventually(t, func() bool {
+			_, idle := sizeOf(connector.(*directConnector).pool)
+			return idle == 2
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&dialed))
+	})
+
+	t.Run("should open the minimum of idle connections to the cluster members when created", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address == "127.0.0.1:9001" {
+				// the pool of the router is warmed up as well, the routing table is fetched on
+				// either connection
+				script := loadScript(t, "v3/acquire_endpoints.script")
+				script.mayBeUnused = true
+				server.play(script)
+				return
+			}
+			acceptAndServe(address, server)
+		})
+
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", &Config{MinIdleConns: 1}, dial).(*routingConnector)
+		defer connector.Close()
+
+		for _, address := range []string{"127.0.0.1:9005", "127.0.0.1:9006", "127.0.0.1:9007"} {
+			require.Eventually(t, func() bool {
+				connector.mutex.Lock()
+				pool, ok := connector.pools[address]
+				connector.mutex.Unlock()
+				if !ok {
+					return false
+				}
+				_, idle := sizeOf(pool)
+				return idle == 1
+			}, time.Second, time.Millisecond, "idle connections to %s", address)
+		}
+	})
+
+	t.Run("should check that connections idle for longer than the threshold are alive", func(t *testing.T) {
+		var dialed, resets int32
+		dial := testDialer(t, func(address string, server *testServer) {
+			first := atomic.AddInt32(&dialed, 1) == 1
+			if !server.accept(3) {
+				return
+			}
+			for {
+				signature, _, ok := server.receive()
+				if !ok || signature == msgGoodbye {
+					return
+				}
+				if signature == msgReset && atomic.AddInt32(&resets, 1) > 1 && first {
+					// the second check finds the connection broken
+					return
+				}
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connector := newTestConnector(t, "bolt://localhost", &Config{ConnLivenessCheckTimeout: time.Millisecond}, dial)
+		defer connector.Close()
+
+		first, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		require.NoError(t, first.Close())
+
+		time.Sleep(5 * time.Millisecond)
+		second, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Equal(t, int32(1), atomic.LoadInt32(&resets))
+		require.NoError(t, second.Close())
+
+		time.Sleep(5 * time.Millisecond)
+		third, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		assert.NotSame(t, first, third)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&dialed))
+	})
+
+	t.Run("should report the connections and acquisitions of the pool", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{MaxPoolSize: 1, ConnAcquisitionTimeout: 10 * time.Millisecond}, testDialer(t, acceptAndServe))
+
+		connection, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		_, err = connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.Error(t, err)
+
+		metrics := connector.Metrics()
+		require.Len(t, metrics, 1)
+		assert.Equal(t, "localhost:7687", metrics[0].Address)
+		assert.Equal(t, 1, metrics[0].InUse)
+		assert.Equal(t, 0, metrics[0].Idle)
+		assert.Equal(t, int64(1), metrics[0].Created)
+		assert.Equal(t, int64(1), metrics[0].AcquisitionTimeouts)
+		assert.Equal(t, int64(1), metrics[0].AcquisitionLatency.Count)
+		assert.Len(t, metrics[0].AcquisitionLatency.Counts, len(metrics[0].AcquisitionLatency.Bounds)+1)
+
+		require.NoError(t, connection.Close())
+		metrics = connector.Metrics()
+		assert.Equal(t, 0, metrics[0].InUse)
+		assert.Equal(t, 1, metrics[0].Idle)
+
+		require.NoError(t, connector.Close())
+		metrics = connector.Metrics()
+		assert.Equal(t, 0, metrics[0].Idle)
+		assert.Equal(t, int64(1), metrics[0].Closed)
+	})
+
+	t.Run("should report connections that could not be opened", func(t *testing.T) {
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, func(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error) {
+			return nil, syscall.ECONNREFUSED
+		})
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.Error(t, err)
+
+		metrics := connector.Metrics()
+		require.Len(t, metrics, 1)
+		assert.Equal(t, int64(0), metrics[0].Created)
+		assert.Equal(t, int64(1), metrics[0].FailedToCreate)
+		assert.Equal(t, int64(0), metrics[0].AcquisitionTimeouts)
+		assert.Equal(t, int64(0), metrics[0].AcquisitionLatency.Count)
+	})
+
+	t.Run("should verify connectivity with a round trip to the server", func(t *testing.T) {
+		var resets int32
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			for {
+				signature, _, ok := server.receive()
+				if !ok || signature == msgGoodbye {
+					return
+				}
+				if signature == msgReset {
+					atomic.AddInt32(&resets, 1)
+				}
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, dial)
+		defer connector.Close()
+
+		require.NoError(t, connector.VerifyConnectivity(context.Background()))
+		require.NoError(t, connector.VerifyConnectivity(context.Background()))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&resets))
+
+		total, idle := sizeOf(connector.(*directConnector).pool)
+		assert.Equal(t, 1, total)
+		assert.Equal(t, 1, idle)
+	})
+
+	t.Run("should fail verification when the credentials are rejected", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.handshake(3) {
+				return
+			}
+			server.expect(msgHello)
+			server.send(msgFailure, map[string]interface{}{"code": "Neo.ClientError.Security.Unauthorized", "message": "invalid credentials"})
+		})
+
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, dial)
+		defer connector.Close()
+
+		err := connector.VerifyConnectivity(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, ErrorPermissionDenied, err.(ConnectorError).Code())
+	})
+
+	t.Run("should fail verification when no protocol version is agreed upon", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.handshake(0)
+		})
+
+		connector := newTestConnector(t, "bolt://localhost", &Config{}, dial)
+		defer connector.Close()
+
+		err := connector.VerifyConnectivity(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, ErrorProtocolUnsupported, err.(ConnectorError).Code())
+	})
+
+	t.Run("should route connections according to the routing table", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address != "router:7687" {
+				acceptAndServe(address, server)
+				return
+			}
+
+			if !server.accept(3) {
+				return
+			}
+			fields, _ := server.expect(msgRun)
+			assert.Equal(t, "CALL dbms.cluster.routing.getRoutingTable($context)", fields[0])
+			assert.Equal(t, map[string]interface{}{"context": map[string]interface{}{"region": "eu"}}, fields[1])
+			server.expect(msgPullAll)
+			server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"ttl", "servers"}})
+			server.send(msgRecord, []interface{}{int64(300), []interface{}{
+				map[string]interface{}{"addresses": []interface{}{"writer:7687"}, "role": "WRITE"},
+				map[string]interface{}{"addresses": []interface{}{"reader1:7687", "reader2:7687"}, "role": "READ"},
+				map[string]interface{}{"addresses": []interface{}{"router:7687"}, "role": "ROUTE"},
+			}})
+			server.send(msgSuccess, map[string]interface{}{})
+			server.expect(msgGoodbye)
+		})
+
+		connector := newTestConnector(t, "neo4j://router?region=eu", &Config{}, dial)
+		defer connector.Close()
+
+		writer, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		address, _ := writer.RemoteAddress()
+		assert.Equal(t, "writer:7687", address)
+
+		readers := map[string]bool{}
+		for i := 0; i < 2; i++ {
+			reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+			require.NoError(t, err)
+			address, _ := reader.RemoteAddress()
+			readers[address] = true
+		}
+		assert.Equal(t, map[string]bool{"reader1:7687": true, "reader2:7687": true}, readers)
+	})
+
+	t.Run("should keep a routing table per database", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address != "router:7687" {
+				acceptAndServe(address, server)
+				return
+			}
+
+			if !server.accept(4) {
+				return
+			}
+			for {
+				signature, fields, ok := server.receive()
+				if !ok || signature == msgGoodbye {
+					return
+				}
+				require.Equal(t, msgRun, signature)
+				assert.Equal(t, "CALL dbms.routing.getRoutingTable($context, $database)", fields[0])
+				assert.Equal(t, map[string]interface{}{"db": "system"}, fields[2])
+
+				writer := "writer:7687"
+				if database := fields[1].(map[string]interface{})["database"]; database != nil {
+					writer = database.(string) + "-writer:7687"
+				}
+				server.expect(msgPullAll)
+				server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"ttl", "servers"}})
+				server.send(msgRecord, []interface{}{int64(300), []interface{}{
+					map[string]interface{}{"addresses": []interface{}{writer}, "role": "WRITE"},
+					map[string]interface{}{"addresses": []interface{}{"reader:7687"}, "role": "READ"},
+					map[string]interface{}{"addresses": []interface{}{"router:7687"}, "role": "ROUTE"},
+				}})
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connector := newTestConnector(t, "neo4j://router", &Config{}, dial)
+		defer connector.Close()
+
+		for database, expected := range map[string]string{"movies": "movies-writer:7687", "": "writer:7687"} {
+			writer, err := connector.Acquire(context.Background(), AccessModeWrite, database)
+			require.NoError(t, err)
+			address, _ := writer.RemoteAddress()
+			assert.Equal(t, expected, address)
+			assert.Equal(t, database, writer.(*boltConnection).database)
+		}
+	})
+
+	t.Run("should keep serving while a routing table is fetched", func(t *testing.T) {
+		var calls int32
+		fetching, release := make(chan struct{}, 1), make(chan struct{})
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address != "router:7687" {
+				acceptAndServe(address, server)
+				return
+			}
+
+			if !server.accept(4) {
+				return
+			}
+			for {
+				signature, fields, ok := server.receive()
+				if !ok || signature == msgGoodbye {
+					return
+				}
+				require.Equal(t, msgRun, signature)
+				atomic.AddInt32(&calls, 1)
+
+				if fields[1].(map[string]interface{})["database"] == "slow" {
+					fetching <- struct{}{}
+					<-release
+				}
+				server.expect(msgPullAll)
+				server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"ttl", "servers"}})
+				server.send(msgRecord, []interface{}{int64(300), []interface{}{
+					map[string]interface{}{"addresses": []interface{}{"writer:7687"}, "role": "WRITE"},
+					map[string]interface{}{"addresses": []interface{}{"reader:7687"}, "role": "READ"},
+					map[string]interface{}{"addresses": []interface{}{"router:7687"}, "role": "ROUTE"},
+				}})
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		})
+
+		connector := newTestConnector(t, "neo4j://router", &Config{}, dial)
+		defer connector.Close()
+
+		acquired := make(chan error, 3)
+		for i := 0; i < cap(acquired); i++ {
+			go func() {
+				connection, err := connector.Acquire(context.Background(), AccessModeWrite, "slow")
+				if err == nil {
+					err = connection.Close()
+				}
+				acquired <- err
+			}()
+		}
+		<-fetching
+
+		writer, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		assert.Len(t, connector.Metrics(), 2)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = connector.Acquire(ctx, AccessModeRead, "slow")
+		assert.Equal(t, context.DeadlineExceeded, err)
+
+		close(release)
+		for i := 0; i < cap(acquired); i++ {
+			assert.NoError(t, <-acquired)
+		}
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should fail when no routing table can be retrieved", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if !server.accept(3) {
+				return
+			}
+			server.expect(msgRun)
+			server.expect(msgPullAll)
+			server.send(msgFailure, map[string]interface{}{"code": "Neo.ClientError.Procedure.ProcedureNotFound", "message": "no such procedure"})
+			server.send(msgIgnored)
+			server.expect(msgReset)
+			server.send(msgSuccess, map[string]interface{}{})
+		})
+
+		connector := newTestConnector(t, "bolt+routing://router", &Config{}, dial)
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeRead, "")
+		require.Error(t, err)
+		assert.Equal(t, ErrorRoutingUnableToRetrieveTable, err.(ConnectorError).Code())
+		assert.True(t, IsServiceUnavailable(err))
+	})
+
+	// serveRoutingTable answers every routing request with the given ttl and counts them
+	serveRoutingTable := func(ttl int64, calls *int32) func(address string, server *testServer) {
+		return func(address string, server *testServer) {
+			if address != "router:7687" {
+				acceptAndServe(address, server)
+				return
+			}
+
+			if !server.accept(3) {
+				return
+			}
+			for {
+				signature, _, ok := server.receive()
+				if !ok || signature == msgGoodbye {
+					return
+				}
+				require.Equal(t, msgRun, signature)
+				atomic.AddInt32(calls, 1)
+
+				server.expect(msgPullAll)
+				server.send(msgSuccess, map[string]interface{}{"fields": []interface{}{"ttl", "servers"}})
+				server.send(msgRecord, []interface{}{ttl, []interface{}{
+					map[string]interface{}{"addresses": []interface{}{"writer:7687"}, "role": "WRITE"},
+					map[string]interface{}{"addresses": []interface{}{"reader:7687"}, "role": "READ"},
+					map[string]interface{}{"addresses": []interface{}{"router:7687"}, "role": "ROUTE"},
+				}})
+				server.send(msgSuccess, map[string]interface{}{})
+			}
+		}
+	}
+
+	t.Run("should route with the routing table of the acquire endpoints script", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address == "127.0.0.1:9001" {
+				server.play(loadScript(t, "v3/acquire_endpoints.script"))
+				return
+			}
+			acceptAndServe(address, server)
+		})
+
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", &Config{}, dial)
+		defer connector.Close()
+
+		writer, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		address, _ := writer.RemoteAddress()
+		assert.Equal(t, "127.0.0.1:9007", address)
+
+		table := connector.(*routingConnector).tables[""]
+		assert.Equal(t, []string{"127.0.0.1:9005", "127.0.0.1:9006"}, table.readers)
+		assert.Equal(t, []string{"127.0.0.1:9001", "127.0.0.1:9002", "127.0.0.1:9003"}, table.routers)
+		assert.WithinDuration(t, time.Now().Add(6000*time.Second), table.expires, time.Minute)
+	})
+
+	t.Run("should give up when the router keeps advertising an unreachable server", func(t *testing.T) {
+		serve := testDialer(t, func(address string, server *testServer) {
+			server.play(loadScript(t, "v3/unreachable_reader.script"))
+		})
+		var dialed int32
+		dial := func(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error) {
+			if address == "127.0.0.1:9004" {
+				atomic.AddInt32(&dialed, 1)
+				return nil, syscall.ECONNREFUSED
+			}
+			return serve(network, address, timeout, keepAlive)
+		}
+
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", &Config{}, dial)
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeRead, "")
+		require.Error(t, err)
+		assert.True(t, IsSessionExpired(err))
+		assert.True(t, IsServiceUnavailable(err))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&dialed))
+	})
+
+	t.Run("should require a load balancer to route connections", func(t *testing.T) {
+		targetURL, _ := url.Parse("neo4j://router")
+
+		_, err := newConnector(targetURL, token, &Config{}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "a load balancer is required")
+	})
+
+	t.Run("should select servers with the configured load balancer", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address == "127.0.0.1:9001" {
+				server.play(loadScript(t, "v3/acquire_endpoints.script"))
+				return
+			}
+			acceptAndServe(address, server)
+		})
+
+		var loads []ServerLoad
+		config := &Config{LoadBalancer: func(mode AccessMode, servers []ServerLoad) int {
+			assert.Equal(t, AccessModeRead, mode)
+			loads = servers
+			return len(servers) - 1
+		}}
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", config, dial)
+		defer connector.Close()
+
+		for i := 0; i < 2; i++ {
+			reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+			require.NoError(t, err)
+			address, _ := reader.RemoteAddress()
+			assert.Equal(t, "127.0.0.1:9006", address)
+		}
+
+		require.Len(t, loads, 2)
+		assert.Equal(t, ServerLoad{Address: "127.0.0.1:9005"}, loads[0])
+		assert.Equal(t, "127.0.0.1:9006", loads[1].Address)
+		assert.Equal(t, 1, loads[1].InUse)
+		assert.True(t, loads[1].Latency > 0)
+	})
+
+	t.Run("should fail when the load balancer selects an unknown server", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.play(loadScript(t, "v3/acquire_endpoints.script"))
+		})
+
+		config := &Config{LoadBalancer: func(mode AccessMode, servers []ServerLoad) int {
+			return len(servers)
+		}}
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", config, dial)
+		defer connector.Close()
+
+		_, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "load balancer selected server 1 out of 1 candidates")
+	})
+
+	t.Run("should verify connectivity of a cluster by retrieving a routing table", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			server.play(loadScript(t, "v3/acquire_endpoints.script"))
+		})
+
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", &Config{}, dial)
+		defer connector.Close()
+
+		require.NoError(t, connector.VerifyConnectivity(context.Background()))
+		assert.Equal(t, []string{"127.0.0.1:9007"}, connector.(*routingConnector).tables[""].writers)
+	})
+
+	t.Run("should fail verification of a cluster with the error of the last router", func(t *testing.T) {
+		var dialed []string
+		dial := func(network, address string, timeout time.Duration, keepAlive bool) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return nil, &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{Err: "no such host", Name: address}}
+		}
+		config := &Config{AddressResolver: func(address *url.URL) []*url.URL {
+			return []*url.URL{{Host: "core1:7687"}, {Host: "core2:7687"}}
+		}}
+
+		connector := newTestConnector(t, "neo4j://cluster", config, dial)
+		defer connector.Close()
+
+		err := connector.VerifyConnectivity(context.Background())
+		require.Error(t, err)
+		assert.Equal(t, ErrorAddressNotResolved, err.(ConnectorError).Code())
+		assert.Contains(t, err.Error(), "core2:7687")
+		assert.Equal(t, []string{"core1:7687", "core2:7687"}, dialed)
+	})
+
+	t.Run("should honour the time to live of the routing table", func(t *testing.T) {
+		for ttl, expected := range map[int64]int32{0: 3, 300: 1} {
+			var calls int32
+			connector := newTestConnector(t, "neo4j://router", &Config{}, testDialer(t, serveRoutingTable(ttl, &calls)))
+
+			for i := 0; i < 3; i++ {
+				reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+				require.NoError(t, err)
+				require.NoError(t, reader.Close())
+			}
+			assert.Equal(t, expected, atomic.LoadInt32(&calls), "routing calls with a ttl of %d", ttl)
+
+			require.NoError(t, connector.Close())
+		}
+	})
+
+	t.Run("should forget servers that fail while in use", func(t *testing.T) {
+		dial := testDialer(t, func(address string, server *testServer) {
+			switch address {
+			case "127.0.0.1:9001":
+				server.play(loadScript(t, "v3/acquire_endpoints.script"))
+			case "127.0.0.1:9005":
+				server.play(loadScript(t, "v3/disconnect_on_read.script"))
+			default:
+				acceptAndServe(address, server)
+			}
+		})
+
+		connector := newTestConnector(t, "neo4j://127.0.0.1:9001", &Config{}, dial)
+		defer connector.Close()
+
+		// keep one reader busy so that the next one is the failing server
+		var failing Connection
+		for failing == nil {
+			reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+			require.NoError(t, err)
+			if address, _ := reader.RemoteAddress(); address == "127.0.0.1:9005" {
+				failing = reader
+			}
+		}
+
+		runHandle, err := failing.Run("RETURN 1", map[string]interface{}{}, nil, 0, nil)
+		require.NoError(t, err)
+		require.NoError(t, failing.Flush())
+		_, err = failing.Fetch(runHandle)
+		require.Error(t, err)
+		assert.True(t, IsServiceUnavailable(err))
+		require.NoError(t, failing.Close())
+
+		reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+		require.NoError(t, err)
+		address, _ := reader.RemoteAddress()
+		assert.Equal(t, "127.0.0.1:9006", address)
+		assert.Equal(t, []string{"127.0.0.1:9006"}, connector.(*routingConnector).tables[""].readers)
+	})
+
+	t.Run("should refresh the routing table when the writer is no longer the leader", func(t *testing.T) {
+		var calls int32
+		serve := serveRoutingTable(300, &calls)
+		dial := testDialer(t, func(address string, server *testServer) {
+			if address == "writer:7687" && atomic.LoadInt32(&calls) == 1 {
+				server.play(loadScript(t, "v3/not_a_leader.script"))
+				return
+			}
+			serve(address, server)
+		})
+
+		connector := newTestConnector(t, "neo4j://router", &Config{}, dial)
+		defer connector.Close()
+
+		writer, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+
+		_, err = writer.Run("CREATE ()", map[string]interface{}{}, nil, 0, nil)
+		require.NoError(t, err)
+		pullHandle, err := writer.PullAll()
+		require.NoError(t, err)
+		require.NoError(t, writer.Flush())
+		_, err = writer.Fetch(pullHandle)
+		require.Error(t, err)
+		assert.True(t, IsWriteError(err))
+		require.NoError(t, writer.Close())
+
+		writer, err = connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		address, _ := writer.RemoteAddress()
+		assert.Equal(t, "writer:7687", address)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should report the pools of the cluster members ordered by address", func(t *testing.T) {
+		var calls int32
+		connector := newTestConnector(t, "neo4j://router:7687", &Config{}, testDialer(t, serveRoutingTable(300, &calls)))
+		defer connector.Close()
+
+		reader, err := connector.Acquire(context.Background(), AccessModeRead, "")
+		require.NoError(t, err)
+		writer, err := connector.Acquire(context.Background(), AccessModeWrite, "")
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+
+		metrics := connector.Metrics()
+		require.Len(t, metrics, 3)
+		assert.Equal(t, "reader:7687", metrics[0].Address)
+		assert.Equal(t, 1, metrics[0].InUse)
+		assert.Equal(t, "router:7687", metrics[1].Address)
+		assert.Equal(t, int64(1), metrics[1].Created)
+		assert.Equal(t, "writer:7687", metrics[2].Address)
+		assert.Equal(t, 1, metrics[2].Idle)
+		require.NoError(t, reader.Close())
+	})
+}
+
+func sizeOf(p *pool) (int, int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.total, len(p.idle)
+}
+
+func waitersOf(p *pool) int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.waiters)
+}
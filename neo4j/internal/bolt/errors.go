@@ -0,0 +1,455 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+// Connection states reported on connector errors
+const (
+	StateDisconnected = 0
+	StateConnected    = 1
+	StateReady        = 2
+	StateFailed       = 3
+	StateDefunct      = 4
+)
+
+// Error codes reported on connector errors
+const (
+	ErrorUnknown                            = 1
+	ErrorUnsupported                        = 2
+	ErrorInterrupted                        = 3
+	ErrorConnectionReset                    = 4
+	ErrorNoValidAddress                     = 5
+	ErrorTimedOut                           = 6
+	ErrorPermissionDenied                   = 7
+	ErrorOutOfFiles                         = 8
+	ErrorOutOfMemory                        = 9
+	ErrorOutOfPorts                         = 10
+	ErrorConnectionRefused                  = 11
+	ErrorNetworkUnreachable                 = 12
+	ErrorTLS                                = 13
+	ErrorEndOfTransmission                  = 15
+	ErrorServerFailure                      = 16
+	ErrorTransportUnsupported               = 0x400
+	ErrorProtocolViolation                  = 0x500
+	ErrorProtocolUnsupportedType            = 0x501
+	ErrorProtocolNotImplementedType         = 0x502
+	ErrorProtocolUnexpectedMarker           = 0x503
+	ErrorProtocolUnsupported                = 0x504
+	ErrorPoolFull                           = 0x600
+	ErrorPoolAcquisitionTimedOut            = 0x601
+	ErrorAddressNotResolved                 = 0x700
+	ErrorRoutingUnableToRetrieveTable       = 0x800
+	ErrorRoutingNoServersToSelect           = 0x801
+	ErrorRoutingUnableToConstructPool       = 0x802
+	ErrorRoutingUnableToRefreshTable        = 0x803
+	ErrorRoutingUnexpectedDiscoveryResponse = 0x804
+)
+
+var errorTexts = map[int]string{
+	ErrorUnknown:                            "BOLT_UNKNOWN_ERROR",
+	ErrorUnsupported:                        "BOLT_UNSUPPORTED",
+	ErrorInterrupted:                        "BOLT_INTERRUPTED",
+	ErrorConnectionReset:                    "BOLT_CONNECTION_RESET",
+	ErrorNoValidAddress:                     "BOLT_NO_VALID_ADDRESS",
+	ErrorTimedOut:                           "BOLT_TIMED_OUT",
+	ErrorPermissionDenied:                   "BOLT_PERMISSION_DENIED",
+	ErrorOutOfFiles:                         "BOLT_OUT_OF_FILES",
+	ErrorOutOfMemory:                        "BOLT_OUT_OF_MEMORY",
+	ErrorOutOfPorts:                         "BOLT_OUT_OF_PORTS",
+	ErrorConnectionRefused:                  "BOLT_CONNECTION_REFUSED",
+	ErrorNetworkUnreachable:                 "BOLT_NETWORK_UNREACHABLE",
+	ErrorTLS:                                "BOLT_TLS_ERROR",
+	ErrorEndOfTransmission:                  "BOLT_END_OF_TRANSMISSION",
+	ErrorServerFailure:                      "BOLT_SERVER_FAILURE",
+	ErrorTransportUnsupported:               "BOLT_TRANSPORT_UNSUPPORTED",
+	ErrorProtocolViolation:                  "BOLT_PROTOCOL_VIOLATION",
+	ErrorProtocolUnsupportedType:            "BOLT_PROTOCOL_UNSUPPORTED_TYPE",
+	ErrorProtocolNotImplementedType:         "BOLT_PROTOCOL_NOT_IMPLEMENTED_TYPE",
+	ErrorProtocolUnexpectedMarker:           "BOLT_PROTOCOL_UNEXPECTED_MARKER",
+	ErrorProtocolUnsupported:                "BOLT_PROTOCOL_UNSUPPORTED",
+	ErrorPoolFull:                           "BOLT_POOL_FULL",
+	ErrorPoolAcquisitionTimedOut:            "BOLT_POOL_ACQUISITION_TIMED_OUT",
+	ErrorAddressNotResolved:                 "BOLT_ADDRESS_NOT_RESOLVED",
+	ErrorRoutingUnableToRetrieveTable:       "BOLT_ROUTING_UNABLE_TO_RETRIEVE_ROUTING_TABLE",
+	ErrorRoutingNoServersToSelect:           "BOLT_ROUTING_NO_SERVERS_TO_SELECT",
+	ErrorRoutingUnableToConstructPool:       "BOLT_ROUTING_UNABLE_TO_CONSTRUCT_POOL_FOR_SERVER",
+	ErrorRoutingUnableToRefreshTable:        "BOLT_ROUTING_UNABLE_TO_REFRESH_ROUTING_TABLE",
+	ErrorRoutingUnexpectedDiscoveryResponse: "BOLT_ROUTING_UNEXPECTED_DISCOVERY_RESPONSE",
+}
+
+// BoltError is the marker interface shared by all errors created through the error
+// factories of a Config
+type BoltError interface {
+	error
+	BoltError() bool
+}
+
+// GenericError represents errors raised by the driver itself
+type GenericError interface {
+	BoltError
+	Message() string
+}
+
+// DatabaseError represents errors returned by the server as FAILURE messages
+type DatabaseError interface {
+	BoltError
+	Classification() string
+	Code() string
+	Message() string
+}
+
+// ConnectorError represents errors raised by the connection layer, i.e. network and
+// protocol failures
+type ConnectorError interface {
+	BoltError
+	State() int
+	Code() int
+	Context() string
+	Description() string
+}
+
+// GenericErrorFactory creates a generic error
+type GenericErrorFactory func(format string, args ...interface{}) GenericError
+
+// ConnectorErrorFactory creates a connector error
+type ConnectorErrorFactory func(state int, code int, codeText, context, description string) ConnectorError
+
+// DatabaseErrorFactory creates a database error
+type DatabaseErrorFactory func(classification, code, message string) DatabaseError
+
+type defaultGenericError struct {
+	message string
+}
+
+type defaultDatabaseError struct {
+	classification string
+	code           string
+	message        string
+}
+
+type defaultConnectorError struct {
+	state       int
+	code        int
+	codeText    string
+	context     string
+	description string
+}
+
+func (failure *defaultGenericError) BoltError() bool {
+	return true
+}
+
+func (failure *defaultGenericError) Message() string {
+	return failure.message
+}
+
+func (failure *defaultGenericError) Error() string {
+	return failure.message
+}
+
+func (failure *defaultDatabaseError) BoltError() bool {
+	return true
+}
+
+func (failure *defaultDatabaseError) Classification() string {
+	return failure.classification
+}
+
+func (failure *defaultDatabaseError) Code() string {
+	return failure.code
+}
+
+func (failure *defaultDatabaseError) Message() string {
+	return failure.message
+}
+
+func (failure *defaultDatabaseError) Error() string {
+	return fmt.Sprintf("database returned error [%s]: %s", failure.code, failure.message)
+}
+
+func (failure *defaultConnectorError) BoltError() bool {
+	return true
+}
+
+func (failure *defaultConnectorError) State() int {
+	return failure.state
+}
+
+func (failure *defaultConnectorError) Code() int {
+	return failure.code
+}
+
+func (failure *defaultConnectorError) Context() string {
+	return failure.context
+}
+
+func (failure *defaultConnectorError) Description() string {
+	return failure.description
+}
+
+func (failure *defaultConnectorError) Error() string {
+	return fmt.Sprintf("%s: error: [%d] %s, state: %d, context: %s", failure.description, failure.code, failure.codeText, failure.state, failure.context)
+}
+
+func newDefaultGenericError(format string, args ...interface{}) GenericError {
+	return &defaultGenericError{message: fmt.Sprintf(format, args...)}
+}
+
+func newDefaultDatabaseError(classification, code, message string) DatabaseError {
+	return &defaultDatabaseError{classification: classification, code: code, message: message}
+}
+
+func newDefaultConnectorError(state int, code int, codeText, context, description string) ConnectorError {
+	return &defaultConnectorError{state: state, code: code, codeText: codeText, context: context, description: description}
+}
+
+// NewValueHandlerError creates an error to be returned from ValueHandler implementations
+func NewValueHandlerError(format string, args ...interface{}) error {
+	return &valueHandlerError{message: fmt.Sprintf(format, args...)}
+}
+
+type valueHandlerError struct {
+	message string
+}
+
+func (failure *valueHandlerError) Error() string {
+	return failure.message
+}
+
+func (config *Config) newGenericError(format string, args ...interface{}) error {
+	if config.GenericErrorFactory != nil {
+		return config.GenericErrorFactory(format, args...)
+	}
+	return newDefaultGenericError(format, args...)
+}
+
+func (config *Config) newConnectorError(state int, code int, context, description string) error {
+	if config.ConnectorErrorFactory != nil {
+		return config.ConnectorErrorFactory(state, code, errorTexts[code], context, description)
+	}
+	return newDefaultConnectorError(state, code, errorTexts[code], context, description)
+}
+
+func (config *Config) newDatabaseError(code, message string) error {
+	classification := classificationOf(code)
+	if config.DatabaseErrorFactory != nil {
+		return config.DatabaseErrorFactory(classification, code, message)
+	}
+	return newDefaultDatabaseError(classification, code, message)
+}
+
+// classificationOf extracts the classification part of a status code of the form
+// Neo.<Classification>.<Category>.<Title>
+func classificationOf(code string) string {
+	parts := strings.Split(code, ".")
+	if len(parts) != 4 {
+		return ""
+	}
+	return parts[1]
+}
+
+// errorCodeOf maps errors raised by the network stack to connector error codes
+func errorCodeOf(err error) int {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return ErrorEndOfTransmission
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return ErrorAddressNotResolved
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorTimedOut
+	}
+
+	var recordErr tls.RecordHeaderError
+	var certErr x509.CertificateInvalidError
+	var hostErr x509.HostnameError
+	var authErr x509.UnknownAuthorityError
+	if errors.As(err, &recordErr) || errors.As(err, &certErr) || errors.As(err, &hostErr) || errors.As(err, &authErr) {
+		return ErrorTLS
+	}
+
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.ECONNREFUSED:
+			return ErrorConnectionRefused
+		case syscall.ENETUNREACH, syscall.EHOSTUNREACH:
+			return ErrorNetworkUnreachable
+		case syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE:
+			return ErrorConnectionReset
+		case syscall.EINTR:
+			return ErrorInterrupted
+		case syscall.EACCES, syscall.EPERM:
+			return ErrorPermissionDenied
+		case syscall.EMFILE, syscall.ENFILE:
+			return ErrorOutOfFiles
+		case syscall.ENOMEM, syscall.ENOBUFS:
+			return ErrorOutOfMemory
+		case syscall.EADDRNOTAVAIL:
+			return ErrorOutOfPorts
+		}
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorConnectionReset
+	}
+
+	var syscallErr *os.SyscallError
+	if errors.As(err, &syscallErr) {
+		return ErrorConnectionReset
+	}
+
+	return ErrorUnknown
+}
+
+func asDatabaseError(err error) (DatabaseError, bool) {
+	var dbErr DatabaseError
+	ok := errors.As(err, &dbErr)
+	return dbErr, ok
+}
+
+func asConnectorError(err error) (ConnectorError, bool) {
+	var connErr ConnectorError
+	ok := errors.As(err, &connErr)
+	return connErr, ok
+}
+
+// IsServiceUnavailable checks whether the provided error is caused by the server or the
+// cluster being (temporarily) unreachable
+func IsServiceUnavailable(err error) bool {
+	if connErr, ok := asConnectorError(err); ok {
+		switch connErr.Code() {
+		case ErrorInterrupted,
+			ErrorConnectionReset,
+			ErrorNoValidAddress,
+			ErrorTimedOut,
+			ErrorConnectionRefused,
+			ErrorNetworkUnreachable,
+			ErrorTLS,
+			ErrorEndOfTransmission,
+			ErrorPoolFull,
+			ErrorAddressNotResolved,
+			ErrorRoutingUnableToRetrieveTable,
+			ErrorRoutingUnableToRefreshTable,
+			ErrorRoutingNoServersToSelect:
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsTransientError checks whether the provided error is a temporary failure reported by
+// the server which may be worked around by retrying
+func IsTransientError(err error) bool {
+	if dbErr, ok := asDatabaseError(err); ok {
+		if dbErr.Classification() == "TransientError" {
+			switch dbErr.Code() {
+			case "Neo.TransientError.Transaction.Terminated", "Neo.TransientError.Transaction.LockClientStopped":
+				return false
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsWriteError checks whether the provided error is caused by sending a write request to
+// a server which no longer accepts writes
+func IsWriteError(err error) bool {
+	if dbErr, ok := asDatabaseError(err); ok {
+		switch dbErr.Code() {
+		case "Neo.ClientError.Cluster.NotALeader", "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase":
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSecurityError checks whether the provided error is caused by a TLS or authentication
+// failure
+func IsSecurityError(err error) bool {
+	if connErr, ok := asConnectorError(err); ok {
+		return connErr.Code() == ErrorTLS || connErr.Code() == ErrorPermissionDenied
+	}
+
+	if dbErr, ok := asDatabaseError(err); ok {
+		return strings.HasPrefix(dbErr.Code(), "Neo.ClientError.Security.")
+	}
+
+	return false
+}
+
+// IsAuthenticationError checks whether the provided error is caused by invalid credentials
+func IsAuthenticationError(err error) bool {
+	if connErr, ok := asConnectorError(err); ok {
+		return connErr.Code() == ErrorPermissionDenied
+	}
+
+	if dbErr, ok := asDatabaseError(err); ok {
+		return dbErr.Code() == "Neo.ClientError.Security.Unauthorized"
+	}
+
+	return false
+}
+
+// IsClientError checks whether the provided error is caused by the client carrying out an
+// invalid operation
+func IsClientError(err error) bool {
+	if dbErr, ok := asDatabaseError(err); ok {
+		return dbErr.Classification() == "ClientError" && !strings.HasPrefix(dbErr.Code(), "Neo.ClientError.Security.")
+	}
+
+	var genericErr GenericError
+	if errors.As(err, &genericErr) {
+		return true
+	}
+
+	return false
+}
+
+// IsSessionExpired checks whether the provided error is caused by the connection no longer
+// satisfying the criteria under which it was acquired
+func IsSessionExpired(err error) bool {
+	if connErr, ok := asConnectorError(err); ok {
+		return connErr.Code() == ErrorRoutingNoServersToSelect
+	}
+
+	return false
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+// LogLevel is the severity of a log message, the values match the levels of the driver
+type LogLevel int
+
+const (
+	LogLevelError LogLevel = iota + 1
+	LogLevelWarning
+	LogLevelInfo
+	LogLevelDebug
+)
+
+// Keys of the fields that describe the context of a log message, they match the keys of
+// the driver
+const (
+	LogFieldConnectionId  = "connection_id"
+	LogFieldServerAddress = "server_address"
+	LogFieldDatabase      = "database"
+	LogFieldError         = "error"
+)
+
+// LogField is a key/value pair that describes the context of a log message
+type LogField struct {
+	Key   string
+	Value interface{}
+}
+
+// Logger is the interface that the connector sends its log outputs to
+type Logger interface {
+	// Enabled returns whether messages of the given level are logged, messages of disabled
+	// levels are not built at all
+	Enabled(level LogLevel) bool
+	// Log logs the message along with the fields that describe its context
+	Log(level LogLevel, message string, fields []LogField)
+}
+
+func (config *Config) log(level LogLevel, message string, fields []LogField) {
+	if config.Log != nil && config.Log.Enabled(level) {
+		config.Log.Log(level, message, fields)
+	}
+}
+
+func (config *Config) debug(message string, fields ...LogField) {
+	config.log(LogLevelDebug, message, fields)
+}
+
+func (config *Config) info(message string, fields ...LogField) {
+	config.log(LogLevelInfo, message, fields)
+}
+
+func (config *Config) warning(message string, fields ...LogField) {
+	config.log(LogLevelWarning, message, fields)
+}
+
+func (config *Config) error(message string, fields ...LogField) {
+	config.log(LogLevelError, message, fields)
+}
+
+func connectionField(id string) LogField {
+	return LogField{Key: LogFieldConnectionId, Value: id}
+}
+
+func addressField(address string) LogField {
+	return LogField{Key: LogFieldServerAddress, Value: address}
+}
+
+func databaseField(database string) LogField {
+	return LogField{Key: LogFieldDatabase, Value: database}
+}
+
+func errorField(err error) LogField {
+	return LogField{Key: LogFieldError, Value: err}
+}
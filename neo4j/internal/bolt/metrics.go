@@ -0,0 +1,119 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"time"
+)
+
+// acquisitionLatencyBounds are the upper bounds of the buckets acquisition latencies are
+// counted in, latencies above the last bound fall into an additional bucket
+var acquisitionLatencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// PoolMetrics is a snapshot of the connection pool of a single server
+type PoolMetrics struct {
+	Address string
+	// InUse is the number of connections that are handed out
+	InUse int
+	// Idle is the number of connections that wait in the pool for being acquired
+	Idle int
+	// Created is the number of connections that were opened
+	Created int64
+	// Closed is the number of connections that were closed
+	Closed int64
+	// FailedToCreate is the number of connections that could not be opened
+	FailedToCreate int64
+	// AcquisitionTimeouts is the number of acquisitions that failed because the pool was full
+	// for longer than the acquisition timeout
+	AcquisitionTimeouts int64
+	// AcquisitionLatency counts the time successful acquisitions took
+	AcquisitionLatency Histogram
+}
+
+// Histogram counts durations in buckets
+type Histogram struct {
+	// Bounds are the inclusive upper bounds of the buckets in ascending order
+	Bounds []time.Duration
+	// Counts holds the number of durations per bucket, it has one more element than Bounds
+	// for the durations above the last bound
+	Counts []int64
+	// Count is the total number of durations
+	Count int64
+	// Sum is the total of all durations
+	Sum time.Duration
+}
+
+// histogram accumulates a Histogram with fixed bounds
+type histogram struct {
+	bounds []time.Duration
+	counts []int64
+	count  int64
+	sum    time.Duration
+}
+
+func newHistogram(bounds []time.Duration) histogram {
+	return histogram{bounds: bounds, counts: make([]int64, len(bounds)+1)}
+}
+
+func (h *histogram) observe(duration time.Duration) {
+	bucket := len(h.bounds)
+	for i, bound := range h.bounds {
+		if duration <= bound {
+			bucket = i
+			break
+		}
+	}
+
+	h.counts[bucket]++
+	h.count++
+	h.sum += duration
+}
+
+// snapshot returns a copy of the histogram that is not affected by later observations
+func (h *histogram) snapshot() Histogram {
+	return Histogram{
+		Bounds: h.bounds,
+		Counts: append([]int64(nil), h.counts...),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
+
+// poolStats are the counters of a pool, they are guarded by the mutex of the pool
+type poolStats struct {
+	created             int64
+	closed              int64
+	failedToCreate      int64
+	acquisitionTimeouts int64
+	acquisitionLatency  histogram
+}
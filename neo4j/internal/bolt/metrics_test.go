@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("should count durations in the bucket of their upper bound", func(t *testing.T) {
+		h := newHistogram([]time.Duration{time.Millisecond, time.Second})
+
+		h.observe(time.Millisecond)
+		h.observe(2 * time.Millisecond)
+		h.observe(time.Minute)
+
+		snapshot := h.snapshot()
+		assert.Equal(t, []int64{1, 1, 1}, snapshot.Counts)
+		assert.Equal(t, int64(3), snapshot.Count)
+		assert.Equal(t, time.Minute+3*time.Millisecond, snapshot.Sum)
+	})
+
+	t.Run("should not change snapshots with later durations", func(t *testing.T) {
+		h := newHistogram([]time.Duration{time.Millisecond})
+		snapshot := h.snapshot()
+
+		h.observe(time.Microsecond)
+
+		assert.Equal(t, []int64{0, 0}, snapshot.Counts)
+		assert.Equal(t, int64(0), snapshot.Count)
+	})
+}
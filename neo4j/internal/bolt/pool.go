@@ -0,0 +1,476 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// pool keeps the connections opened to a single server address. Acquirers that find the pool
+// exhausted wait in line and are served in the order they arrived.
+type pool struct {
+	address   string
+	config    *Config
+	values    *valueSystem
+	authToken map[string]interface{}
+	dial      dialer
+	// onFailure is called with the errors of the connections of the pool, it must not block
+	onFailure func(connection *boltConnection, err error)
+
+	mutex sync.Mutex
+	// idle connections are ordered by the time they were released, most recent last
+	idle    []*boltConnection
+	total   int
+	latency time.Duration
+	closed  bool
+	// waiters receive a released connection, or nil when they may open a new connection in
+	// place of one that was closed
+	waiters []chan *boltConnection
+	stop    chan struct{}
+	stats   poolStats
+}
+
+func newPool(address string, authToken map[string]interface{}, config *Config, values *valueSystem, dial dialer) *pool {
+	p := &pool{
+		address:   address,
+		config:    config,
+		values:    values,
+		authToken: authToken,
+		dial:      dial,
+		stop:      make(chan struct{}),
+		stats:     poolStats{acquisitionLatency: newHistogram(acquisitionLatencyBounds)},
+	}
+
+	if config.MinIdleConns > 0 || config.MaxConnIdleTime > 0 {
+		go p.maintain()
+	}
+
+	return p
+}
+
+// acquire hands out an idle connection or opens a new one, waiting in line for a connection
+// to be released when the pool is full. Waiting stops early when ctx is done.
+func (p *pool) acquire(ctx context.Context, mode AccessMode, database string) (*boltConnection, error) {
+	start := time.Now()
+	connection, err := p.acquireOrWait(ctx, mode, database)
+	p.recordAcquisition(time.Since(start), err)
+
+	return connection, err
+}
+
+func (p *pool) acquireOrWait(ctx context.Context, mode AccessMode, database string) (*boltConnection, error) {
+	timeout := p.config.ConnAcquisitionTimeout
+	deadline := time.Now().Add(timeout)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		p.mutex.Lock()
+		if p.closed {
+			p.mutex.Unlock()
+			return nil, p.config.newGenericError("connection pool for %s is closed", p.address)
+		}
+
+		// acquirers do not pass those already waiting in line
+		var expired []*boltConnection
+		if len(p.waiters) == 0 {
+			var connection *boltConnection
+			connection, expired = p.takeIdle()
+			if connection != nil {
+				p.mutex.Unlock()
+				p.destroyAll(expired)
+				if connection = p.checkLiveness(connection); connection == nil {
+					continue
+				}
+				return p.handOut(connection, mode, database), nil
+			}
+
+			if p.config.MaxPoolSize <= 0 || p.total < p.config.MaxPoolSize {
+				p.total++
+				p.mutex.Unlock()
+				p.destroyAll(expired)
+				return p.open(mode, database)
+			}
+		}
+
+		if timeout == 0 {
+			total := p.total
+			p.mutex.Unlock()
+			p.destroyAll(expired)
+			return nil, p.config.newConnectorError(StateDisconnected, ErrorPoolFull, fmt.Sprintf("all %d connections to %s are in use", total, p.address), "unable to acquire connection from the pool")
+		}
+
+		waiter := make(chan *boltConnection, 1)
+		p.waiters = append(p.waiters, waiter)
+		p.mutex.Unlock()
+		p.destroyAll(expired)
+
+		connection, handedOver, err := p.wait(ctx, waiter, timeout, deadline)
+		if err != nil {
+			return nil, err
+		}
+
+		if !handedOver {
+			return p.open(mode, database)
+		}
+		if connection = p.checkLiveness(connection); connection != nil {
+			return p.handOut(connection, mode, database), nil
+		}
+	}
+}
+
+// wait waits for the waiter to be served, which hands over a released connection or the
+// permission to open a new one. A waiter that gives up passes on what it was served since.
+func (p *pool) wait(ctx context.Context, waiter chan *boltConnection, timeout time.Duration, deadline time.Time) (*boltConnection, bool, error) {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	var err error
+	select {
+	case connection, ok := <-waiter:
+		if !ok {
+			return nil, false, p.config.newGenericError("connection pool for %s is closed", p.address)
+		}
+		return connection, connection != nil, nil
+	case <-expired:
+		err = p.config.newConnectorError(StateDisconnected, ErrorPoolAcquisitionTimedOut, fmt.Sprintf("no connection to %s was released within %v", p.address, timeout), "unable to acquire connection from the pool")
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	p.mutex.Lock()
+	for i, queued := range p.waiters {
+		if queued == waiter {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			p.mutex.Unlock()
+			return nil, false, err
+		}
+	}
+
+	// the waiter was served in the meantime
+	connection, ok := <-waiter
+	switch {
+	case !ok:
+	case connection == nil:
+		p.closedLocked()
+	case p.closed:
+		p.total--
+		p.stats.closed++
+		p.mutex.Unlock()
+		_ = connection.destroy()
+		return nil, false, err
+	default:
+		p.idleLocked(connection)
+	}
+	p.mutex.Unlock()
+
+	return nil, false, err
+}
+
+// open opens a new connection in a slot that has been counted in total already
+func (p *pool) open(mode AccessMode, database string) (*boltConnection, error) {
+	start := time.Now()
+	connection, err := connect(p.dial, p.address, mode, p.authToken, p.config, p.values)
+	if err != nil {
+		p.mutex.Lock()
+		p.stats.failedToCreate++
+		p.closedLocked()
+		p.mutex.Unlock()
+		return nil, err
+	}
+	p.recordOpened(time.Since(start))
+
+	connection.pool = p
+	connection.database = database
+	return connection, nil
+}
+
+func (p *pool) handOut(connection *boltConnection, mode AccessMode, database string) *boltConnection {
+	connection.mode = mode
+	connection.database = database
+	return connection
+}
+
+// checkLiveness probes connections that have been idle for longer than the liveness check
+// timeout, it returns nil when the connection turned out to be broken
+func (p *pool) checkLiveness(connection *boltConnection) *boltConnection {
+	threshold := p.config.ConnLivenessCheckTimeout
+	if threshold <= 0 || time.Since(connection.idleSince) <= threshold {
+		return connection
+	}
+
+	if err := connection.probe(); err != nil {
+		p.config.warning("connection failed the liveness check", connectionField(connection.id), addressField(p.address), errorField(err))
+		p.mutex.Lock()
+		p.stats.closed++
+		p.closedLocked()
+		p.mutex.Unlock()
+		_ = connection.destroy()
+		return nil
+	}
+
+	return connection
+}
+
+// takeIdle returns the most recently released idle connection, connections that outlived
+// their lifetime are taken out of the pool and returned for being closed, must be called with
+// the mutex held
+func (p *pool) takeIdle() (*boltConnection, []*boltConnection) {
+	var expired []*boltConnection
+	for len(p.idle) > 0 {
+		connection := p.idle[len(p.idle)-1]
+		p.idle = p.idle[:len(p.idle)-1]
+
+		if p.hasExpired(connection) {
+			p.total--
+			p.stats.closed++
+			expired = append(expired, connection)
+			continue
+		}
+
+		return connection, expired
+	}
+
+	return nil, expired
+}
+
+// release takes a connection back into the pool, connections that can not be reused are
+// closed instead
+func (p *pool) release(connection *boltConnection) error {
+	reusable := connection.state != StateDefunct && !p.hasExpired(connection)
+	if reusable && connection.needsReset() {
+		if err := connection.reset(); err != nil {
+			p.config.warning("unable to reset connection", connectionField(connection.id), addressField(p.address), errorField(err))
+			reusable = false
+		}
+	}
+
+	p.mutex.Lock()
+	if p.closed || !reusable {
+		p.stats.closed++
+		p.closedLocked()
+		p.mutex.Unlock()
+		return connection.destroy()
+	}
+
+	connection.boltLogger = nil
+	p.idleLocked(connection)
+	p.mutex.Unlock()
+	return nil
+}
+
+// idleLocked hands a released connection over to the first waiter or keeps it as idle, must
+// be called with the mutex held
+func (p *pool) idleLocked(connection *boltConnection) {
+	connection.idleSince = time.Now()
+
+	if len(p.waiters) > 0 {
+		waiter := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		waiter <- connection
+		return
+	}
+
+	p.idle = append(p.idle, connection)
+}
+
+// closedLocked frees the slot of a connection that was closed or could not be opened, the
+// first waiter takes the slot over, must be called with the mutex held
+func (p *pool) closedLocked() {
+	if len(p.waiters) > 0 && !p.closed {
+		waiter := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		waiter <- nil
+		return
+	}
+
+	p.total--
+}
+
+// warmUp opens connections until the pool holds the configured minimum of idle connections,
+// the same minimum the eviction of idle connections keeps, connections in use do not count
+func (p *pool) warmUp() error {
+	for {
+		p.mutex.Lock()
+		if p.closed || len(p.idle) >= p.config.MinIdleConns || (p.config.MaxPoolSize > 0 && p.total >= p.config.MaxPoolSize) {
+			p.mutex.Unlock()
+			return nil
+		}
+		p.total++
+		p.mutex.Unlock()
+
+		connection, err := p.open(AccessModeWrite, "")
+		if err != nil {
+			return err
+		}
+		if err = p.release(connection); err != nil {
+			return err
+		}
+	}
+}
+
+// maintain opens the configured minimum of idle connections and periodically closes the
+// connections beyond it that have been idle for longer than the maximum idle time, until the
+// pool is closed
+func (p *pool) maintain() {
+	if err := p.warmUp(); err != nil {
+		p.config.warning("unable to open idle connections", addressField(p.address), errorField(err))
+	}
+
+	if p.config.MaxConnIdleTime <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(p.config.MaxConnIdleTime / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		p.mutex.Lock()
+		var evicted []*boltConnection
+		for len(p.idle) > p.config.MinIdleConns {
+			connection := p.idle[0]
+			if time.Since(connection.idleSince) <= p.config.MaxConnIdleTime && !p.hasExpired(connection) {
+				break
+			}
+
+			p.idle = p.idle[1:]
+			p.total--
+			p.stats.closed++
+			evicted = append(evicted, connection)
+		}
+		p.mutex.Unlock()
+
+		if len(evicted) > 0 {
+			p.config.debug("closing idle connections", addressField(p.address), LogField{Key: "count", Value: len(evicted)})
+			p.destroyAll(evicted)
+		}
+
+		if err := p.warmUp(); err != nil {
+			p.config.warning("unable to open idle connections", addressField(p.address), errorField(err))
+		}
+	}
+}
+
+// load returns the current load of the server of the pool
+func (p *pool) load() ServerLoad {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return ServerLoad{Address: p.address, InUse: p.total - len(p.idle), Latency: p.latency}
+}
+
+// metrics returns a snapshot of the connections and the counters of the pool
+func (p *pool) metrics() PoolMetrics {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return PoolMetrics{
+		Address:             p.address,
+		InUse:               p.total - len(p.idle),
+		Idle:                len(p.idle),
+		Created:             p.stats.created,
+		Closed:              p.stats.closed,
+		FailedToCreate:      p.stats.failedToCreate,
+		AcquisitionTimeouts: p.stats.acquisitionTimeouts,
+		AcquisitionLatency:  p.stats.acquisitionLatency.snapshot(),
+	}
+}
+
+// recordAcquisition counts the outcome of an acquisition that took the given time
+func (p *pool) recordAcquisition(latency time.Duration, err error) {
+	timedOut := false
+	if connErr, ok := asConnectorError(err); ok {
+		timedOut = connErr.Code() == ErrorPoolAcquisitionTimedOut || connErr.Code() == ErrorPoolFull
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	switch {
+	case err == nil:
+		p.stats.acquisitionLatency.observe(latency)
+	case timedOut:
+		p.stats.acquisitionTimeouts++
+	}
+}
+
+// recordOpened counts a new connection and adds the time it took to establish it to the
+// moving average of the latency of the server
+func (p *pool) recordOpened(latency time.Duration) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.stats.created++
+	if p.latency == 0 {
+		p.latency = latency
+	} else {
+		p.latency = (3*p.latency + latency) / 4
+	}
+}
+
+func (p *pool) close() error {
+	p.mutex.Lock()
+	if p.closed {
+		p.mutex.Unlock()
+		return nil
+	}
+
+	idle := p.idle
+	p.idle = nil
+	p.total -= len(idle)
+	p.stats.closed += int64(len(idle))
+	p.closed = true
+	for _, waiter := range p.waiters {
+		close(waiter)
+	}
+	p.waiters = nil
+	close(p.stop)
+	p.mutex.Unlock()
+
+	p.destroyAll(idle)
+	return nil
+}
+
+func (p *pool) hasExpired(connection *boltConnection) bool {
+	return p.config.MaxConnLifetime > 0 && time.Since(connection.createdAt) > p.config.MaxConnLifetime
+}
+
+func (p *pool) destroyAll(connections []*boltConnection) {
+	for _, connection := range connections {
+		if err := connection.destroy(); err != nil {
+			p.config.warning("unable to close connection", connectionField(connection.id), addressField(p.address), errorField(err))
+		}
+	}
+}
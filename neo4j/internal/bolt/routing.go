@@ -0,0 +1,647 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/utils"
+)
+
+var (
+	routingProcedureVersion = utils.VersionOf("3.2.0")
+	multiDatabaseVersion    = utils.VersionOf("4.0.0")
+)
+
+// systemDatabase is the database routing tables are retrieved from on servers that host
+// multiple databases
+const systemDatabase = "system"
+
+// routingTable holds the members of a cluster, grouped by role
+type routingTable struct {
+	routers []string
+	readers []string
+	writers []string
+	expires time.Time
+}
+
+// isStale checks whether the table needs to be refreshed before serving the given mode
+func (table *routingTable) isStale(mode AccessMode) bool {
+	if table == nil || time.Now().After(table.expires) || len(table.routers) == 0 {
+		return true
+	}
+
+	if mode == AccessModeRead {
+		return len(table.readers) == 0
+	}
+
+	return len(table.writers) == 0
+}
+
+func (table *routingTable) servers() map[string]bool {
+	servers := make(map[string]bool)
+	for _, group := range [][]string{table.routers, table.readers, table.writers} {
+		for _, server := range group {
+			servers[server] = true
+		}
+	}
+	return servers
+}
+
+// tableRefresh is a routing table fetch in progress, callers that need the same table while
+// it is fetched wait for done instead of fetching it again
+type tableRefresh struct {
+	done  chan struct{}
+	table *routingTable
+	err   error
+	// canceled is set when the fetch stopped because the context of its caller was done
+	canceled bool
+}
+
+// serverFailure records a server that failed while one of its connections was in use
+type serverFailure struct {
+	address string
+	// database and writer are set when the server only stopped accepting writes for a database
+	database string
+	writer   bool
+}
+
+type routingConnector struct {
+	target    *url.URL
+	context   map[string]string
+	authToken map[string]interface{}
+	config    *Config
+	values    *valueSystem
+	dial      dialer
+
+	mutex sync.Mutex
+	// tables holds a routing table per database, the default database is keyed by ""
+	tables    map[string]*routingTable
+	refreshes map[string]*tableRefresh
+	pools     map[string]*pool
+	closed    bool
+
+	// failures are reported by connections while the mutex may be held, so they are collected
+	// separately and applied to the routing tables before the next server is selected
+	failureMutex sync.Mutex
+	failures     []serverFailure
+}
+
+func newRoutingConnector(target *url.URL, context map[string]string, authToken map[string]interface{}, config *Config, values *valueSystem, dial dialer) *routingConnector {
+	return &routingConnector{
+		target:    target,
+		context:   context,
+		authToken: authToken,
+		config:    config,
+		values:    values,
+		dial:      dial,
+		tables:    make(map[string]*routingTable),
+		refreshes: make(map[string]*tableRefresh),
+		pools:     make(map[string]*pool),
+	}
+}
+
+// Acquire hands out a connection to a server of the given database for the given mode. Every
+// server is tried at most once, so that a router that keeps advertising unreachable servers
+// does not keep the caller waiting forever.
+func (connector *routingConnector) Acquire(ctx context.Context, mode AccessMode, database string) (Connection, error) {
+	tried := make(map[string]bool)
+	for {
+		address, err := connector.selectServer(ctx, mode, database)
+		if err != nil {
+			return nil, err
+		}
+
+		if tried[address] {
+			return nil, connector.config.newConnectorError(StateDisconnected, ErrorRoutingNoServersToSelect, fmt.Sprintf("tried servers %v of %s", keys(tried), connector.describe(database)), "all servers for the requested access mode are unreachable")
+		}
+		tried[address] = true
+
+		connection, err := connector.poolFor(address).acquire(ctx, mode, database)
+		if err != nil {
+			if !IsServiceUnavailable(err) {
+				return nil, err
+			}
+
+			// the server is no longer reachable, leave it out and pick another one
+			connector.config.warning("unable to acquire connection, removing the server from the routing table", addressField(address), errorField(err))
+			connector.forget(address)
+			continue
+		}
+
+		return connection, nil
+	}
+}
+
+// VerifyConnectivity retrieves the routing table of the default database from the initial
+// routers, it returns the error of the last router when none of them could provide one
+func (connector *routingConnector) VerifyConnectivity(ctx context.Context) error {
+	connector.mutex.Lock()
+	closed := connector.closed
+	connector.mutex.Unlock()
+
+	if closed {
+		return connector.config.newGenericError("routing connector for %s is closed", connector.target.Host)
+	}
+
+	routers := connector.initialAddresses()
+	if len(routers) == 0 {
+		return connector.config.newConnectorError(StateDisconnected, ErrorNoValidAddress, fmt.Sprintf("no addresses resolved for %s", connector.target.Host), "unable to retrieve routing table")
+	}
+
+	var err error
+	for _, router := range routers {
+		var table *routingTable
+		if table, err = connector.fetchTable(ctx, router, ""); err == nil {
+			connector.mutex.Lock()
+			connector.store("", table)
+			connector.mutex.Unlock()
+			return nil
+		}
+		connector.config.warning("unable to retrieve routing table", addressField(router), errorField(err))
+	}
+	return err
+}
+
+// warmUp retrieves the routing table of the default database, which opens the configured
+// minimum of idle connections to its members
+func (connector *routingConnector) warmUp() {
+	connector.mutex.Lock()
+	_, ok := connector.tables[""]
+	closed := connector.closed
+	connector.mutex.Unlock()
+
+	if ok || closed {
+		return
+	}
+
+	if _, err := connector.refresh(context.Background(), ""); err != nil {
+		connector.config.warning("unable to warm up connections", addressField(connector.target.Host), errorField(err))
+	}
+}
+
+// Metrics returns the metrics of the pools of the current cluster members ordered by address,
+// the counters of a member start over when it rejoins the cluster after being removed
+func (connector *routingConnector) Metrics() []PoolMetrics {
+	connector.mutex.Lock()
+	defer connector.mutex.Unlock()
+
+	metrics := make([]PoolMetrics, 0, len(connector.pools))
+	for _, pool := range connector.pools {
+		metrics = append(metrics, pool.metrics())
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Address < metrics[j].Address
+	})
+
+	return metrics
+}
+
+func (connector *routingConnector) Close() error {
+	connector.mutex.Lock()
+	pools := connector.pools
+	connector.pools = make(map[string]*pool)
+	connector.closed = true
+	connector.mutex.Unlock()
+
+	for _, pool := range pools {
+		_ = pool.close()
+	}
+
+	return nil
+}
+
+// selectServer picks a server of the given database for the given mode using the configured
+// load balancer, refreshing the routing table when required. The mutex is not held while the
+// routing table is refreshed, so that other databases and modes are served in the meantime.
+func (connector *routingConnector) selectServer(ctx context.Context, mode AccessMode, database string) (string, error) {
+	connector.mutex.Lock()
+	if connector.closed {
+		connector.mutex.Unlock()
+		return "", connector.config.newGenericError("routing connector for %s is closed", connector.target.Host)
+	}
+
+	connector.applyFailures()
+
+	table := connector.tables[database]
+	if table.isStale(mode) {
+		connector.mutex.Unlock()
+
+		var err error
+		if table, err = connector.refresh(ctx, database); err != nil {
+			return "", err
+		}
+
+		connector.mutex.Lock()
+		if connector.closed {
+			connector.mutex.Unlock()
+			return "", connector.config.newGenericError("routing connector for %s is closed", connector.target.Host)
+		}
+	}
+	defer connector.mutex.Unlock()
+
+	servers := table.writers
+	if mode == AccessModeRead {
+		servers = table.readers
+	}
+
+	if len(servers) == 0 {
+		return "", connector.config.newConnectorError(StateDisconnected, ErrorRoutingNoServersToSelect, fmt.Sprintf("routing table of %s has no servers for the requested access mode", connector.describe(database)), "no servers to select")
+	}
+
+	loads := make([]ServerLoad, len(servers))
+	for i, server := range servers {
+		loads[i] = connector.loadOf(server)
+	}
+
+	selected := connector.config.LoadBalancer(mode, loads)
+	if selected < 0 || selected >= len(servers) {
+		return "", connector.config.newGenericError("load balancer selected server %d out of %d candidates", selected, len(servers))
+	}
+	return servers[selected], nil
+}
+
+// loadOf returns the load of the given server, which is empty when no connection was opened
+// to it yet, must be called with the mutex held
+func (connector *routingConnector) loadOf(server string) ServerLoad {
+	if pool, ok := connector.pools[server]; ok {
+		return pool.load()
+	}
+	return ServerLoad{Address: server}
+}
+
+// refresh fetches a new routing table of the given database and stores it, callers that
+// refresh the same database concurrently share a single fetch. It must be called without the
+// mutex held, which is only taken to look up the routers and to store the new table.
+func (connector *routingConnector) refresh(ctx context.Context, database string) (*routingTable, error) {
+	for {
+		connector.mutex.Lock()
+		pending, ok := connector.refreshes[database]
+		if !ok {
+			break
+		}
+		connector.mutex.Unlock()
+
+		select {
+		case <-pending.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+
+		// a fetch given up by its caller is retried with the context of this one
+		if !pending.canceled {
+			return pending.table, pending.err
+		}
+	}
+
+	pending := &tableRefresh{done: make(chan struct{})}
+	connector.refreshes[database] = pending
+	current, known := connector.tables[database]
+	var routers []string
+	if known {
+		routers = append(routers, current.routers...)
+	}
+	connector.mutex.Unlock()
+
+	pending.table, pending.err = connector.fetchFirst(ctx, database, routers, known)
+	pending.canceled = pending.err != nil && ctx.Err() != nil
+
+	connector.mutex.Lock()
+	delete(connector.refreshes, database)
+	if pending.err == nil && !connector.closed {
+		connector.store(database, pending.table)
+	}
+	connector.mutex.Unlock()
+	close(pending.done)
+
+	return pending.table, pending.err
+}
+
+// fetchFirst returns the routing table of the given database from the first of the given
+// routers that provides one, falling back to the initial address
+func (connector *routingConnector) fetchFirst(ctx context.Context, database string, routers []string, known bool) (*routingTable, error) {
+	for _, address := range connector.initialAddresses() {
+		if !contains(routers, address) {
+			routers = append(routers, address)
+		}
+	}
+
+	for _, router := range routers {
+		table, err := connector.fetchTable(ctx, router, database)
+		if err != nil {
+			if failure, ok := asConnectorError(err); ok && failure.Code() == ErrorProtocolUnsupported {
+				return nil, err
+			}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			connector.config.warning("unable to retrieve routing table", addressField(router), errorField(err))
+			continue
+		}
+
+		return table, nil
+	}
+
+	if !known {
+		return nil, connector.config.newConnectorError(StateDisconnected, ErrorRoutingUnableToRetrieveTable, fmt.Sprintf("tried routers %v", routers), "unable to retrieve routing table")
+	}
+	return nil, connector.config.newConnectorError(StateDisconnected, ErrorRoutingUnableToRefreshTable, fmt.Sprintf("tried routers %v", routers), "unable to refresh routing table")
+}
+
+// store replaces the routing table of the given database, must be called with the mutex held
+func (connector *routingConnector) store(database string, table *routingTable) {
+	connector.config.debug("routing table updated", addressField(connector.target.Host), databaseField(database),
+		LogField{Key: "routers", Value: table.routers}, LogField{Key: "readers", Value: table.readers}, LogField{Key: "writers", Value: table.writers})
+	connector.tables[database] = table
+	connector.purge()
+
+	if connector.config.MinIdleConns > 0 {
+		// opening the pools of new members warms them up
+		for _, servers := range [][]string{table.readers, table.writers} {
+			for _, address := range servers {
+				connector.poolForLocked(address)
+			}
+		}
+	}
+}
+
+// describe names the cluster and the database for messages
+func (connector *routingConnector) describe(database string) string {
+	if database == "" {
+		return connector.target.Host
+	}
+	return fmt.Sprintf("%s (database %s)", connector.target.Host, database)
+}
+
+func (connector *routingConnector) initialAddresses() []string {
+	if connector.config.AddressResolver == nil {
+		return []string{addressOf(connector.target)}
+	}
+
+	var addresses []string
+	for _, resolved := range connector.config.AddressResolver(connector.target) {
+		addresses = append(addresses, addressOf(resolved))
+	}
+	return addresses
+}
+
+// fetchTable calls the routing procedure for the given database on the given router
+func (connector *routingConnector) fetchTable(ctx context.Context, router string, database string) (*routingTable, error) {
+	connection, err := connector.poolFor(router).acquire(ctx, AccessModeWrite, "")
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	routingContext := make(map[string]interface{}, len(connector.context))
+	for key, value := range connector.context {
+		routingContext[key] = value
+	}
+
+	version := utils.VersionOf(connection.server)
+	statement, params := "CALL dbms.cluster.routing.getServers", map[string]interface{}{}
+	switch {
+	case version.GreaterThanOrEqual(multiDatabaseVersion):
+		var name interface{}
+		if database != "" {
+			name = database
+		}
+		statement, params = "CALL dbms.routing.getRoutingTable($context, $database)", map[string]interface{}{"context": routingContext, "database": name}
+		connection.database = systemDatabase
+	case database != "":
+		return nil, connector.config.newConnectorError(StateDisconnected, ErrorProtocolUnsupported, fmt.Sprintf("server %s", connection.server), "database selection is not supported by the server")
+	case version.GreaterThanOrEqual(routingProcedureVersion):
+		var value interface{}
+		if len(routingContext) > 0 {
+			value = routingContext
+		}
+		statement, params = "CALL dbms.cluster.routing.getRoutingTable($context)", map[string]interface{}{"context": value}
+	}
+
+	if _, err = connection.Run(statement, params, nil, 0, nil); err != nil {
+		return nil, err
+	}
+	pullHandle, err := connection.PullAll()
+	if err != nil {
+		return nil, err
+	}
+	if err = connection.Flush(); err != nil {
+		return nil, err
+	}
+
+	var record []interface{}
+	for {
+		fetched, err := connection.Fetch(pullHandle)
+		if err != nil {
+			return nil, err
+		}
+		if fetched != FetchTypeRecord {
+			break
+		}
+		if record == nil {
+			record = connection.data
+		}
+	}
+
+	table, err := parseRoutingTable(record)
+	if err != nil {
+		return nil, connector.config.newConnectorError(StateDisconnected, ErrorRoutingUnexpectedDiscoveryResponse, err.Error(), fmt.Sprintf("unexpected routing table received from %s", router))
+	}
+	return table, nil
+}
+
+func parseRoutingTable(record []interface{}) (*routingTable, error) {
+	if len(record) != 2 {
+		return nil, fmt.Errorf("expected a record with ttl and servers but found %v", record)
+	}
+
+	ttl, ok := record[0].(int64)
+	if !ok {
+		return nil, fmt.Errorf("expected ttl to be an integer but found %T", record[0])
+	}
+
+	servers, ok := record[1].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected servers to be a list but found %T", record[1])
+	}
+
+	table := &routingTable{expires: time.Now().Add(time.Duration(ttl) * time.Second)}
+	for _, item := range servers {
+		server, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected server to be a map but found %T", item)
+		}
+
+		rawAddresses, _ := server["addresses"].([]interface{})
+		addresses := make([]string, 0, len(rawAddresses))
+		for _, address := range rawAddresses {
+			if text, ok := address.(string); ok {
+				addresses = append(addresses, text)
+			}
+		}
+
+		switch server["role"] {
+		case "ROUTE":
+			table.routers = append(table.routers, addresses...)
+		case "READ":
+			table.readers = append(table.readers, addresses...)
+		case "WRITE":
+			table.writers = append(table.writers, addresses...)
+		default:
+			return nil, fmt.Errorf("unexpected server role %v", server["role"])
+		}
+	}
+
+	if len(table.routers) == 0 || len(table.readers) == 0 {
+		return nil, fmt.Errorf("routing table has no routers or readers")
+	}
+
+	return table, nil
+}
+
+// forget removes the given server from all routing tables and closes its pool
+func (connector *routingConnector) forget(address string) {
+	connector.mutex.Lock()
+	defer connector.mutex.Unlock()
+
+	connector.forgetLocked(address)
+}
+
+func (connector *routingConnector) forgetLocked(address string) {
+	for _, table := range connector.tables {
+		table.routers = remove(table.routers, address)
+		table.readers = remove(table.readers, address)
+		table.writers = remove(table.writers, address)
+	}
+
+	if pool, ok := connector.pools[address]; ok {
+		delete(connector.pools, address)
+		_ = pool.close()
+	}
+}
+
+// onFailure records servers that are unreachable or no longer accept writes, it is called by
+// connections of the pools of the connector
+func (connector *routingConnector) onFailure(connection *boltConnection, err error) {
+	var failure serverFailure
+	switch {
+	case IsWriteError(err):
+		failure = serverFailure{address: connection.address, database: connection.database, writer: true}
+	case IsServiceUnavailable(err):
+		failure = serverFailure{address: connection.address}
+	default:
+		return
+	}
+
+	connector.failureMutex.Lock()
+	connector.failures = append(connector.failures, failure)
+	connector.failureMutex.Unlock()
+}
+
+// applyFailures takes the servers that failed out of the routing tables, must be called with
+// the mutex held
+func (connector *routingConnector) applyFailures() {
+	connector.failureMutex.Lock()
+	failures := connector.failures
+	connector.failures = nil
+	connector.failureMutex.Unlock()
+
+	for _, failure := range failures {
+		if !failure.writer {
+			connector.config.warning("connection failed, removing the server from the routing tables", addressField(failure.address))
+			connector.forgetLocked(failure.address)
+			continue
+		}
+
+		if table, ok := connector.tables[failure.database]; ok {
+			connector.config.warning("server no longer accepts writes, removing it from the writers", addressField(failure.address), databaseField(failure.database))
+			table.writers = remove(table.writers, failure.address)
+		}
+	}
+}
+
+// purge closes the pools of servers that are no longer part of any routing table, must be
+// called with the mutex held
+func (connector *routingConnector) purge() {
+	servers := make(map[string]bool)
+	for _, table := range connector.tables {
+		for server := range table.servers() {
+			servers[server] = true
+		}
+	}
+	for address, pool := range connector.pools {
+		if !servers[address] {
+			delete(connector.pools, address)
+			_ = pool.close()
+		}
+	}
+}
+
+func (connector *routingConnector) poolFor(address string) *pool {
+	connector.mutex.Lock()
+	defer connector.mutex.Unlock()
+
+	return connector.poolForLocked(address)
+}
+
+func (connector *routingConnector) poolForLocked(address string) *pool {
+	pool, ok := connector.pools[address]
+	if !ok {
+		pool = newPool(address, connector.authToken, connector.config, connector.values, connector.dial)
+		pool.onFailure = connector.onFailure
+		connector.pools[address] = pool
+	}
+	return pool
+}
+
+// keys returns the addresses of the given set in order
+func keys(servers map[string]bool) []string {
+	addresses := make([]string, 0, len(servers))
+	for address := range servers {
+		addresses = append(addresses, address)
+	}
+	sort.Strings(addresses)
+	return addresses
+}
+
+func contains(servers []string, address string) bool {
+	for _, server := range servers {
+		if server == address {
+			return true
+		}
+	}
+	return false
+}
+
+func remove(servers []string, address string) []string {
+	result := servers[:0]
+	for _, server := range servers {
+		if server != address {
+			result = append(result, server)
+		}
+	}
+	return result
+}
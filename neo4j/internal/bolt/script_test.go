@@ -0,0 +1,211 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptsDir holds the boltstub scripts shared with the stub tests
+const scriptsDir = "../../test-stub/scripts"
+
+// scriptMessage is a message of a boltstub script that is either expected from the client
+// or sent by the server
+type scriptMessage struct {
+	client    bool
+	signature byte
+	fields    []interface{}
+	exit      bool
+}
+
+// script is a parsed boltstub script, see https://github.com/neo4j-drivers/boltkit
+type script struct {
+	version  uint32
+	auto     map[byte]bool
+	messages []scriptMessage
+	// mayBeUnused lets the client close the connection before the first scripted message, as
+	// pools open connections only to keep them idle
+	mayBeUnused bool
+}
+
+var scriptSignatures = map[string]byte{
+	"INIT":    msgHello,
+	"PULL":    msgPullAll,
+	"DISCARD": msgDiscardAll,
+}
+
+func init() {
+	for signature, name := range messageNames {
+		scriptSignatures[name] = signature
+	}
+}
+
+// loadScript parses the script at the given path relative to the scripts directory
+func loadScript(t *testing.T, path string) *script {
+	file, err := os.Open(filepath.Join(scriptsDir, path))
+	require.NoError(t, err)
+	defer file.Close()
+
+	parsed := &script{version: 1, auto: map[byte]bool{}}
+	client := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "!:"):
+			directive := strings.Fields(strings.TrimPrefix(trimmed, "!:"))
+			require.Len(t, directive, 2, "invalid directive %q", trimmed)
+			switch directive[0] {
+			case "BOLT":
+				version, err := strconv.ParseUint(directive[1], 10, 32)
+				require.NoError(t, err)
+				parsed.version = uint32(version)
+			case "AUTO":
+				signature, ok := scriptSignatures[directive[1]]
+				require.True(t, ok, "unknown message %s", directive[1])
+				parsed.auto[signature] = true
+			}
+			continue
+		case strings.HasPrefix(line, "C:"):
+			client, trimmed = true, strings.TrimSpace(line[2:])
+		case strings.HasPrefix(line, "S:"):
+			client, trimmed = false, strings.TrimSpace(line[2:])
+		}
+
+		if trimmed == "<EXIT>" {
+			parsed.messages = append(parsed.messages, scriptMessage{exit: true})
+			continue
+		}
+
+		name, fields := trimmed, ""
+		if i := strings.IndexByte(trimmed, ' '); i >= 0 {
+			name, fields = trimmed[:i], trimmed[i+1:]
+		}
+		signature, ok := scriptSignatures[name]
+		require.True(t, ok, "unknown message %s", name)
+		parsed.messages = append(parsed.messages, scriptMessage{client: client, signature: signature, fields: parseScriptFields(t, fields)})
+	}
+	require.NoError(t, scanner.Err())
+
+	return parsed
+}
+
+func parseScriptFields(t *testing.T, text string) []interface{} {
+	fields := []interface{}{}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	for {
+		var field interface{}
+		err := decoder.Decode(&field)
+		if err == io.EOF {
+			return fields
+		}
+		require.NoError(t, err, "invalid fields %s", text)
+		fields = append(fields, scriptValue(field))
+	}
+}
+
+// scriptValue converts JSON numbers into the integers and floats that are sent over Bolt
+func scriptValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = scriptValue(v[i])
+		}
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = scriptValue(v[key])
+		}
+	}
+	return value
+}
+
+// play serves the given script, answering the messages that are marked as automatic with
+// an empty success and verifying that the client sends the expected messages
+func (server *testServer) play(script *script) {
+	if script.auto[msgHello] {
+		if !server.accept(script.version) {
+			return
+		}
+	} else if !server.handshake(script.version) {
+		return
+	}
+
+	for i, message := range script.messages {
+		switch {
+		case message.exit:
+			return
+		case message.client:
+			signature, fields, ok := server.receiveScripted(script)
+			if i == 0 && script.mayBeUnused && (!ok || signature == msgGoodbye) {
+				return
+			}
+			if !ok {
+				server.t.Errorf("expected %s but connection was closed", messageNames[message.signature])
+				return
+			}
+			assert.Equal(server.t, messageNames[message.signature], messageNames[signature])
+			assert.Equal(server.t, message.fields, fields)
+		default:
+			if !server.send(message.signature, message.fields...) {
+				return
+			}
+		}
+	}
+
+	// the script is complete, the client may only close the connection
+	if signature, _, ok := server.receiveScripted(script); ok && signature != msgGoodbye {
+		server.t.Errorf("unexpected %s after the end of the script", messageNames[signature])
+	}
+}
+
+// receiveScripted receives the next message that is not answered automatically
+func (server *testServer) receiveScripted(script *script) (byte, []interface{}, bool) {
+	for {
+		signature, fields, ok := server.receive()
+		if !ok || signature == msgGoodbye || !script.auto[signature] {
+			return signature, fields, ok
+		}
+
+		if !server.send(msgSuccess, map[string]interface{}{}) {
+			return 0, nil, false
+		}
+	}
+}
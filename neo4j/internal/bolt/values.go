@@ -0,0 +1,135 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/packstream"
+)
+
+// ValueHandler converts between PackStream structs and Go values
+type ValueHandler interface {
+	// ReadableStructs returns the struct signatures this handler can read
+	ReadableStructs() []int16
+	// WritableTypes returns the Go types this handler can write
+	WritableTypes() []reflect.Type
+	// Read converts the fields of a struct with the given signature into a Go value
+	Read(signature int16, values []interface{}) (interface{}, error)
+	// Write converts a Go value into a struct signature and its fields
+	Write(value interface{}) (int16, []interface{}, error)
+}
+
+// plainValue is the signature of neo4j.PlainValue, values written with it have to be replaced by
+// their only field before they are packed
+const plainValue int16 = -1
+
+// valueSystem dispatches struct conversions to the registered value handlers
+type valueSystem struct {
+	readers map[int16]ValueHandler
+	writers map[reflect.Type]ValueHandler
+}
+
+// newValueSystem registers the handlers in order, a handler takes over the signatures and
+// types of the handlers before it
+func newValueSystem(handlers []ValueHandler) *valueSystem {
+	system := &valueSystem{
+		readers: make(map[int16]ValueHandler),
+		writers: make(map[reflect.Type]ValueHandler),
+	}
+
+	for _, handler := range handlers {
+		for _, signature := range handler.ReadableStructs() {
+			system.readers[signature] = handler
+		}
+
+		for _, writable := range handler.WritableTypes() {
+			system.writers[writable] = handler
+		}
+	}
+
+	return system
+}
+
+// hydrate replaces all structs found in the provided value with their Go representations
+func (system *valueSystem) hydrate(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case []interface{}:
+		for i := range v {
+			hydrated, err := system.hydrate(v[i])
+			if err != nil {
+				return nil, err
+			}
+			v[i] = hydrated
+		}
+		return v, nil
+	case map[string]interface{}:
+		for key, item := range v {
+			hydrated, err := system.hydrate(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = hydrated
+		}
+		return v, nil
+	case *packstream.Structure:
+		if _, err := system.hydrate(v.Fields); err != nil {
+			return nil, err
+		}
+
+		handler, ok := system.readers[int16(v.Signature)]
+		if !ok {
+			return nil, fmt.Errorf("no value handler registered for struct signature %#x", v.Signature)
+		}
+
+		return handler.Read(int16(v.Signature), v.Fields)
+	}
+
+	return value, nil
+}
+
+// newPacker creates a packer that writes structs through the registered value handlers,
+// structs are only allowed on protocol versions that support them
+func (system *valueSystem) newPacker(w io.Writer, structsAllowed bool) *packstream.Packer {
+	packer := packstream.NewPacker(w)
+	packer.SetValueMapper(func(value interface{}) (*packstream.Structure, error) {
+		handler, ok := system.writers[reflect.TypeOf(value)]
+		if !ok {
+			return nil, nil
+		}
+
+		if !structsAllowed {
+			return nil, fmt.Errorf("values of type %T are not supported by the negotiated protocol version", value)
+		}
+
+		signature, fields, err := handler.Write(value)
+		if err != nil {
+			return nil, err
+		}
+		if signature == plainValue {
+			return nil, fmt.Errorf("values of type %T are written as plain values, which are only supported in parameters and transaction metadata", value)
+		}
+
+		return &packstream.Structure{Signature: byte(signature), Fields: fields}, nil
+	})
+	return packer
+}
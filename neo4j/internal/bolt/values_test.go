@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/packstream"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testPoint struct {
+	x, y float64
+}
+
+type testPointHandler struct{}
+
+func (handler *testPointHandler) ReadableStructs() []int16 {
+	return []int16{'X'}
+}
+
+func (handler *testPointHandler) WritableTypes() []reflect.Type {
+	return []reflect.Type{reflect.TypeOf(testPoint{})}
+}
+
+func (handler *testPointHandler) Read(signature int16, values []interface{}) (interface{}, error) {
+	return testPoint{x: values[0].(float64), y: values[1].(float64)}, nil
+}
+
+func (handler *testPointHandler) Write(value interface{}) (int16, []interface{}, error) {
+	point := value.(testPoint)
+	return 'X', []interface{}{point.x, point.y}, nil
+}
+
+// testOtherPointHandler reads the same structs as testPointHandler into slices
+type testOtherPointHandler struct {
+	testPointHandler
+}
+
+func (handler *testOtherPointHandler) Read(signature int16, values []interface{}) (interface{}, error) {
+	return []float64{values[0].(float64), values[1].(float64)}, nil
+}
+
+// testPlainPointHandler writes points as plain strings
+type testPlainPointHandler struct {
+	testPointHandler
+}
+
+func (handler *testPlainPointHandler) Write(value interface{}) (int16, []interface{}, error) {
+	point := value.(testPoint)
+	return plainValue, []interface{}{fmt.Sprintf("%v,%v", point.x, point.y)}, nil
+}
+
+func TestValueSystem(t *testing.T) {
+	values := newValueSystem([]ValueHandler{&testPointHandler{}})
+
+	pack := func(value interface{}, structsAllowed bool) ([]byte, error) {
+		var buf bytes.Buffer
+		err := values.newPacker(&buf, structsAllowed).Pack(value)
+		return buf.Bytes(), err
+	}
+
+	roundTrip := func(t *testing.T, value interface{}) interface{} {
+		data, err := pack(value, true)
+		require.NoError(t, err)
+
+		unpacked, err := packstream.Unmarshal(data)
+		require.NoError(t, err)
+
+		hydrated, err := values.hydrate(unpacked)
+		require.NoError(t, err)
+		return hydrated
+	}
+
+	t.Run("should round trip primitive values", func(t *testing.T) {
+		for _, value := range []interface{}{
+			nil,
+			true,
+			false,
+			int64(0),
+			int64(-16),
+			int64(-17),
+			int64(127),
+			int64(128),
+			int64(math.MinInt16),
+			int64(math.MaxInt32),
+			int64(math.MinInt64),
+			int64(math.MaxInt64),
+			1.5,
+			"",
+			"hello",
+			strings.Repeat("a", 300),
+			strings.Repeat("b", 70000),
+			[]byte{1, 2, 3},
+		} {
+			assert.Equal(t, value, roundTrip(t, value))
+		}
+	})
+
+	t.Run("should round trip collections", func(t *testing.T) {
+		list := make([]interface{}, 20)
+		for i := range list {
+			list[i] = int64(i)
+		}
+		assert.Equal(t, list, roundTrip(t, list))
+
+		dict := map[string]interface{}{"a": int64(1), "b": []interface{}{"c"}, "d": map[string]interface{}{}}
+		assert.Equal(t, dict, roundTrip(t, dict))
+	})
+
+	t.Run("should convert typed collections through reflection", func(t *testing.T) {
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, roundTrip(t, []int{1, 2}))
+		assert.Equal(t, []interface{}{"a", "b"}, roundTrip(t, [2]string{"a", "b"}))
+		assert.Equal(t, map[string]interface{}{"a": 1.5}, roundTrip(t, map[string]float64{"a": 1.5}))
+	})
+
+	t.Run("should round trip values through value handlers", func(t *testing.T) {
+		assert.Equal(t, testPoint{x: 1, y: 2}, roundTrip(t, testPoint{x: 1, y: 2}))
+		assert.Equal(t, []interface{}{testPoint{x: 3, y: 4}}, roundTrip(t, []interface{}{testPoint{x: 3, y: 4}}))
+	})
+
+	t.Run("should not write structs when not allowed", func(t *testing.T) {
+		_, err := pack(testPoint{}, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on unsupported values", func(t *testing.T) {
+		for _, value := range []interface{}{struct{}{}, map[int]string{}, uint64(math.MaxUint64)} {
+			_, err := pack(value, true)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("should fail on values written as plain values", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := newValueSystem([]ValueHandler{&testPlainPointHandler{}}).newPacker(&buf, true).Pack(map[string]interface{}{"p": testPoint{}})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "written as plain values")
+		assert.NotContains(t, buf.String(), "\xb1\xff")
+	})
+
+	t.Run("should prefer handlers registered later", func(t *testing.T) {
+		values := newValueSystem([]ValueHandler{&testPointHandler{}, &testOtherPointHandler{}})
+
+		hydrated, err := values.hydrate(&packstream.Structure{Signature: 'X', Fields: []interface{}{1.0, 2.0}})
+		require.NoError(t, err)
+		assert.Equal(t, []float64{1, 2}, hydrated)
+	})
+
+	t.Run("should fail on unknown structs", func(t *testing.T) {
+		_, err := values.hydrate(&packstream.Structure{Signature: 'Z'})
+		assert.Error(t, err)
+	})
+
+}
@@ -0,0 +1,127 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BoltLogger receives the messages exchanged with the server, formatted like the lines of a
+// boltstub script, e.g. C: RUN "RETURN 1" {} {} and S: SUCCESS {"fields":["1"]}. It is
+// called from the goroutines using the connections and must be safe for concurrent use.
+type BoltLogger interface {
+	LogMessage(connectionId string, timestamp time.Time, message string)
+}
+
+// redactedCredentials replaces the credentials of HELLO and INIT messages
+const redactedCredentials = "*******"
+
+func (connection *boltConnection) SetBoltLogger(logger BoltLogger) {
+	connection.boltLogger = logger
+}
+
+// logMessage hands a message over to the bolt logger of the session using the connection,
+// or to the one of the connector
+func (connection *boltConnection) logMessage(client bool, signature byte, fields []interface{}) {
+	logger := connection.boltLogger
+	if logger == nil {
+		logger = connection.config.BoltLogger
+	}
+	if logger == nil {
+		return
+	}
+
+	prefix := "S: "
+	if client {
+		prefix = "C: "
+	}
+	if signature == msgHello {
+		fields = redactHello(fields)
+	}
+
+	logger.LogMessage(connection.id, time.Now(), prefix+formatMessage(connection.messageName(signature), fields))
+}
+
+// messageName returns the name of the message in the protocol version of the connection
+func (connection *boltConnection) messageName(signature byte) string {
+	switch {
+	case signature == msgHello && connection.version < 3:
+		return "INIT"
+	case signature == msgPullAll && connection.version >= 4:
+		return "PULL"
+	case signature == msgDiscardAll && connection.version >= 4:
+		return "DISCARD"
+	}
+
+	if name, ok := messageNames[signature]; ok {
+		return name
+	}
+	return fmt.Sprintf("<0x%02X>", signature)
+}
+
+// formatMessage appends the fields to the name of the message as JSON, values that cannot be
+// represented as JSON are formatted as Go values
+func formatMessage(name string, fields []interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(name)
+
+	var encoded bytes.Buffer
+	encoder := json.NewEncoder(&encoded)
+	encoder.SetEscapeHTML(false)
+	for _, field := range fields {
+		builder.WriteByte(' ')
+
+		encoded.Reset()
+		if err := encoder.Encode(field); err != nil {
+			fmt.Fprintf(&builder, "%v", field)
+			continue
+		}
+		builder.Write(bytes.TrimSuffix(encoded.Bytes(), []byte("\n")))
+	}
+
+	return builder.String()
+}
+
+// redactHello returns the fields of a HELLO or INIT message with the credentials of the
+// authentication token replaced
+func redactHello(fields []interface{}) []interface{} {
+	redacted := make([]interface{}, len(fields))
+	for i, field := range fields {
+		redacted[i] = field
+
+		token, ok := field.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := token["credentials"]; ok {
+			copied := make(map[string]interface{}, len(token))
+			for key, value := range token {
+				copied[key] = value
+			}
+			copied["credentials"] = redactedCredentials
+			redacted[i] = copied
+		}
+	}
+	return redacted
+}
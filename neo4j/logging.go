@@ -20,13 +20,14 @@
 package neo4j
 
 import (
-	"fmt"
-	"path/filepath"
-	"runtime"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 // Logging is the interface that any provided logging target must satisfy for the neo4j
-// driver to send its logging messages
+// driver to send its logging messages. It is kept for compatibility, the messages are
+// rendered as text along with their fields, see StructuredLogger.
 type Logging interface {
 	ErrorEnabled() bool
 	WarningEnabled() bool
@@ -39,37 +40,136 @@ type Logging interface {
 	Debugf(message string, args ...interface{})
 }
 
-func fileAndLineNumberOfCall() string {
-	_, file, line, ok := runtime.Caller(2)
-	if !ok {
-		file = "?"
-		line = 0
-	}
-	_, file = filepath.Split(file)
+// Keys of the fields the driver adds to its log messages
+const (
+	FieldConnectionId  = "connection_id"
+	FieldSessionId     = "session_id"
+	FieldServerAddress = "server_address"
+	FieldDatabase      = "database"
+	FieldStatement     = "statement"
+	FieldError         = "error"
+	FieldAttempt       = "attempt"
+	FieldRetryDelay    = "retry_delay"
+)
+
+// Field is a key/value pair that describes the context of a log message, e.g. the connection
+// or the session it is about.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// StructuredLogger is the interface that a logging target must satisfy for the driver to
+// send its log messages along with the fields that describe their context, instead of
+// rendering them into text. See SlogLogger and ZapLogger for adapters of common loggers.
+type StructuredLogger interface {
+	// Enabled returns whether messages of the given level are logged, the driver does not
+	// build messages of disabled levels.
+	Enabled(level LogLevel) bool
+	// Log logs the message at the given level along with its fields.
+	Log(level LogLevel, message string, fields []Field)
+}
+
+// BoltLogger is the interface that a logging target must satisfy to receive every message the
+// driver exchanges with the server. The messages are formatted like the lines of a boltstub
+// script, client messages prefixed with "C: " and server messages with "S: ", e.g.
+//
+//	C: RUN "RETURN $x" {"x":1} {}
+//	S: SUCCESS {"fields":["$x"],"t_first":1}
+//
+// The credentials of HELLO messages are redacted. It is called from the goroutines using
+// the driver and must be safe for concurrent use.
+type BoltLogger interface {
+	LogMessage(connectionId string, timestamp time.Time, message string)
+}
+
+// logger sends the log messages of the driver to a structured logger, adding the fields of
+// the context it was created for. A nil logger discards all messages.
+type logger struct {
+	delegate StructuredLogger
+	fields   []Field
+}
 
-	return fmt.Sprintf("%s:%d", file, line)
+// newLogger returns the logger of the given configuration, the structured logger takes
+// precedence over the logging target
+func newLogger(config *Config) *logger {
+	switch {
+	case config.Logger != nil:
+		return &logger{delegate: config.Logger}
+	case config.Log != nil:
+		return &logger{delegate: WrapLogging(config.Log)}
+	default:
+		return nil
+	}
 }
 
-func errorf(logging Logging, message string, args ...interface{}) {
-	if logging != nil && logging.ErrorEnabled() {
-		logging.Errorf(fmt.Sprintf("%s: %s", fileAndLineNumberOfCall(), message), args...)
+// with returns a logger that adds the given fields to the ones of this logger
+func (log *logger) with(fields ...Field) *logger {
+	if log == nil {
+		return nil
 	}
+
+	return &logger{delegate: log.delegate, fields: append(append([]Field(nil), log.fields...), fields...)}
+}
+
+// enabled returns whether messages of the given level are logged, to skip building fields
+// that are expensive to compute
+func (log *logger) enabled(level LogLevel) bool {
+	return log != nil && log.delegate.Enabled(level)
 }
 
-func warningf(logging Logging, message string, args ...interface{}) {
-	if logging != nil && logging.WarningEnabled() {
-		logging.Warningf(fmt.Sprintf("%s: %s", fileAndLineNumberOfCall(), message), args...)
+func (log *logger) log(level LogLevel, message string, fields []Field) {
+	if !log.enabled(level) {
+		return
+	}
+
+	if len(log.fields) > 0 {
+		fields = append(append(make([]Field, 0, len(log.fields)+len(fields)), log.fields...), fields...)
 	}
+	log.delegate.Log(level, message, fields)
+}
+
+func (log *logger) error(message string, fields ...Field) {
+	log.log(ERROR, message, fields)
+}
+
+func (log *logger) warning(message string, fields ...Field) {
+	log.log(WARNING, message, fields)
+}
+
+func (log *logger) info(message string, fields ...Field) {
+	log.log(INFO, message, fields)
+}
+
+func (log *logger) debug(message string, fields ...Field) {
+	log.log(DEBUG, message, fields)
+}
+
+func errorField(err error) Field {
+	return Field{Key: FieldError, Value: err}
 }
 
-func infof(logging Logging, message string, args ...interface{}) {
-	if logging != nil && logging.InfoEnabled() {
-		logging.Infof(fmt.Sprintf("%s: %s", fileAndLineNumberOfCall(), message), args...)
+// wrapLoggerOrNil hands the log messages of the connector over to the driver's logger
+func wrapLoggerOrNil(log *logger) bolt.Logger {
+	if log == nil {
+		return nil
 	}
+
+	return &connectorLogger{log: log}
+}
+
+type connectorLogger struct {
+	log *logger
+}
+
+func (adapter *connectorLogger) Enabled(level bolt.LogLevel) bool {
+	return adapter.log.delegate.Enabled(LogLevel(level))
 }
 
-func debugf(logging Logging, message string, args ...interface{}) {
-	if logging != nil && logging.DebugEnabled() {
-		logging.Debugf(fmt.Sprintf("%s: %s", fileAndLineNumberOfCall(), message), args...)
+func (adapter *connectorLogger) Log(level bolt.LogLevel, message string, fields []bolt.LogField) {
+	converted := make([]Field, len(fields))
+	for i, field := range fields {
+		converted[i] = Field{Key: field.Key, Value: field.Value}
 	}
+	adapter.log.log(LogLevel(level), message, converted)
 }
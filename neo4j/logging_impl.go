@@ -20,8 +20,11 @@
 package neo4j
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strings"
+	"time"
 )
 
 // LogLevel is the type that default logging implementations use for available
@@ -39,6 +42,22 @@ const (
 	DEBUG = 4
 )
 
+// String returns the name of the level.
+func (level LogLevel) String() string {
+	switch level {
+	case ERROR:
+		return "ERROR"
+	case WARNING:
+		return "WARNING"
+	case INFO:
+		return "INFO"
+	case DEBUG:
+		return "DEBUG"
+	default:
+		return fmt.Sprintf("LogLevel(%d)", int(level))
+	}
+}
+
 type internalLogger struct {
 	level         LogLevel
 	errorLogger   *log.Logger
@@ -63,6 +82,20 @@ func ConsoleLogger(level LogLevel) Logging {
 	}
 }
 
+type consoleBoltLogger struct {
+	logger *log.Logger
+}
+
+// ConsoleBoltLogger returns a simple bolt logger that writes the messages to the console
+// along with their timestamp and the id of their connection
+func ConsoleBoltLogger() BoltLogger {
+	return &consoleBoltLogger{logger: log.New(os.Stdout, "", 0)}
+}
+
+func (logger *consoleBoltLogger) LogMessage(connectionId string, timestamp time.Time, message string) {
+	logger.logger.Printf("BOLT   : %s %s %s", timestamp.Format("2006/01/02 15:04:05.000000"), connectionId, message)
+}
+
 func (logger *internalLogger) ErrorEnabled() bool {
 	return ERROR <= logger.level
 }
@@ -94,3 +127,121 @@ func (logger *internalLogger) Infof(message string, args ...interface{}) {
 func (logger *internalLogger) Debugf(message string, args ...interface{}) {
 	logger.debugLogger.Printf(message, args...)
 }
+
+type wrappedLogging struct {
+	logging Logging
+}
+
+// WrapLogging returns a structured logger that renders the messages along with their fields
+// into text and writes them to the given logging target, e.g.
+//
+//	connection closed connection_id=conn-1 server_address=localhost:7687
+func WrapLogging(logging Logging) StructuredLogger {
+	return &wrappedLogging{logging: logging}
+}
+
+func (wrapped *wrappedLogging) Enabled(level LogLevel) bool {
+	switch level {
+	case ERROR:
+		return wrapped.logging.ErrorEnabled()
+	case WARNING:
+		return wrapped.logging.WarningEnabled()
+	case INFO:
+		return wrapped.logging.InfoEnabled()
+	case DEBUG:
+		return wrapped.logging.DebugEnabled()
+	default:
+		return false
+	}
+}
+
+func (wrapped *wrappedLogging) Log(level LogLevel, message string, fields []Field) {
+	text := formatFields(message, fields)
+	switch level {
+	case ERROR:
+		wrapped.logging.Errorf("%s", text)
+	case WARNING:
+		wrapped.logging.Warningf("%s", text)
+	case INFO:
+		wrapped.logging.Infof("%s", text)
+	case DEBUG:
+		wrapped.logging.Debugf("%s", text)
+	}
+}
+
+// formatFields appends the fields to the message as key=value pairs, quoting values that
+// contain whitespace
+func formatFields(message string, fields []Field) string {
+	var builder strings.Builder
+	builder.WriteString(message)
+	for _, field := range fields {
+		value := fmt.Sprint(field.Value)
+		if strings.ContainsAny(value, " \t\n\"") {
+			value = fmt.Sprintf("%q", value)
+		}
+		fmt.Fprintf(&builder, " %s=%s", field.Key, value)
+	}
+	return builder.String()
+}
+
+// SlogStyleLogger is the interface of loggers that take the fields of a message as
+// alternating keys and values, *slog.Logger satisfies it.
+type SlogStyleLogger interface {
+	Error(message string, keysAndValues ...interface{})
+	Warn(message string, keysAndValues ...interface{})
+	Info(message string, keysAndValues ...interface{})
+	Debug(message string, keysAndValues ...interface{})
+}
+
+// ZapStyleLogger is the interface of loggers that take the fields of a message as
+// alternating keys and values through methods suffixed with w, *zap.SugaredLogger
+// satisfies it.
+type ZapStyleLogger interface {
+	Errorw(message string, keysAndValues ...interface{})
+	Warnw(message string, keysAndValues ...interface{})
+	Infow(message string, keysAndValues ...interface{})
+	Debugw(message string, keysAndValues ...interface{})
+}
+
+type keyValueLogger struct {
+	level LogLevel
+	log   [DEBUG + 1]func(message string, keysAndValues ...interface{})
+}
+
+// SlogLogger returns a structured logger that sends the messages up to the given level to
+// a slog-style logger, which may filter them further.
+func SlogLogger(logger SlogStyleLogger, level LogLevel) StructuredLogger {
+	return &keyValueLogger{level: level, log: [DEBUG + 1]func(string, ...interface{}){
+		ERROR:   logger.Error,
+		WARNING: logger.Warn,
+		INFO:    logger.Info,
+		DEBUG:   logger.Debug,
+	}}
+}
+
+// ZapLogger returns a structured logger that sends the messages up to the given level to a
+// zap-style logger, which may filter them further.
+func ZapLogger(logger ZapStyleLogger, level LogLevel) StructuredLogger {
+	return &keyValueLogger{level: level, log: [DEBUG + 1]func(string, ...interface{}){
+		ERROR:   logger.Errorw,
+		WARNING: logger.Warnw,
+		INFO:    logger.Infow,
+		DEBUG:   logger.Debugw,
+	}}
+}
+
+func (logger *keyValueLogger) Enabled(level LogLevel) bool {
+	return ERROR <= level && level <= DEBUG && level <= logger.level
+}
+
+func (logger *keyValueLogger) Log(level LogLevel, message string, fields []Field) {
+	if !logger.Enabled(level) {
+		return
+	}
+
+	keysAndValues := make([]interface{}, 0, 2*len(fields))
+	for _, field := range fields {
+		keysAndValues = append(keysAndValues, field.Key, field.Value)
+	}
+	logger.log[level](message, keysAndValues...)
+}
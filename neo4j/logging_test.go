@@ -20,12 +20,86 @@
 package neo4j
 
 import (
+	"context"
+	"errors"
+	"fmt"
+
 	"github.com/golang/mock/gomock"
-	"github.com/neo4j/neo4j-go-driver/neo4j/utils/test"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 	. "github.com/onsi/ginkgo"
-	"github.com/onsi/gomega"
+	. "github.com/onsi/gomega"
 )
 
+// recordedMessage is a message logged by a recordingLogger
+type recordedMessage struct {
+	level   LogLevel
+	message string
+	fields  []Field
+}
+
+// recordingLogger records the messages it receives up to the given level
+type recordingLogger struct {
+	level    LogLevel
+	messages []recordedMessage
+}
+
+func (logger *recordingLogger) Enabled(level LogLevel) bool {
+	return level <= logger.level
+}
+
+func (logger *recordingLogger) Log(level LogLevel, message string, fields []Field) {
+	logger.messages = append(logger.messages, recordedMessage{level: level, message: message, fields: fields})
+}
+
+// keyValueRecorder records the calls of both slog-style and zap-style loggers
+type keyValueRecorder struct {
+	calls []string
+}
+
+func (recorder *keyValueRecorder) record(method string, message string, keysAndValues []interface{}) {
+	recorder.calls = append(recorder.calls, fmt.Sprintf("%s %s %v", method, message, keysAndValues))
+}
+
+func (recorder *keyValueRecorder) Error(message string, keysAndValues ...interface{}) {
+	recorder.record("Error", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Warn(message string, keysAndValues ...interface{}) {
+	recorder.record("Warn", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Info(message string, keysAndValues ...interface{}) {
+	recorder.record("Info", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Debug(message string, keysAndValues ...interface{}) {
+	recorder.record("Debug", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Errorw(message string, keysAndValues ...interface{}) {
+	recorder.record("Errorw", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Warnw(message string, keysAndValues ...interface{}) {
+	recorder.record("Warnw", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Infow(message string, keysAndValues ...interface{}) {
+	recorder.record("Infow", message, keysAndValues)
+}
+
+func (recorder *keyValueRecorder) Debugw(message string, keysAndValues ...interface{}) {
+	recorder.record("Debugw", message, keysAndValues)
+}
+
+// expectServerInfo allows the calls that describe the server of a connection
+func expectServerInfo(connection *MockConnection) {
+	connection.EXPECT().RemoteAddress().Return("localhost:7687", nil).AnyTimes()
+	connection.EXPECT().Server().Return("Neo4j/3.5.0", nil).AnyTimes()
+	connection.EXPECT().ProtocolVersion().Return(3, nil).AnyTimes()
+	connection.EXPECT().ServerConnectionId().Return("bolt-1", nil).AnyTimes()
+}
+
 var _ = Describe("Logging", func() {
 	var (
 		mockCtrl *gomock.Controller
@@ -39,7 +113,7 @@ var _ = Describe("Logging", func() {
 		mockCtrl.Finish()
 	})
 
-	Context("errorf", func() {
+	Context("WrapLogging", func() {
 		When("Error level is not enabled", func() {
 			It("should not invoke Errorf on logger", func() {
 				logging := NewMockLogging(mockCtrl)
@@ -47,31 +121,18 @@ var _ = Describe("Logging", func() {
 				logging.EXPECT().ErrorEnabled().Times(1).Return(false)
 				logging.EXPECT().Errorf(gomock.Any(), gomock.Any()).Times(0)
 
-				errorf(logging, "some error: %s, %d", "str1", 1)
+				newLogger(&Config{Log: logging}).error("some error", Field{Key: "a", Value: 1})
 			})
 		})
 
 		When("Error level is enabled", func() {
-			It("should invoke Errorf on logger", func() {
+			It("should invoke Errorf on logger with the rendered fields", func() {
 				logging := NewMockLogging(mockCtrl)
 
 				logging.EXPECT().ErrorEnabled().Times(1).Return(true)
-				logging.EXPECT().Errorf(test.WrapMatcher(gomega.ContainSubstring("some error")), "str1", 1).Times(1)
-
-				errorf(logging, "some error", "str1", 1)
-			})
-		})
-	})
-
-	Context("warningf", func() {
-		When("Warning level is not enabled", func() {
-			It("should not invoke Warningf on logger", func() {
-				logging := NewMockLogging(mockCtrl)
+				logging.EXPECT().Errorf("%s", "some error a=1 b=str1").Times(1)
 
-				logging.EXPECT().WarningEnabled().Times(1).Return(false)
-				logging.EXPECT().Warningf(gomock.Any(), gomock.Any()).Times(0)
-
-				warningf(logging, "some warning: %s, %d", "str1", 1)
+				newLogger(&Config{Log: logging}).error("some error", Field{Key: "a", Value: 1}, Field{Key: "b", Value: "str1"})
 			})
 		})
 
@@ -80,22 +141,9 @@ var _ = Describe("Logging", func() {
 				logging := NewMockLogging(mockCtrl)
 
 				logging.EXPECT().WarningEnabled().Times(1).Return(true)
-				logging.EXPECT().Warningf(test.WrapMatcher(gomega.ContainSubstring("some warning")), "str1", 1).Times(1)
+				logging.EXPECT().Warningf("%s", "some warning").Times(1)
 
-				warningf(logging, "some warning", "str1", 1)
-			})
-		})
-	})
-
-	Context("infof", func() {
-		When("Info level is not enabled", func() {
-			It("should not invoke Infof on logger", func() {
-				logging := NewMockLogging(mockCtrl)
-
-				logging.EXPECT().InfoEnabled().Times(1).Return(false)
-				logging.EXPECT().Infof(gomock.Any(), gomock.Any()).Times(0)
-
-				infof(logging, "some info: %s, %d", "str1", 1)
+				newLogger(&Config{Log: logging}).warning("some warning")
 			})
 		})
 
@@ -104,14 +152,12 @@ var _ = Describe("Logging", func() {
 				logging := NewMockLogging(mockCtrl)
 
 				logging.EXPECT().InfoEnabled().Times(1).Return(true)
-				logging.EXPECT().Infof(test.WrapMatcher(gomega.ContainSubstring("some info")), "str1", 1).Times(1)
+				logging.EXPECT().Infof("%s", "some info").Times(1)
 
-				infof(logging, "some info", "str1", 1)
+				newLogger(&Config{Log: logging}).info("some info")
 			})
 		})
-	})
 
-	Context("debugf", func() {
 		When("Debug level is not enabled", func() {
 			It("should not invoke Debugf on logger", func() {
 				logging := NewMockLogging(mockCtrl)
@@ -119,19 +165,184 @@ var _ = Describe("Logging", func() {
 				logging.EXPECT().DebugEnabled().Times(1).Return(false)
 				logging.EXPECT().Debugf(gomock.Any(), gomock.Any()).Times(0)
 
-				debugf(logging, "some debug: %s, %d", "str1", 1)
+				newLogger(&Config{Log: logging}).debug("some debug")
 			})
 		})
 
-		When("Debug level is enabled", func() {
-			It("should invoke Debugf on logger", func() {
-				logging := NewMockLogging(mockCtrl)
+		It("should quote values containing whitespace", func() {
+			Expect(formatFields("running statement", []Field{{Key: FieldStatement, Value: "RETURN 1"}, {Key: FieldError, Value: errors.New("failed")}})).
+				To(Equal(`running statement statement="RETURN 1" error=failed`))
+		})
 
-				logging.EXPECT().DebugEnabled().Times(1).Return(true)
-				logging.EXPECT().Debugf(test.WrapMatcher(gomega.ContainSubstring("some debug")), "str1", 1).Times(1)
+		It("should not interpret format verbs in messages", func() {
+			logging := NewMockLogging(mockCtrl)
 
-				debugf(logging, "some debug", "str1", 1)
-			})
+			logging.EXPECT().Warningf("%s", "100% done").Times(1)
+
+			WrapLogging(logging).Log(WARNING, "100% done", nil)
+		})
+	})
+
+	Context("logger", func() {
+		It("should prefer the structured logger", func() {
+			structured := &recordingLogger{level: DEBUG}
+			logging := NewMockLogging(mockCtrl)
+
+			newLogger(&Config{Log: logging, Logger: structured}).info("some info")
+
+			Expect(structured.messages).To(HaveLen(1))
+		})
+
+		It("should discard messages when no logging target is set", func() {
+			log := newLogger(&Config{})
+
+			Expect(log).To(BeNil())
+			Expect(func() { log.with(Field{Key: FieldSessionId, Value: "session-1"}).error("some error") }).NotTo(Panic())
+		})
+
+		It("should add the fields of its context in front", func() {
+			structured := &recordingLogger{level: DEBUG}
+			session := newLogger(&Config{Logger: structured}).with(Field{Key: FieldSessionId, Value: "session-1"})
+
+			session.with(Field{Key: FieldConnectionId, Value: "conn-1"}).warning("some warning", Field{Key: FieldAttempt, Value: 2})
+			session.debug("some debug")
+
+			Expect(structured.messages).To(Equal([]recordedMessage{
+				{level: WARNING, message: "some warning", fields: []Field{{Key: FieldSessionId, Value: "session-1"}, {Key: FieldConnectionId, Value: "conn-1"}, {Key: FieldAttempt, Value: 2}}},
+				{level: DEBUG, message: "some debug", fields: []Field{{Key: FieldSessionId, Value: "session-1"}}},
+			}))
+		})
+
+		It("should not pass messages of disabled levels", func() {
+			structured := &recordingLogger{level: WARNING}
+
+			newLogger(&Config{Logger: structured}).info("some info")
+
+			Expect(structured.messages).To(BeEmpty())
+		})
+
+		It("should hand over the messages of the connector", func() {
+			structured := &recordingLogger{level: DEBUG}
+			connectorLog := wrapLoggerOrNil(newLogger(&Config{Logger: structured}))
+
+			Expect(connectorLog.Enabled(bolt.LogLevelDebug)).To(BeTrue())
+			connectorLog.Log(bolt.LogLevelWarning, "connection closed", []bolt.LogField{{Key: bolt.LogFieldConnectionId, Value: "conn-1"}})
+
+			Expect(structured.messages).To(Equal([]recordedMessage{
+				{level: WARNING, message: "connection closed", fields: []Field{{Key: FieldConnectionId, Value: "conn-1"}}},
+			}))
+		})
+	})
+
+	Context("session", func() {
+		It("should log statements with the ids of the session and the connection", func() {
+			structured := &recordingLogger{level: DEBUG}
+			connection := NewMockConnection(mockCtrl)
+			driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+			driver.config.Logger = structured
+			driver.config.RedactStatement = func(statement string) string { return "RETURN ?" }
+			driver.log = newLogger(driver.config)
+
+			connection.EXPECT().Id().Return("conn-1", nil).AnyTimes()
+			expectServerInfo(connection)
+			connection.EXPECT().Run("RETURN 1", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+			connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+			connection.EXPECT().Flush().Return(nil)
+
+			session := newSession(context.Background(), driver, SessionConfig{DatabaseName: "movies"}).(*neoSession)
+			_, err := session.Run("RETURN 1", nil)
+			Expect(err).To(BeNil())
+
+			Expect(structured.messages).To(HaveLen(1))
+			Expect(structured.messages[0].message).To(Equal("running statement"))
+			Expect(structured.messages[0].fields).To(ConsistOf(
+				Field{Key: FieldSessionId, Value: session.log.fields[0].Value},
+				Field{Key: FieldStatement, Value: "RETURN ?"},
+				Field{Key: FieldConnectionId, Value: "conn-1"},
+				Field{Key: FieldDatabase, Value: "movies"},
+			))
+			Expect(session.log.fields[0].Value).To(HavePrefix("session-"))
+		})
+	})
+
+	Context("BoltLogger", func() {
+		It("should set the bolt logger of the session on its connections", func() {
+			boltLogger := ConsoleBoltLogger()
+			connection := NewMockConnection(mockCtrl)
+			driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+
+			expectServerInfo(connection)
+			connection.EXPECT().LastBookmark().Return("", nil).AnyTimes()
+			connection.EXPECT().Close().Return(nil).AnyTimes()
+			// the connection is released after the first result was consumed and acquired again
+			connection.EXPECT().SetBoltLogger(boltLogger).Times(2)
+			connection.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil).Times(2)
+			connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil).Times(2)
+			connection.EXPECT().Flush().Return(nil).Times(2)
+			connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).Return(bolt.FetchTypeMetadata, nil).AnyTimes()
+			connection.EXPECT().Fields().Return([]string{}, nil).AnyTimes()
+			connection.EXPECT().Metadata().Return(map[string]interface{}{}, nil).AnyTimes()
+
+			session := newSession(context.Background(), driver, SessionConfig{BoltLogger: boltLogger})
+			_, err := session.Run("RETURN 1", nil)
+			Expect(err).To(BeNil())
+			_, err = session.Run("RETURN 2", nil)
+			Expect(err).To(BeNil())
+		})
+
+		It("should leave the bolt logger of the driver on the connections of other sessions", func() {
+			connection := NewMockConnection(mockCtrl)
+			driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+
+			expectServerInfo(connection)
+			connection.EXPECT().SetBoltLogger(gomock.Any()).Times(0)
+			connection.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+			connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+			connection.EXPECT().Flush().Return(nil)
+
+			_, err := newSession(context.Background(), driver, SessionConfig{}).Run("RETURN 1", nil)
+			Expect(err).To(BeNil())
+		})
+	})
+
+	Context("SlogLogger", func() {
+		It("should pass fields as alternating keys and values", func() {
+			recorder := &keyValueRecorder{}
+			logger := SlogLogger(recorder, INFO)
+
+			logger.Log(ERROR, "some error", []Field{{Key: FieldSessionId, Value: "session-1"}, {Key: FieldAttempt, Value: 2}})
+			logger.Log(INFO, "some info", nil)
+
+			Expect(recorder.calls).To(Equal([]string{"Error some error [session_id session-1 attempt 2]", "Info some info []"}))
+		})
+
+		It("should only enable the levels up to the given one", func() {
+			logger := SlogLogger(&keyValueRecorder{}, WARNING)
+
+			Expect(logger.Enabled(ERROR)).To(BeTrue())
+			Expect(logger.Enabled(WARNING)).To(BeTrue())
+			Expect(logger.Enabled(INFO)).To(BeFalse())
+			Expect(logger.Enabled(LogLevel(0))).To(BeFalse())
+		})
+	})
+
+	Context("ZapLogger", func() {
+		It("should pass fields as alternating keys and values", func() {
+			recorder := &keyValueRecorder{}
+			logger := ZapLogger(recorder, DEBUG)
+
+			logger.Log(WARNING, "some warning", []Field{{Key: FieldConnectionId, Value: "conn-1"}})
+			logger.Log(DEBUG, "some debug", nil)
+
+			Expect(recorder.calls).To(Equal([]string{"Warnw some warning [connection_id conn-1]", "Debugw some debug []"}))
+		})
+
+		It("should drop messages of disabled levels", func() {
+			recorder := &keyValueRecorder{}
+
+			ZapLogger(recorder, ERROR).Log(DEBUG, "some debug", nil)
+
+			Expect(recorder.calls).To(BeEmpty())
 		})
 	})
 })
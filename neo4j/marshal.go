@@ -0,0 +1,282 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// Marshaler is implemented by types that convert themselves into a statement parameter. The
+// returned value is marshalled in turn, so it can be anything that is accepted as a parameter.
+type Marshaler interface {
+	MarshalNeo4j() (interface{}, error)
+}
+
+// paramMarshaller converts statement parameters into values that can be sent to the server
+type paramMarshaller struct {
+	// nativeTypes are the types that the value handlers of the driver write
+	nativeTypes map[reflect.Type]bool
+	// writers are the custom value handlers by the types they write
+	writers map[reflect.Type]ValueHandler
+}
+
+func newParamMarshaller(custom []ValueHandler) *paramMarshaller {
+	marshaller := &paramMarshaller{
+		nativeTypes: make(map[reflect.Type]bool),
+		writers:     make(map[reflect.Type]ValueHandler),
+	}
+
+	for _, handler := range valueHandlers(nil) {
+		for _, writable := range handler.WritableTypes() {
+			marshaller.nativeTypes[writable] = true
+		}
+	}
+
+	for _, handler := range custom {
+		for _, writable := range handler.WritableTypes() {
+			marshaller.writers[writable] = handler
+		}
+	}
+
+	return marshaller
+}
+
+// marshalParams converts the parameters of a statement into values that can be sent to the
+// server. Besides those values and the ones written by value handlers, parameters can be
+// structs, typed slices and maps with string keys, pointers and named types of basic kinds, as
+// well as types that implement Marshaler. Struct fields are named the same way as they are by
+// ScanRecord, and values that contain themselves are rejected.
+func (marshaller *paramMarshaller) marshalParams(params map[string]interface{}) (map[string]interface{}, error) {
+	if params == nil {
+		return nil, nil
+	}
+
+	marshalled := make(map[string]interface{}, len(params))
+	visiting := make(visits)
+	for key, value := range params {
+		converted, err := marshaller.marshalValue(value, key, visiting)
+		if err != nil {
+			return nil, err
+		}
+		marshalled[key] = converted
+	}
+
+	return marshalled, nil
+}
+
+func (marshaller *paramMarshaller) marshalValue(value interface{}, path string, visiting visits) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, bool, string, []byte,
+		int, int8, int16, int32, int64, uint8, uint16, uint32, float32, float64:
+		return v, nil
+	case Marshaler:
+		converted, err := v.MarshalNeo4j()
+		if err != nil {
+			return nil, newDriverError("unable to marshal parameter '%s': %v", path, err)
+		}
+		return marshaller.marshalValue(converted, path, visiting)
+	}
+
+	valueType := reflect.TypeOf(value)
+	if handler, ok := marshaller.writers[valueType]; ok {
+		return marshaller.marshalWritten(handler, value, path, visiting)
+	}
+
+	if marshaller.nativeTypes[valueType] {
+		return value, nil
+	}
+
+	return marshaller.marshalReflected(reflect.ValueOf(value), path, visiting)
+}
+
+func (marshaller *paramMarshaller) marshalReflected(value reflect.Value, path string, visiting visits) (interface{}, error) {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		if value.Kind() == reflect.Ptr {
+			leave, err := visiting.enter(value, path)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		return marshaller.marshalValue(value.Elem().Interface(), path, visiting)
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return nil, newDriverError("unable to marshal parameter '%s': value %d overflows int64", path, value.Uint())
+		}
+		return int64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil, nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(bytes), value)
+			return bytes, nil
+		}
+		if value.Kind() == reflect.Slice {
+			leave, err := visiting.enter(value, path)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		list := make([]interface{}, value.Len())
+		for i := range list {
+			item, err := marshaller.marshalValue(value.Index(i).Interface(), fmt.Sprintf("%s[%d]", path, i), visiting)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = item
+		}
+		return list, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if value.IsNil() {
+			return nil, nil
+		}
+		leave, err := visiting.enter(value, path)
+		if err != nil {
+			return nil, err
+		}
+		defer leave()
+		dict := make(map[string]interface{}, value.Len())
+		iter := value.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			item, err := marshaller.marshalValue(iter.Value().Interface(), path+"."+key, visiting)
+			if err != nil {
+				return nil, err
+			}
+			dict[key] = item
+		}
+		return dict, nil
+	case reflect.Struct:
+		dict := make(map[string]interface{})
+		if err := marshaller.marshalStruct(value, dict, path, visiting); err != nil {
+			return nil, err
+		}
+		return dict, nil
+	}
+
+	return nil, newDriverError("unable to marshal parameter '%s': values of type %s are not supported", path, value.Type())
+}
+
+// marshalWritten keeps a value that a custom handler writes as a struct, values it writes as a
+// PlainValue are replaced by that value
+func (marshaller *paramMarshaller) marshalWritten(handler ValueHandler, value interface{}, path string, visiting visits) (interface{}, error) {
+	signature, fields, err := handler.Write(value)
+	if err != nil {
+		return nil, newDriverError("unable to marshal parameter '%s': %v", path, err)
+	}
+
+	if signature != PlainValue {
+		return value, nil
+	}
+
+	if len(fields) != 1 {
+		return nil, newDriverError("unable to marshal parameter '%s': expected a plain value to have 1 field but it had %d", path, len(fields))
+	}
+
+	return marshaller.marshalValue(fields[0], path, visiting)
+}
+
+// marshalStruct adds the fields of a struct to dict, flattening embedded structs
+func (marshaller *paramMarshaller) marshalStruct(value reflect.Value, dict map[string]interface{}, path string, visiting visits) error {
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		tag := field.Tag.Get(scanTag)
+
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := marshaller.marshalStruct(value.Field(i), dict, path, visiting); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// unexported fields are left out
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag != "" {
+			name = tag
+		}
+
+		item, err := marshaller.marshalValue(value.Field(i).Interface(), path+"."+name, visiting)
+		if err != nil {
+			return err
+		}
+		dict[name] = item
+	}
+
+	return nil
+}
+
+// visit identifies a pointer, map or slice by the memory it refers to along with its type, a
+// pointer to a struct and to its first field share the address
+type visit struct {
+	address   uintptr
+	valueType reflect.Type
+	length    int
+}
+
+// visits holds the pointers, maps and slices that enclose the value being marshalled
+type visits map[visit]bool
+
+// enter adds a pointer, map or slice to the enclosing ones and returns a function that
+// removes it again, it fails when the value is already enclosing itself
+func (visiting visits) enter(value reflect.Value, path string) (func(), error) {
+	key := visit{address: value.Pointer(), valueType: value.Type()}
+	if value.Kind() == reflect.Slice {
+		key.length = value.Len()
+	}
+
+	if visiting[key] {
+		return nil, newDriverError("unable to marshal parameter '%s': values that contain themselves are not supported", path)
+	}
+
+	visiting[key] = true
+	return func() {
+		delete(visiting, key)
+	}, nil
+}
@@ -0,0 +1,228 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	. "github.com/neo4j/neo4j-go-driver/neo4j/utils/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type decimal struct {
+	units    int64
+	exponent int
+}
+
+type decimalHandler struct {
+	signature int16
+}
+
+func (handler *decimalHandler) ReadableStructs() []int16 {
+	return nil
+}
+
+func (handler *decimalHandler) WritableTypes() []reflect.Type {
+	return []reflect.Type{reflect.TypeOf(decimal{})}
+}
+
+func (handler *decimalHandler) Read(signature int16, values []interface{}) (interface{}, error) {
+	return nil, errors.New("not readable")
+}
+
+func (handler *decimalHandler) Write(value interface{}) (int16, []interface{}, error) {
+	d := value.(decimal)
+	if d.exponent != 0 {
+		return 0, nil, errors.New("unsupported exponent")
+	}
+	return handler.signature, []interface{}{fmt.Sprintf("%d", d.units)}, nil
+}
+
+type celsius float64
+
+func (c celsius) MarshalNeo4j() (interface{}, error) {
+	if c < -273.15 {
+		return nil, errors.New("below absolute zero")
+	}
+	return map[string]interface{}{"value": float64(c), "unit": "C"}, nil
+}
+
+var _ = Describe("Parameter Marshalling", func() {
+	type base struct {
+		ID int64 `neo4j:"id"`
+	}
+
+	type person struct {
+		base
+		Name    string    `neo4j:"name"`
+		Tags    []string  `neo4j:"tags"`
+		Manager *person   `neo4j:"manager"`
+		Born    time.Time `neo4j:"born"`
+		Temp    celsius   `neo4j:"temp"`
+		Secret  string    `neo4j:"-"`
+		Visible bool
+		hidden  bool
+	}
+
+	It("should keep supported values as they are", func() {
+		now := time.Now()
+		params := map[string]interface{}{
+			"nil":   nil,
+			"int":   1,
+			"float": 1.5,
+			"str":   "s",
+			"bytes": []byte{1, 2},
+			"time":  now,
+			"date":  DateOf(now),
+			"point": NewPoint2D(1, 2, 3),
+			"span":  time.Second,
+		}
+
+		marshalled, err := newParamMarshaller(nil).marshalParams(params)
+
+		Expect(err).To(BeNil())
+		Expect(marshalled).To(Equal(params))
+	})
+
+	It("should convert structs into maps", func() {
+		born := time.Date(1980, time.May, 4, 0, 0, 0, 0, time.UTC)
+		alice := &person{base: base{ID: 1}, Name: "Alice", Tags: []string{"a"}, Born: born, Temp: 21.5, Secret: "x", Visible: true, hidden: true}
+		bob := person{Name: "Bob", Manager: alice}
+
+		marshalled, err := newParamMarshaller(nil).marshalParams(map[string]interface{}{"p": bob})
+
+		Expect(err).To(BeNil())
+		Expect(marshalled).To(Equal(map[string]interface{}{
+			"p": map[string]interface{}{
+				"id":      int64(0),
+				"name":    "Bob",
+				"tags":    nil,
+				"born":    time.Time{},
+				"temp":    map[string]interface{}{"value": 0.0, "unit": "C"},
+				"Visible": false,
+				"manager": map[string]interface{}{
+					"id":      int64(1),
+					"name":    "Alice",
+					"tags":    []interface{}{"a"},
+					"manager": nil,
+					"born":    born,
+					"temp":    map[string]interface{}{"value": 21.5, "unit": "C"},
+					"Visible": true,
+				},
+			},
+		}))
+	})
+
+	It("should convert typed collections and named types", func() {
+		type level int8
+		type label string
+
+		marshalled, err := newParamMarshaller(nil).marshalParams(map[string]interface{}{
+			"levels": []level{1, 2},
+			"labels": map[string]label{"a": "b"},
+			"array":  [2]uint{3, 4},
+			"bytes":  [2]byte{5, 6},
+		})
+
+		Expect(err).To(BeNil())
+		Expect(marshalled).To(Equal(map[string]interface{}{
+			"levels": []interface{}{int64(1), int64(2)},
+			"labels": map[string]interface{}{"a": "b"},
+			"array":  []interface{}{int64(3), int64(4)},
+			"bytes":  []byte{5, 6},
+		}))
+	})
+
+	It("should replace values that custom handlers write as plain values", func() {
+		marshaller := newParamMarshaller([]ValueHandler{&decimalHandler{signature: PlainValue}})
+
+		marshalled, err := marshaller.marshalParams(map[string]interface{}{"d": []decimal{{units: 42}}})
+
+		Expect(err).To(BeNil())
+		Expect(marshalled).To(Equal(map[string]interface{}{"d": []interface{}{"42"}}))
+
+		_, err = marshaller.marshalParams(map[string]interface{}{"d": decimal{exponent: 1}})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'd': unsupported exponent")))
+	})
+
+	It("should keep values that custom handlers write as structs", func() {
+		marshaller := newParamMarshaller([]ValueHandler{&decimalHandler{signature: 'D'}})
+
+		marshalled, err := marshaller.marshalParams(map[string]interface{}{"d": decimal{units: 42}})
+
+		Expect(err).To(BeNil())
+		Expect(marshalled).To(Equal(map[string]interface{}{"d": decimal{units: 42}}))
+	})
+
+	It("should register custom handlers after the ones of the driver", func() {
+		custom := &decimalHandler{signature: 'D'}
+
+		handlers := valueHandlers([]ValueHandler{custom})
+
+		Expect(handlers).To(HaveLen(11))
+		Expect(handlers[10]).To(BeIdenticalTo(custom))
+	})
+
+	It("should report the path of values that cannot be marshalled", func() {
+		_, err := newParamMarshaller(nil).marshalParams(map[string]interface{}{"p": person{Tags: nil, Temp: -300}})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'p.temp': below absolute zero")))
+
+		_, err = newParamMarshaller(nil).marshalParams(map[string]interface{}{"m": map[string]interface{}{"l": []interface{}{1, func() {}}}})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'm.l[1]': values of type func() are not supported")))
+
+		_, err = newParamMarshaller(nil).marshalParams(map[string]interface{}{"m": map[int]string{}})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'm': values of type map[int]string are not supported")))
+
+		_, err = newParamMarshaller(nil).marshalParams(map[string]interface{}{"u": uint64(math.MaxUint64)})
+		Expect(err).To(BeGenericError(ContainSubstring("overflows int64")))
+	})
+
+	It("should reject values that contain themselves", func() {
+		boss := &person{Name: "Alice"}
+		boss.Manager = boss
+		_, err := newParamMarshaller(nil).marshalParams(map[string]interface{}{"p": boss})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'p.manager': values that contain themselves are not supported")))
+
+		list := []interface{}{1, nil}
+		list[1] = list
+		_, err = newParamMarshaller(nil).marshalParams(map[string]interface{}{"l": list})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'l[1]': values that contain themselves are not supported")))
+
+		dict := map[string]interface{}{}
+		dict["self"] = dict
+		_, err = newParamMarshaller(nil).marshalParams(map[string]interface{}{"m": dict})
+		Expect(err).To(BeGenericError(Equal("unable to marshal parameter 'm.self': values that contain themselves are not supported")))
+	})
+
+	It("should marshal values that are shared but do not contain themselves", func() {
+		manager := &person{Name: "Alice"}
+		team := []*person{{Name: "Bob", Manager: manager}, {Name: "Carol", Manager: manager}}
+
+		marshalled, err := newParamMarshaller(nil).marshalParams(map[string]interface{}{"team": team, "manager": manager})
+
+		Expect(err).To(BeNil())
+		Expect(marshalled["team"]).To(HaveLen(2))
+	})
+})
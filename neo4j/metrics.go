@@ -0,0 +1,98 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+// Metrics is a snapshot of the connection pools of a driver.
+type Metrics struct {
+	// Time the snapshot was taken at.
+	Time time.Time
+	// ConnectionPools holds the metrics of the connection pool of each server the driver
+	// keeps connections to, keyed by the address of the server. The counters of a cluster
+	// member start over when it rejoins the cluster after being removed.
+	ConnectionPools map[string]ConnectionPoolMetrics
+}
+
+// ConnectionPoolMetrics describes the connection pool of a single server.
+type ConnectionPoolMetrics struct {
+	// Address of the server.
+	Address string
+	// InUse is the number of connections that are acquired by sessions.
+	InUse int
+	// Idle is the number of connections that wait in the pool for being acquired.
+	Idle int
+	// Created is the number of connections that were opened.
+	Created int64
+	// Closed is the number of connections that were closed.
+	Closed int64
+	// FailedToCreate is the number of connections that could not be opened.
+	FailedToCreate int64
+	// AcquisitionTimeouts is the number of acquisitions that failed because all connections
+	// stayed in use for longer than ConnectionAcquisitionTimeout.
+	AcquisitionTimeouts int64
+	// AcquisitionLatency counts the time it took to acquire connections, including the time
+	// it took to open new ones.
+	AcquisitionLatency LatencyHistogram
+}
+
+// LatencyHistogram counts latencies in buckets.
+type LatencyHistogram struct {
+	// Bounds are the inclusive upper bounds of the buckets in ascending order.
+	Bounds []time.Duration
+	// Counts holds the number of latencies per bucket, it has one more element than Bounds
+	// that counts the latencies above the last bound.
+	Counts []int64
+	// Count is the total number of latencies.
+	Count int64
+	// Sum is the total of all latencies.
+	Sum time.Duration
+}
+
+func newMetrics(pools []bolt.PoolMetrics) Metrics {
+	metrics := Metrics{
+		Time:            time.Now(),
+		ConnectionPools: make(map[string]ConnectionPoolMetrics, len(pools)),
+	}
+
+	for _, pool := range pools {
+		metrics.ConnectionPools[pool.Address] = ConnectionPoolMetrics{
+			Address:             pool.Address,
+			InUse:               pool.InUse,
+			Idle:                pool.Idle,
+			Created:             pool.Created,
+			Closed:              pool.Closed,
+			FailedToCreate:      pool.FailedToCreate,
+			AcquisitionTimeouts: pool.AcquisitionTimeouts,
+			AcquisitionLatency: LatencyHistogram{
+				Bounds: pool.AcquisitionLatency.Bounds,
+				Counts: pool.AcquisitionLatency.Counts,
+				Count:  pool.AcquisitionLatency.Count,
+				Sum:    pool.AcquisitionLatency.Sum,
+			},
+		}
+	}
+
+	return metrics
+}
@@ -24,10 +24,11 @@
 package neo4j
 
 import (
+	"context"
 	"reflect"
 	"time"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 
 	"github.com/golang/mock/gomock"
 )
@@ -94,10 +95,46 @@ func (mr *MockConnectionMockRecorder) Server() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Server", reflect.TypeOf((*MockConnection)(nil).Server))
 }
 
+// ProtocolVersion connector-mocks base method
+func (m *MockConnection) ProtocolVersion() (int, error) {
+	ret := m.ctrl.Call(m, "ProtocolVersion")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProtocolVersion indicates an expected call of ProtocolVersion
+func (mr *MockConnectionMockRecorder) ProtocolVersion() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProtocolVersion", reflect.TypeOf((*MockConnection)(nil).ProtocolVersion))
+}
+
+// ServerConnectionId connector-mocks base method
+func (m *MockConnection) ServerConnectionId() (string, error) {
+	ret := m.ctrl.Call(m, "ServerConnectionId")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServerConnectionId indicates an expected call of ServerConnectionId
+func (mr *MockConnectionMockRecorder) ServerConnectionId() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServerConnectionId", reflect.TypeOf((*MockConnection)(nil).ServerConnectionId))
+}
+
+// SetBoltLogger connector-mocks base method
+func (m *MockConnection) SetBoltLogger(arg0 bolt.BoltLogger) {
+	m.ctrl.Call(m, "SetBoltLogger", arg0)
+}
+
+// SetBoltLogger indicates an expected call of SetBoltLogger
+func (mr *MockConnectionMockRecorder) SetBoltLogger(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBoltLogger", reflect.TypeOf((*MockConnection)(nil).SetBoltLogger), arg0)
+}
+
 // Begin connector-mocks base method
-func (m *MockConnection) Begin(arg0 []string, arg1 time.Duration, arg2 map[string]interface{}) (gobolt.RequestHandle, error) {
+func (m *MockConnection) Begin(arg0 []string, arg1 time.Duration, arg2 map[string]interface{}) (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "Begin", arg0, arg1, arg2)
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -120,9 +157,9 @@ func (mr *MockConnectionMockRecorder) Close() *gomock.Call {
 }
 
 // Commit connector-mocks base method
-func (m *MockConnection) Commit() (gobolt.RequestHandle, error) {
+func (m *MockConnection) Commit() (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "Commit")
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -145,10 +182,23 @@ func (mr *MockConnectionMockRecorder) Data() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Data", reflect.TypeOf((*MockConnection)(nil).Data))
 }
 
+// Discard connector-mocks base method
+func (m *MockConnection) Discard(arg0 int, arg1 int64) (bolt.RequestHandle, error) {
+	ret := m.ctrl.Call(m, "Discard", arg0, arg1)
+	ret0, _ := ret[0].(bolt.RequestHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Discard indicates an expected call of Discard
+func (mr *MockConnectionMockRecorder) Discard(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Discard", reflect.TypeOf((*MockConnection)(nil).Discard), arg0, arg1)
+}
+
 // DiscardAll connector-mocks base method
-func (m *MockConnection) DiscardAll() (gobolt.RequestHandle, error) {
+func (m *MockConnection) DiscardAll() (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "DiscardAll")
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -159,9 +209,9 @@ func (mr *MockConnectionMockRecorder) DiscardAll() *gomock.Call {
 }
 
 // Fetch connector-mocks base method
-func (m *MockConnection) Fetch(arg0 gobolt.RequestHandle) (gobolt.FetchType, error) {
+func (m *MockConnection) Fetch(arg0 bolt.RequestHandle) (bolt.FetchType, error) {
 	ret := m.ctrl.Call(m, "Fetch", arg0)
-	ret0, _ := ret[0].(gobolt.FetchType)
+	ret0, _ := ret[0].(bolt.FetchType)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -171,8 +221,21 @@ func (mr *MockConnectionMockRecorder) Fetch(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockConnection)(nil).Fetch), arg0)
 }
 
+// FetchContext connector-mocks base method
+func (m *MockConnection) FetchContext(arg0 context.Context, arg1 bolt.RequestHandle) (bolt.FetchType, error) {
+	ret := m.ctrl.Call(m, "FetchContext", arg0, arg1)
+	ret0, _ := ret[0].(bolt.FetchType)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchContext indicates an expected call of FetchContext
+func (mr *MockConnectionMockRecorder) FetchContext(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchContext", reflect.TypeOf((*MockConnection)(nil).FetchContext), arg0, arg1)
+}
+
 // FetchSummary connector-mocks base method
-func (m *MockConnection) FetchSummary(arg0 gobolt.RequestHandle) (int, error) {
+func (m *MockConnection) FetchSummary(arg0 bolt.RequestHandle) (int, error) {
 	ret := m.ctrl.Call(m, "FetchSummary", arg0)
 	ret0, _ := ret[0].(int)
 	ret1, _ := ret[1].(error)
@@ -235,10 +298,23 @@ func (mr *MockConnectionMockRecorder) Metadata() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metadata", reflect.TypeOf((*MockConnection)(nil).Metadata))
 }
 
+// Pull connector-mocks base method
+func (m *MockConnection) Pull(arg0 int, arg1 int64) (bolt.RequestHandle, error) {
+	ret := m.ctrl.Call(m, "Pull", arg0, arg1)
+	ret0, _ := ret[0].(bolt.RequestHandle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pull indicates an expected call of Pull
+func (mr *MockConnectionMockRecorder) Pull(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pull", reflect.TypeOf((*MockConnection)(nil).Pull), arg0, arg1)
+}
+
 // PullAll connector-mocks base method
-func (m *MockConnection) PullAll() (gobolt.RequestHandle, error) {
+func (m *MockConnection) PullAll() (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "PullAll")
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -249,9 +325,9 @@ func (mr *MockConnectionMockRecorder) PullAll() *gomock.Call {
 }
 
 // Reset connector-mocks base method
-func (m *MockConnection) Reset() (gobolt.RequestHandle, error) {
+func (m *MockConnection) Reset() (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "Reset")
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -262,9 +338,9 @@ func (mr *MockConnectionMockRecorder) Reset() *gomock.Call {
 }
 
 // Rollback connector-mocks base method
-func (m *MockConnection) Rollback() (gobolt.RequestHandle, error) {
+func (m *MockConnection) Rollback() (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "Rollback")
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -275,9 +351,9 @@ func (mr *MockConnectionMockRecorder) Rollback() *gomock.Call {
 }
 
 // Run connector-mocks base method
-func (m *MockConnection) Run(arg0 string, arg1 map[string]interface{}, arg2 []string, arg3 time.Duration, arg4 map[string]interface{}) (gobolt.RequestHandle, error) {
+func (m *MockConnection) Run(arg0 string, arg1 map[string]interface{}, arg2 []string, arg3 time.Duration, arg4 map[string]interface{}) (bolt.RequestHandle, error) {
 	ret := m.ctrl.Call(m, "Run", arg0, arg1, arg2, arg3, arg4)
-	ret0, _ := ret[0].(gobolt.RequestHandle)
+	ret0, _ := ret[0].(bolt.RequestHandle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -24,9 +24,10 @@
 package neo4j
 
 import (
+	context "context"
 	reflect "reflect"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -66,15 +67,39 @@ func (mr *MockConnectorMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockConnector)(nil).Close))
 }
 
+// VerifyConnectivity connector-mocks base method
+func (m *MockConnector) VerifyConnectivity(arg0 context.Context) error {
+	ret := m.ctrl.Call(m, "VerifyConnectivity", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyConnectivity indicates an expected call of VerifyConnectivity
+func (mr *MockConnectorMockRecorder) VerifyConnectivity(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyConnectivity", reflect.TypeOf((*MockConnector)(nil).VerifyConnectivity), arg0)
+}
+
+// Metrics connector-mocks base method
+func (m *MockConnector) Metrics() []bolt.PoolMetrics {
+	ret := m.ctrl.Call(m, "Metrics")
+	ret0, _ := ret[0].([]bolt.PoolMetrics)
+	return ret0
+}
+
+// Metrics indicates an expected call of Metrics
+func (mr *MockConnectorMockRecorder) Metrics() *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Metrics", reflect.TypeOf((*MockConnector)(nil).Metrics))
+}
+
 // GetPool connector-mocks base method
-func (m *MockConnector) Acquire(arg0 gobolt.AccessMode) (gobolt.Connection, error) {
-	ret := m.ctrl.Call(m, "Acquire", arg0)
-	ret0, _ := ret[0].(gobolt.Connection)
+func (m *MockConnector) Acquire(arg0 context.Context, arg1 bolt.AccessMode, arg2 string) (bolt.Connection, error) {
+	ret := m.ctrl.Call(m, "Acquire", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bolt.Connection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetPool indicates an expected call of GetPool
-func (mr *MockConnectorMockRecorder) Acquire(arg0 gobolt.AccessMode) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockConnector)(nil).Acquire), arg0)
+func (mr *MockConnectorMockRecorder) Acquire(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockConnector)(nil).Acquire), arg0, arg1, arg2)
 }
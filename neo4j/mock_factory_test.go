@@ -19,22 +19,34 @@
 
 package neo4j
 
-import "github.com/neo4j-drivers/gobolt"
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
 
 type mockConnector struct {
 	connection *MockConnection
 }
 
-func (connector *mockConnector) Acquire(mode gobolt.AccessMode) (gobolt.Connection, error) {
+func (connector *mockConnector) Acquire(ctx context.Context, mode bolt.AccessMode, database string) (bolt.Connection, error) {
 	return connector.connection, nil
 }
 
+func (connector *mockConnector) VerifyConnectivity(ctx context.Context) error {
+	return nil
+}
+
+func (connector *mockConnector) Metrics() []bolt.PoolMetrics {
+	return nil
+}
+
 func (connector *mockConnector) Close() error {
 	return connector.connection.Close()
 }
 
 // MockedConnector returns a mocked connector with the provided mocked connection
-func MockedConnector(connection *MockConnection) gobolt.Connector {
+func MockedConnector(connection *MockConnection) bolt.Connector {
 	return &mockConnector{
 		connection: connection,
 	}
@@ -26,7 +26,7 @@ package neo4j
 import (
 	reflect "reflect"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -55,9 +55,9 @@ func (m *MockPool) EXPECT() *MockPoolMockRecorder {
 }
 
 // Acquire connector-mocks base method
-func (m *MockPool) Acquire() (gobolt.Connection, error) {
+func (m *MockPool) Acquire() (bolt.Connection, error) {
 	ret := m.ctrl.Call(m, "Acquire")
-	ret0, _ := ret[0].(gobolt.Connection)
+	ret0, _ := ret[0].(bolt.Connection)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
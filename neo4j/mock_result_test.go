@@ -24,6 +24,7 @@
 package neo4j
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
@@ -65,6 +66,19 @@ func (mr *MockResultMockRecorder) Consume() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Consume", reflect.TypeOf((*MockResult)(nil).Consume))
 }
 
+// ConsumeContext mocks base method
+func (m *MockResult) ConsumeContext(arg0 context.Context) (ResultSummary, error) {
+	ret := m.ctrl.Call(m, "ConsumeContext", arg0)
+	ret0, _ := ret[0].(ResultSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConsumeContext indicates an expected call of ConsumeContext
+func (mr *MockResultMockRecorder) ConsumeContext(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeContext", reflect.TypeOf((*MockResult)(nil).ConsumeContext), arg0)
+}
+
 // Err mocks base method
 func (m *MockResult) Err() error {
 	ret := m.ctrl.Call(m, "Err")
@@ -102,6 +116,18 @@ func (mr *MockResultMockRecorder) Next() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Next", reflect.TypeOf((*MockResult)(nil).Next))
 }
 
+// NextContext mocks base method
+func (m *MockResult) NextContext(arg0 context.Context) bool {
+	ret := m.ctrl.Call(m, "NextContext", arg0)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// NextContext indicates an expected call of NextContext
+func (mr *MockResultMockRecorder) NextContext(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NextContext", reflect.TypeOf((*MockResult)(nil).NextContext), arg0)
+}
+
 // Record mocks base method
 func (m *MockResult) Record() Record {
 	ret := m.ctrl.Call(m, "Record")
@@ -114,6 +140,31 @@ func (mr *MockResultMockRecorder) Record() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockResult)(nil).Record))
 }
 
+// Scan mocks base method
+func (m *MockResult) Scan(arg0 interface{}) error {
+	ret := m.ctrl.Call(m, "Scan", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Scan indicates an expected call of Scan
+func (mr *MockResultMockRecorder) Scan(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Scan", reflect.TypeOf((*MockResult)(nil).Scan), arg0)
+}
+
+// Stream mocks base method
+func (m *MockResult) Stream(arg0 context.Context) (<-chan Record, <-chan error) {
+	ret := m.ctrl.Call(m, "Stream", arg0)
+	ret0, _ := ret[0].(<-chan Record)
+	ret1, _ := ret[1].(<-chan error)
+	return ret0, ret1
+}
+
+// Stream indicates an expected call of Stream
+func (mr *MockResultMockRecorder) Stream(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stream", reflect.TypeOf((*MockResult)(nil).Stream), arg0)
+}
+
 // Summary mocks base method
 func (m *MockResult) Summary() (ResultSummary, error) {
 	ret := m.ctrl.Call(m, "Summary")
@@ -0,0 +1,43 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import "time"
+
+// Observer is notified about the work carried out by a driver, e.g. to collect metrics
+// about statements and retries. Its methods are called synchronously by the sessions of the
+// driver and should return quickly.
+type Observer interface {
+	// StatementCompleted is called once a statement run in a session or in a transaction
+	// has completed. The summary tells the statement and the server it ran on, its timings
+	// are only known when err is nil. A statement that could not be sent to a server, e.g.
+	// because no connection could be acquired, completes with that error.
+	StatementCompleted(summary ResultSummary, err error)
+	// TransactionRetried is called when an attempt of a transaction function failed with
+	// the retriable err and another attempt is made after delay. Attempts are counted from 1.
+	TransactionRetried(attempt int, delay time.Duration, err error)
+}
+
+// statementCompleted notifies the observers of the driver that a statement completed
+func (driver *neoDriver) statementCompleted(summary ResultSummary, err error) {
+	for _, observer := range driver.config.Observers {
+		observer.StatementCompleted(summary, err)
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package opentelemetry hands the spans of a driver over to OpenTelemetry:
+//
+//	driver, err := neo4j.NewDriver(uri, auth, func(config *neo4j.Config) {
+//		config.Tracer = opentelemetry.NewTracer(otel.Tracer("neo4j"))
+//	})
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracer struct {
+	delegate trace.Tracer
+}
+
+type span struct {
+	delegate trace.Span
+}
+
+// NewTracer returns a tracer that creates its spans with the given OpenTelemetry tracer, the
+// spans are of the client kind
+func NewTracer(delegate trace.Tracer) neo4j.Tracer {
+	return &tracer{delegate: delegate}
+}
+
+func (tracer *tracer) StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, neo4j.Span) {
+	ctx, delegate := tracer.delegate.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(keyValues(attributes)...))
+	return ctx, &span{delegate: delegate}
+}
+
+func (span *span) SetAttributes(attributes map[string]interface{}) {
+	span.delegate.SetAttributes(keyValues(attributes)...)
+}
+
+func (span *span) End(err error) {
+	if err != nil {
+		span.delegate.RecordError(err)
+		span.delegate.SetStatus(codes.Error, err.Error())
+	}
+	span.delegate.End()
+}
+
+// keyValues converts the attributes of the driver, values of unexpected types are converted
+// to their string representation
+func keyValues(attributes map[string]interface{}) []attribute.KeyValue {
+	keyValues := make([]attribute.KeyValue, 0, len(attributes))
+	for key, value := range attributes {
+		switch v := value.(type) {
+		case string:
+			keyValues = append(keyValues, attribute.String(key, v))
+		case int:
+			keyValues = append(keyValues, attribute.Int(key, v))
+		case int64:
+			keyValues = append(keyValues, attribute.Int64(key, v))
+		case bool:
+			keyValues = append(keyValues, attribute.Bool(key, v))
+		case float64:
+			keyValues = append(keyValues, attribute.Float64(key, v))
+		default:
+			keyValues = append(keyValues, attribute.String(key, fmt.Sprint(v)))
+		}
+	}
+	return keyValues
+}
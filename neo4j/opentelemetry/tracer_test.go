@@ -0,0 +1,95 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package opentelemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	createTracer := func() (*tracetest.SpanRecorder, *tracer) {
+		recorder := tracetest.NewSpanRecorder()
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		return recorder, NewTracer(provider.Tracer("neo4j")).(*tracer)
+	}
+
+	t.Run("shouldCreateClientSpansWithAttributes", func(t *testing.T) {
+		recorder, tracer := createTracer()
+
+		_, span := tracer.StartSpan(context.Background(), "neo4j.run", map[string]interface{}{"db.system": "neo4j", "neo4j.attempt": 2})
+		span.SetAttributes(map[string]interface{}{"neo4j.result_available_after_ms": int64(5), "neo4j.contains_updates": true})
+		span.End(nil)
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, "neo4j.run", ended[0].Name())
+		assert.Equal(t, trace.SpanKindClient, ended[0].SpanKind())
+		assert.Equal(t, codes.Unset, ended[0].Status().Code)
+		assert.ElementsMatch(t, []attribute.KeyValue{
+			attribute.String("db.system", "neo4j"),
+			attribute.Int("neo4j.attempt", 2),
+			attribute.Int64("neo4j.result_available_after_ms", 5),
+			attribute.Bool("neo4j.contains_updates", true),
+		}, ended[0].Attributes())
+	})
+
+	t.Run("shouldNestSpansThroughTheContext", func(t *testing.T) {
+		recorder, tracer := createTracer()
+
+		ctx, parent := tracer.StartSpan(context.Background(), "neo4j.transaction", nil)
+		_, child := tracer.StartSpan(ctx, "neo4j.commit", nil)
+		child.End(nil)
+		parent.End(nil)
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 2)
+		assert.Equal(t, ended[1].SpanContext().SpanID(), ended[0].Parent().SpanID())
+		assert.Equal(t, ended[1].SpanContext().TraceID(), ended[0].SpanContext().TraceID())
+	})
+
+	t.Run("shouldRecordErrors", func(t *testing.T) {
+		recorder, tracer := createTracer()
+
+		_, span := tracer.StartSpan(context.Background(), "neo4j.run", nil)
+		span.End(errors.New("connection reset"))
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, codes.Error, ended[0].Status().Code)
+		assert.Equal(t, "connection reset", ended[0].Status().Description)
+		require.Len(t, ended[0].Events(), 1)
+		assert.Equal(t, "exception", ended[0].Events()[0].Name)
+	})
+
+	t.Run("shouldConvertUnexpectedValuesToStrings", func(t *testing.T) {
+		assert.Equal(t, []attribute.KeyValue{attribute.String("timeout", "1s")}, keyValues(map[string]interface{}{"timeout": time.Second}))
+	})
+}
@@ -0,0 +1,54 @@
+//go:build gofuzz
+// +build gofuzz
+
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packstream
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+// Fuzz is the entry point for go-fuzz, every value that can be decoded must survive a round
+// trip through the packer unchanged
+func Fuzz(data []byte) int {
+	value, err := Unmarshal(data)
+	if err != nil {
+		return 0
+	}
+
+	encoded, err := Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("unable to encode decoded value %v: %v", value, err))
+	}
+
+	decoded, err := Unmarshal(encoded)
+	if err != nil {
+		panic(fmt.Sprintf("unable to decode encoded value %v: %v", value, err))
+	}
+
+	if !reflect.DeepEqual(value, decoded) && !bytes.Equal(data, encoded) {
+		panic(fmt.Sprintf("value %v changed to %v after a round trip", value, decoded))
+	}
+
+	return 1
+}
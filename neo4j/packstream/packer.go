@@ -0,0 +1,354 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packstream
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// ValueMapper converts values that have no native PackStream representation into a
+// Structure. It returns a nil Structure for values it does not handle.
+type ValueMapper func(value interface{}) (*Structure, error)
+
+// Packer writes PackStream values to an underlying writer
+type Packer struct {
+	w      io.Writer
+	mapper ValueMapper
+	buf    [9]byte
+}
+
+// NewPacker returns a packer that writes to w
+func NewPacker(w io.Writer) *Packer {
+	return &Packer{w: w}
+}
+
+// SetValueMapper registers a mapper that is consulted by Pack before falling back to
+// reflection for values of types without a native representation
+func (p *Packer) SetValueMapper(mapper ValueMapper) {
+	p.mapper = mapper
+}
+
+// PackNil writes a null
+func (p *Packer) PackNil() error {
+	return p.writeMarker(markerNull)
+}
+
+// PackBool writes a boolean
+func (p *Packer) PackBool(b bool) error {
+	if b {
+		return p.writeMarker(markerTrue)
+	}
+
+	return p.writeMarker(markerFalse)
+}
+
+// PackInt writes an integer using the smallest possible representation
+func (p *Packer) PackInt(i int64) error {
+	switch {
+	case -0x10 <= i && i < 0x80:
+		return p.writeMarker(byte(int8(i)))
+	case math.MinInt8 <= i && i <= math.MaxInt8:
+		p.buf[0], p.buf[1] = markerInt8, byte(int8(i))
+		return p.write(p.buf[:2])
+	case math.MinInt16 <= i && i <= math.MaxInt16:
+		p.buf[0] = markerInt16
+		binary.BigEndian.PutUint16(p.buf[1:], uint16(int16(i)))
+		return p.write(p.buf[:3])
+	case math.MinInt32 <= i && i <= math.MaxInt32:
+		p.buf[0] = markerInt32
+		binary.BigEndian.PutUint32(p.buf[1:], uint32(int32(i)))
+		return p.write(p.buf[:5])
+	default:
+		p.buf[0] = markerInt64
+		binary.BigEndian.PutUint64(p.buf[1:], uint64(i))
+		return p.write(p.buf[:9])
+	}
+}
+
+// PackFloat writes a 64-bit floating point number
+func (p *Packer) PackFloat(f float64) error {
+	p.buf[0] = markerFloat
+	binary.BigEndian.PutUint64(p.buf[1:], math.Float64bits(f))
+	return p.write(p.buf[:9])
+}
+
+// PackString writes an UTF-8 string
+func (p *Packer) PackString(s string) error {
+	if err := p.writeHeader(len(s), markerTinyString, markerString8, markerString16, markerString32); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(p.w, s)
+	return err
+}
+
+// PackBytes writes a byte array
+func (p *Packer) PackBytes(b []byte) error {
+	size := len(b)
+	switch {
+	case size <= math.MaxUint8:
+		p.buf[0], p.buf[1] = markerBytes8, byte(size)
+		if err := p.write(p.buf[:2]); err != nil {
+			return err
+		}
+	case size <= math.MaxUint16:
+		p.buf[0] = markerBytes16
+		binary.BigEndian.PutUint16(p.buf[1:], uint16(size))
+		if err := p.write(p.buf[:3]); err != nil {
+			return err
+		}
+	case int64(size) <= math.MaxUint32:
+		p.buf[0] = markerBytes32
+		binary.BigEndian.PutUint32(p.buf[1:], uint32(size))
+		if err := p.write(p.buf[:5]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("packstream: byte array of size %d is too large", size)
+	}
+
+	return p.write(b)
+}
+
+// PackListHeader writes the header of a list, which must be followed by size values
+func (p *Packer) PackListHeader(size int) error {
+	return p.writeHeader(size, markerTinyList, markerList8, markerList16, markerList32)
+}
+
+// PackMapHeader writes the header of a map, which must be followed by size key and value
+// pairs where keys are strings
+func (p *Packer) PackMapHeader(size int) error {
+	return p.writeHeader(size, markerTinyMap, markerMap8, markerMap16, markerMap32)
+}
+
+// PackStructHeader writes the header of a struct, which must be followed by size values
+func (p *Packer) PackStructHeader(size int, signature byte) error {
+	switch {
+	case size < 0x10:
+		p.buf[0], p.buf[1] = markerTinyStruct|byte(size), signature
+		return p.write(p.buf[:2])
+	case size <= math.MaxUint8:
+		p.buf[0], p.buf[1], p.buf[2] = markerStruct8, byte(size), signature
+		return p.write(p.buf[:3])
+	case size <= math.MaxUint16:
+		p.buf[0] = markerStruct16
+		binary.BigEndian.PutUint16(p.buf[1:], uint16(size))
+		p.buf[3] = signature
+		return p.write(p.buf[:4])
+	}
+
+	return fmt.Errorf("packstream: struct with %d fields is too large", size)
+}
+
+// Pack writes the provided value, including all values nested in it
+func (p *Packer) Pack(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return p.PackNil()
+	case bool:
+		return p.PackBool(v)
+	case int:
+		return p.PackInt(int64(v))
+	case int8:
+		return p.PackInt(int64(v))
+	case int16:
+		return p.PackInt(int64(v))
+	case int32:
+		return p.PackInt(int64(v))
+	case int64:
+		return p.PackInt(v)
+	case uint8:
+		return p.PackInt(int64(v))
+	case uint16:
+		return p.PackInt(int64(v))
+	case uint32:
+		return p.PackInt(int64(v))
+	case uint:
+		return p.packUint(uint64(v), value)
+	case uint64:
+		return p.packUint(v, value)
+	case float32:
+		return p.PackFloat(float64(v))
+	case float64:
+		return p.PackFloat(v)
+	case string:
+		return p.PackString(v)
+	case []byte:
+		return p.PackBytes(v)
+	case []interface{}:
+		if err := p.PackListHeader(len(v)); err != nil {
+			return err
+		}
+		for _, item := range v {
+			if err := p.Pack(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if err := p.PackMapHeader(len(v)); err != nil {
+			return err
+		}
+		for key, item := range v {
+			if err := p.PackString(key); err != nil {
+				return err
+			}
+			if err := p.Pack(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case *Structure:
+		if v == nil {
+			return p.PackNil()
+		}
+		return p.packStructure(v)
+	case Structure:
+		return p.packStructure(&v)
+	}
+
+	if p.mapper != nil {
+		structure, err := p.mapper(value)
+		if err != nil {
+			return err
+		}
+		if structure != nil {
+			return p.packStructure(structure)
+		}
+	}
+
+	return p.packReflected(reflect.ValueOf(value))
+}
+
+func (p *Packer) packUint(v uint64, value interface{}) error {
+	if v > math.MaxInt64 {
+		return fmt.Errorf("packstream: value %d of type %T is out of range of a signed 64-bit integer", v, value)
+	}
+
+	return p.PackInt(int64(v))
+}
+
+func (p *Packer) packStructure(structure *Structure) error {
+	if err := p.PackStructHeader(len(structure.Fields), structure.Signature); err != nil {
+		return err
+	}
+
+	for _, field := range structure.Fields {
+		if err := p.Pack(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Packer) packReflected(value reflect.Value) error {
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if value.IsNil() {
+			return p.PackNil()
+		}
+		return p.Pack(value.Elem().Interface())
+	case reflect.Bool:
+		return p.PackBool(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return p.PackInt(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return p.packUint(value.Uint(), value.Interface())
+	case reflect.Float32, reflect.Float64:
+		return p.PackFloat(value.Float())
+	case reflect.String:
+		return p.PackString(value.String())
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return p.PackNil()
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			bytes := make([]byte, value.Len())
+			reflect.Copy(reflect.ValueOf(bytes), value)
+			return p.PackBytes(bytes)
+		}
+		if err := p.PackListHeader(value.Len()); err != nil {
+			return err
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := p.Pack(value.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return &UnsupportedTypeError{Type: value.Type()}
+		}
+		if value.IsNil() {
+			return p.PackNil()
+		}
+		if err := p.PackMapHeader(value.Len()); err != nil {
+			return err
+		}
+		iter := value.MapRange()
+		for iter.Next() {
+			if err := p.PackString(iter.Key().String()); err != nil {
+				return err
+			}
+			if err := p.Pack(iter.Value().Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return &UnsupportedTypeError{Type: value.Type()}
+}
+
+func (p *Packer) writeHeader(size int, tiny, marker8, marker16, marker32 byte) error {
+	switch {
+	case size < 0x10:
+		return p.writeMarker(tiny | byte(size))
+	case size <= math.MaxUint8:
+		p.buf[0], p.buf[1] = marker8, byte(size)
+		return p.write(p.buf[:2])
+	case size <= math.MaxUint16:
+		p.buf[0] = marker16
+		binary.BigEndian.PutUint16(p.buf[1:], uint16(size))
+		return p.write(p.buf[:3])
+	case int64(size) <= math.MaxUint32:
+		p.buf[0] = marker32
+		binary.BigEndian.PutUint32(p.buf[1:], uint32(size))
+		return p.write(p.buf[:5])
+	}
+
+	return fmt.Errorf("packstream: value of size %d is too large", size)
+}
+
+func (p *Packer) writeMarker(marker byte) error {
+	p.buf[0] = marker
+	return p.write(p.buf[:1])
+}
+
+func (p *Packer) write(b []byte) error {
+	_, err := p.w.Write(b)
+	return err
+}
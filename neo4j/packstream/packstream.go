@@ -0,0 +1,114 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package packstream implements PackStream, the binary serialization format that is used by
+// the Bolt protocol to exchange values with Neo4j servers.
+//
+// Values are written with a Packer and read back with an Unpacker, both of which operate on
+// streams. The following mapping is used between PackStream and Go types:
+//
+//	Null    <-> nil
+//	Boolean <-> bool
+//	Integer <-> int64 (all Go integer types are accepted when packing)
+//	Float   <-> float64 (float32 is accepted when packing)
+//	String  <-> string
+//	Bytes   <-> []byte
+//	List    <-> []interface{} (typed slices and arrays are accepted when packing)
+//	Map     <-> map[string]interface{} (maps with string keys are accepted when packing)
+//	Struct  <-> *Structure
+package packstream
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+const (
+	markerNull     byte = 0xC0
+	markerFloat    byte = 0xC1
+	markerFalse    byte = 0xC2
+	markerTrue     byte = 0xC3
+	markerInt8     byte = 0xC8
+	markerInt16    byte = 0xC9
+	markerInt32    byte = 0xCA
+	markerInt64    byte = 0xCB
+	markerBytes8   byte = 0xCC
+	markerBytes16  byte = 0xCD
+	markerBytes32  byte = 0xCE
+	markerString8  byte = 0xD0
+	markerString16 byte = 0xD1
+	markerString32 byte = 0xD2
+	markerList8    byte = 0xD4
+	markerList16   byte = 0xD5
+	markerList32   byte = 0xD6
+	markerMap8     byte = 0xD8
+	markerMap16    byte = 0xD9
+	markerMap32    byte = 0xDA
+	markerStruct8  byte = 0xDC
+	markerStruct16 byte = 0xDD
+
+	markerTinyString byte = 0x80
+	markerTinyList   byte = 0x90
+	markerTinyMap    byte = 0xA0
+	markerTinyStruct byte = 0xB0
+)
+
+// Structure is a PackStream struct, a signature byte followed by a list of fields
+type Structure struct {
+	Signature byte
+	Fields    []interface{}
+}
+
+// UnsupportedTypeError is returned when a value that has no PackStream representation is
+// passed to Pack
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+func (failure *UnsupportedTypeError) Error() string {
+	return fmt.Sprintf("packstream: values of type %v are not supported", failure.Type)
+}
+
+// Marshal returns the PackStream encoding of the provided value
+func Marshal(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := NewPacker(&buf).Pack(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal decodes a single PackStream value from the provided data, trailing bytes are
+// reported as an error
+func Unmarshal(data []byte) (interface{}, error) {
+	reader := bytes.NewReader(data)
+
+	value, err := NewUnpacker(reader).Unpack()
+	if err != nil {
+		return nil, err
+	}
+
+	if reader.Len() > 0 {
+		return nil, fmt.Errorf("packstream: %d unexpected trailing bytes", reader.Len())
+	}
+
+	return value, nil
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packstream
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackStream(t *testing.T) {
+	roundTrip := func(t *testing.T, value interface{}) interface{} {
+		data, err := Marshal(value)
+		require.NoError(t, err)
+
+		unmarshalled, err := Unmarshal(data)
+		require.NoError(t, err)
+		return unmarshalled
+	}
+
+	t.Run("should round trip primitive values", func(t *testing.T) {
+		for _, value := range []interface{}{
+			nil,
+			true,
+			false,
+			int64(0),
+			int64(-16),
+			int64(-17),
+			int64(127),
+			int64(128),
+			int64(math.MinInt16),
+			int64(math.MaxInt32),
+			int64(math.MinInt64),
+			int64(math.MaxInt64),
+			1.5,
+			math.Inf(-1),
+			"",
+			"hello",
+			"åäö",
+			strings.Repeat("a", 300),
+			strings.Repeat("b", 70000),
+			[]byte{},
+			[]byte{1, 2, 3},
+			bytes.Repeat([]byte{7}, 70000),
+		} {
+			assert.Equal(t, value, roundTrip(t, value))
+		}
+	})
+
+	t.Run("should encode values with the smallest marker", func(t *testing.T) {
+		cases := []struct {
+			value    interface{}
+			expected []byte
+		}{
+			{nil, []byte{0xC0}},
+			{true, []byte{0xC3}},
+			{false, []byte{0xC2}},
+			{1, []byte{0x01}},
+			{-16, []byte{0xF0}},
+			{-17, []byte{0xC8, 0xEF}},
+			{128, []byte{0xC9, 0x00, 0x80}},
+			{100000, []byte{0xCA, 0x00, 0x01, 0x86, 0xA0}},
+			{int64(math.MaxInt64), []byte{0xCB, 0x7F, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}},
+			{1.1, []byte{0xC1, 0x3F, 0xF1, 0x99, 0x99, 0x99, 0x99, 0x99, 0x9A}},
+			{"a", []byte{0x81, 0x61}},
+			{[]byte{1}, []byte{0xCC, 0x01, 0x01}},
+			{[]interface{}{1, 2}, []byte{0x92, 0x01, 0x02}},
+			{map[string]interface{}{"a": 1}, []byte{0xA1, 0x81, 0x61, 0x01}},
+			{&Structure{Signature: 'N', Fields: []interface{}{1}}, []byte{0xB1, 0x4E, 0x01}},
+		}
+
+		for _, c := range cases {
+			data, err := Marshal(c.value)
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, data, "packing %v", c.value)
+		}
+	})
+
+	t.Run("should round trip collections", func(t *testing.T) {
+		list := make([]interface{}, 300)
+		for i := range list {
+			list[i] = int64(i)
+		}
+		assert.Equal(t, list, roundTrip(t, list))
+
+		dict := map[string]interface{}{"a": int64(1), "b": []interface{}{"c"}, "d": map[string]interface{}{}}
+		assert.Equal(t, dict, roundTrip(t, dict))
+	})
+
+	t.Run("should round trip structs", func(t *testing.T) {
+		structure := &Structure{Signature: 'X', Fields: []interface{}{int64(1), &Structure{Signature: 'Y'}}}
+		assert.Equal(t, &Structure{Signature: 'X', Fields: []interface{}{int64(1), &Structure{Signature: 'Y', Fields: []interface{}{}}}}, roundTrip(t, structure))
+
+		fields := make([]interface{}, 20)
+		for i := range fields {
+			fields[i] = int64(i)
+		}
+		assert.Equal(t, &Structure{Signature: 'Z', Fields: fields}, roundTrip(t, Structure{Signature: 'Z', Fields: fields}))
+	})
+
+	t.Run("should convert typed values through reflection", func(t *testing.T) {
+		type custom string
+		value := 5
+
+		assert.Equal(t, []interface{}{int64(1), int64(2)}, roundTrip(t, []int{1, 2}))
+		assert.Equal(t, []interface{}{"a", "b"}, roundTrip(t, [2]string{"a", "b"}))
+		assert.Equal(t, map[string]interface{}{"a": 1.5}, roundTrip(t, map[string]float64{"a": 1.5}))
+		assert.Equal(t, "x", roundTrip(t, custom("x")))
+		assert.Equal(t, int64(5), roundTrip(t, &value))
+		assert.Nil(t, roundTrip(t, []string(nil)))
+	})
+
+	t.Run("should use value mapper for unsupported types", func(t *testing.T) {
+		type point struct{ x, y int64 }
+
+		var buf bytes.Buffer
+		packer := NewPacker(&buf)
+		packer.SetValueMapper(func(value interface{}) (*Structure, error) {
+			if p, ok := value.(point); ok {
+				return &Structure{Signature: 'P', Fields: []interface{}{p.x, p.y}}, nil
+			}
+			return nil, nil
+		})
+		require.NoError(t, packer.Pack([]interface{}{point{1, 2}}))
+
+		value, err := Unmarshal(buf.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{&Structure{Signature: 'P', Fields: []interface{}{int64(1), int64(2)}}}, value)
+	})
+
+	t.Run("should fail on unsupported values", func(t *testing.T) {
+		_, err := Marshal(struct{}{})
+		assert.IsType(t, &UnsupportedTypeError{}, err)
+
+		_, err = Marshal(map[int]string{})
+		assert.IsType(t, &UnsupportedTypeError{}, err)
+
+		_, err = Marshal(uint64(math.MaxUint64))
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail on malformed input", func(t *testing.T) {
+		for _, data := range [][]byte{
+			{},
+			{0x85, 'a'},
+			{0xC9, 0x00},
+			{0x92, 0x01},
+			{0xA1, 0x01, 0x01},
+			{0xE0},
+			{0xD2, 0xFF, 0xFF, 0xFF, 0xFF},
+			{0x01, 0x02},
+		} {
+			_, err := Unmarshal(data)
+			assert.Error(t, err, "unmarshalling %x", data)
+		}
+	})
+
+	t.Run("should fail on values nested too deep", func(t *testing.T) {
+		nested := func(depth int) []byte {
+			data := bytes.Repeat([]byte{0x91}, depth)
+			return append(data, 0x01)
+		}
+
+		_, err := Unmarshal(nested(maxDepth))
+		assert.NoError(t, err)
+
+		_, err = Unmarshal(nested(maxDepth + 1))
+		assert.EqualError(t, err, "packstream: values are nested more than 1024 levels deep")
+
+		_, err = Unmarshal(append(bytes.Repeat([]byte{0xA1, 0x81, 'k'}, maxDepth+1), 0x01))
+		assert.EqualError(t, err, "packstream: values are nested more than 1024 levels deep")
+	})
+
+	t.Run("should stream consecutive values", func(t *testing.T) {
+		var buf bytes.Buffer
+		packer := NewPacker(&buf)
+		require.NoError(t, packer.PackStructHeader(2, 0x10))
+		require.NoError(t, packer.PackString("RETURN 1"))
+		require.NoError(t, packer.PackMapHeader(0))
+		require.NoError(t, packer.PackListHeader(1))
+		require.NoError(t, packer.PackInt(1))
+
+		unpacker := NewUnpacker(&buf)
+		size, signature, err := unpacker.UnpackStructHeader()
+		require.NoError(t, err)
+		assert.Equal(t, 2, size)
+		assert.Equal(t, byte(0x10), signature)
+
+		statement, err := unpacker.Unpack()
+		require.NoError(t, err)
+		assert.Equal(t, "RETURN 1", statement)
+
+		entries, err := unpacker.UnpackMapHeader()
+		require.NoError(t, err)
+		assert.Equal(t, 0, entries)
+
+		items, err := unpacker.UnpackListHeader()
+		require.NoError(t, err)
+		assert.Equal(t, 1, items)
+
+		item, err := unpacker.Unpack()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), item)
+
+		_, err = unpacker.Unpack()
+		assert.Equal(t, io.EOF, err)
+	})
+}
@@ -0,0 +1,382 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package packstream
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// sizes announced in headers are not trusted for allocations beyond this limit, larger
+// values grow as their content is read
+const maxPreallocation = 1 << 16
+
+// lists, maps and structs are not unpacked beyond this depth, as each level of nesting takes
+// a level of recursion
+const maxDepth = 1024
+
+// Unpacker reads PackStream values from an underlying reader
+type Unpacker struct {
+	r   io.Reader
+	buf [8]byte
+	// depth is the number of lists, maps and structs enclosing the value being unpacked
+	depth int
+}
+
+// NewUnpacker returns an unpacker that reads from r
+func NewUnpacker(r io.Reader) *Unpacker {
+	return &Unpacker{r: r}
+}
+
+// Unpack reads the next value, including all values nested in it. It returns io.EOF when
+// the stream ends before the next value.
+func (u *Unpacker) Unpack() (interface{}, error) {
+	marker, err := u.readMarker()
+	if err != nil {
+		return nil, err
+	}
+
+	return u.unpackValue(marker)
+}
+
+// UnpackStructHeader reads the header of a struct and returns its number of fields and its
+// signature
+func (u *Unpacker) UnpackStructHeader() (int, byte, error) {
+	marker, err := u.readMarker()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	size, err := u.structSize(marker)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	signature, err := u.readByte()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return size, signature, nil
+}
+
+// UnpackListHeader reads the header of a list and returns its number of items
+func (u *Unpacker) UnpackListHeader() (int, error) {
+	marker, err := u.readMarker()
+	if err != nil {
+		return 0, err
+	}
+
+	if marker&0xF0 == markerTinyList {
+		return int(marker & 0x0F), nil
+	}
+
+	switch marker {
+	case markerList8, markerList16, markerList32:
+		return u.readSize(marker - markerList8)
+	}
+
+	return 0, unexpectedMarker("list", marker)
+}
+
+// UnpackMapHeader reads the header of a map and returns its number of entries
+func (u *Unpacker) UnpackMapHeader() (int, error) {
+	marker, err := u.readMarker()
+	if err != nil {
+		return 0, err
+	}
+
+	if marker&0xF0 == markerTinyMap {
+		return int(marker & 0x0F), nil
+	}
+
+	switch marker {
+	case markerMap8, markerMap16, markerMap32:
+		return u.readSize(marker - markerMap8)
+	}
+
+	return 0, unexpectedMarker("map", marker)
+}
+
+func (u *Unpacker) unpackValue(marker byte) (interface{}, error) {
+	// tiny int
+	if marker < 0x80 || marker >= 0xF0 {
+		return int64(int8(marker)), nil
+	}
+
+	switch marker & 0xF0 {
+	case markerTinyString:
+		return u.unpackString(int(marker & 0x0F))
+	case markerTinyList:
+		return u.unpackList(int(marker & 0x0F))
+	case markerTinyMap:
+		return u.unpackMap(int(marker & 0x0F))
+	case markerTinyStruct:
+		return u.unpackStruct(int(marker & 0x0F))
+	}
+
+	switch marker {
+	case markerNull:
+		return nil, nil
+	case markerTrue:
+		return true, nil
+	case markerFalse:
+		return false, nil
+	case markerFloat:
+		if err := u.readFull(u.buf[:8]); err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(u.buf[:8])), nil
+	case markerInt8:
+		if err := u.readFull(u.buf[:1]); err != nil {
+			return nil, err
+		}
+		return int64(int8(u.buf[0])), nil
+	case markerInt16:
+		if err := u.readFull(u.buf[:2]); err != nil {
+			return nil, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(u.buf[:2]))), nil
+	case markerInt32:
+		if err := u.readFull(u.buf[:4]); err != nil {
+			return nil, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(u.buf[:4]))), nil
+	case markerInt64:
+		if err := u.readFull(u.buf[:8]); err != nil {
+			return nil, err
+		}
+		return int64(binary.BigEndian.Uint64(u.buf[:8])), nil
+	case markerBytes8, markerBytes16, markerBytes32:
+		size, err := u.readSize(marker - markerBytes8)
+		if err != nil {
+			return nil, err
+		}
+		return u.readBytes(size)
+	case markerString8, markerString16, markerString32:
+		size, err := u.readSize(marker - markerString8)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackString(size)
+	case markerList8, markerList16, markerList32:
+		size, err := u.readSize(marker - markerList8)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackList(size)
+	case markerMap8, markerMap16, markerMap32:
+		size, err := u.readSize(marker - markerMap8)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackMap(size)
+	case markerStruct8, markerStruct16:
+		size, err := u.structSize(marker)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackStruct(size)
+	}
+
+	return nil, unexpectedMarker("value", marker)
+}
+
+func (u *Unpacker) unpack() (interface{}, error) {
+	marker, err := u.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	return u.unpackValue(marker)
+}
+
+func (u *Unpacker) unpackString(size int) (string, error) {
+	b, err := u.readBytes(size)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (u *Unpacker) unpackList(size int) ([]interface{}, error) {
+	if err := u.descend(); err != nil {
+		return nil, err
+	}
+	defer u.ascend()
+
+	list := make([]interface{}, 0, preallocation(size))
+	for i := 0; i < size; i++ {
+		value, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+
+	return list, nil
+}
+
+func (u *Unpacker) unpackMap(size int) (map[string]interface{}, error) {
+	if err := u.descend(); err != nil {
+		return nil, err
+	}
+	defer u.ascend()
+
+	dict := make(map[string]interface{}, preallocation(size))
+	for i := 0; i < size; i++ {
+		key, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+
+		keyString, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("packstream: expected map key to be a string but found %T", key)
+		}
+
+		value, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		dict[keyString] = value
+	}
+
+	return dict, nil
+}
+
+func (u *Unpacker) unpackStruct(size int) (*Structure, error) {
+	signature, err := u.readByte()
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := u.unpackList(size)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Structure{Signature: signature, Fields: fields}, nil
+}
+
+// descend enters a list, map or struct and fails when that nests values too deep
+func (u *Unpacker) descend() error {
+	if u.depth >= maxDepth {
+		return fmt.Errorf("packstream: values are nested more than %d levels deep", maxDepth)
+	}
+
+	u.depth++
+	return nil
+}
+
+func (u *Unpacker) ascend() {
+	u.depth--
+}
+
+func (u *Unpacker) structSize(marker byte) (int, error) {
+	if marker&0xF0 == markerTinyStruct {
+		return int(marker & 0x0F), nil
+	}
+
+	switch marker {
+	case markerStruct8, markerStruct16:
+		return u.readSize(marker - markerStruct8)
+	}
+
+	return 0, unexpectedMarker("struct", marker)
+}
+
+// readSize reads a size of 1, 2 or 4 bytes for the given marker offset of 0, 1 or 2
+func (u *Unpacker) readSize(offset byte) (int, error) {
+	n := 1 << offset
+	if err := u.readFull(u.buf[:n]); err != nil {
+		return 0, err
+	}
+
+	switch n {
+	case 1:
+		return int(u.buf[0]), nil
+	case 2:
+		return int(binary.BigEndian.Uint16(u.buf[:2])), nil
+	}
+	return int(binary.BigEndian.Uint32(u.buf[:4])), nil
+}
+
+// readBytes reads size bytes without allocating more than is actually received
+func (u *Unpacker) readBytes(size int) ([]byte, error) {
+	b := make([]byte, 0, preallocation(size))
+	for len(b) < size {
+		chunk := size - len(b)
+		if chunk > maxPreallocation {
+			chunk = maxPreallocation
+		}
+
+		start := len(b)
+		b = append(b, make([]byte, chunk)...)
+		if err := u.readFull(b[start:]); err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// readMarker reads the marker that starts a value, unlike readByte it passes io.EOF through
+// as the stream may legitimately end here
+func (u *Unpacker) readMarker() (byte, error) {
+	if _, err := io.ReadFull(u.r, u.buf[:1]); err != nil {
+		return 0, err
+	}
+
+	return u.buf[0], nil
+}
+
+func (u *Unpacker) readByte() (byte, error) {
+	if err := u.readFull(u.buf[:1]); err != nil {
+		return 0, err
+	}
+
+	return u.buf[0], nil
+}
+
+func (u *Unpacker) readFull(b []byte) error {
+	if _, err := io.ReadFull(u.r, b); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	return nil
+}
+
+func preallocation(size int) int {
+	if size > maxPreallocation {
+		return maxPreallocation
+	}
+	return size
+}
+
+func unexpectedMarker(expected string, marker byte) error {
+	return fmt.Errorf("packstream: expected %s but found marker %#x", expected, marker)
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package prometheus exports the metrics of a driver to Prometheus. A Collector is hooked
+// into the configuration of a driver to observe its statements and retries, and reports the
+// connection pools of the driver it monitors whenever it is scraped:
+//
+//	collector := prometheus.NewCollector("")
+//	driver, err := neo4j.NewDriver(uri, auth, collector.Configure)
+//	if err != nil {
+//		return err
+//	}
+//	collector.Monitor(driver)
+//	registry.MustRegister(collector)
+package prometheus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+// Error classifications the errors of statements are counted by
+const (
+	ClassificationServiceUnavailable = "service_unavailable"
+	ClassificationSessionExpired     = "session_expired"
+	ClassificationAuthentication     = "authentication"
+	ClassificationSecurity           = "security"
+	ClassificationTransient          = "transient"
+	ClassificationClient             = "client"
+	ClassificationOther              = "other"
+)
+
+const subsystem = "neo4j"
+
+// Collector is a prometheus.Collector for the metrics of a driver and a neo4j.Observer of
+// its statements and retries. It reports
+//   - the connections in use and idle, the connections created, closed and failed to create,
+//     the timed out acquisitions and the acquisition latency per server address
+//   - the time until results were available and consumed, as reported by the summaries of
+//     the statements
+//   - the number of retried transaction function attempts
+//   - the number of failed statements per error classification
+type Collector struct {
+	mutex  sync.Mutex
+	driver neo4j.Driver
+
+	inUse               *prom.Desc
+	idle                *prom.Desc
+	created             *prom.Desc
+	closed              *prom.Desc
+	failedToCreate      *prom.Desc
+	acquisitionTimeouts *prom.Desc
+	acquisitionLatency  *prom.Desc
+
+	resultAvailableAfter prom.Histogram
+	resultConsumedAfter  prom.Histogram
+	retries              prom.Counter
+	errors               *prom.CounterVec
+}
+
+// NewCollector creates a collector whose metrics are named <namespace>_neo4j_<name>, or
+// neo4j_<name> when namespace is empty
+func NewCollector(namespace string) *Collector {
+	poolDesc := func(name, help string) *prom.Desc {
+		return prom.NewDesc(prom.BuildFQName(namespace, subsystem, name), help, []string{"address"}, nil)
+	}
+
+	return &Collector{
+		inUse:               poolDesc("connections_in_use", "Number of connections to the server that are in use."),
+		idle:                poolDesc("connections_idle", "Number of idle connections to the server."),
+		created:             poolDesc("connections_created_total", "Number of connections to the server that were opened."),
+		closed:              poolDesc("connections_closed_total", "Number of connections to the server that were closed."),
+		failedToCreate:      poolDesc("connections_failed_to_create_total", "Number of connections to the server that could not be opened."),
+		acquisitionTimeouts: poolDesc("connection_acquisition_timeouts_total", "Number of connection acquisitions that timed out because the pool of the server was full."),
+		acquisitionLatency:  poolDesc("connection_acquisition_duration_seconds", "Time it took to acquire connections to the server."),
+		resultAvailableAfter: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "result_available_after_seconds",
+			Help:      "Time it took the server to make the results of statements available.",
+			Buckets:   prom.DefBuckets,
+		}),
+		resultConsumedAfter: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "result_consumed_after_seconds",
+			Help:      "Time it took to consume the results of statements.",
+			Buckets:   prom.DefBuckets,
+		}),
+		retries: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "transaction_retries_total",
+			Help:      "Number of transaction function attempts that failed and were retried.",
+		}),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "statement_errors_total",
+			Help:      "Number of statements that failed, by error classification.",
+		}, []string{"classification"}),
+	}
+}
+
+// Configure registers the collector as observer of the driver that is created with the
+// configuration, it is meant to be passed to neo4j.NewDriver
+func (collector *Collector) Configure(config *neo4j.Config) {
+	config.Observers = append(config.Observers, collector)
+}
+
+// Monitor sets the driver whose connection pools are reported
+func (collector *Collector) Monitor(driver neo4j.Driver) {
+	collector.mutex.Lock()
+	defer collector.mutex.Unlock()
+
+	collector.driver = driver
+}
+
+// Describe implements prometheus.Collector
+func (collector *Collector) Describe(descs chan<- *prom.Desc) {
+	descs <- collector.inUse
+	descs <- collector.idle
+	descs <- collector.created
+	descs <- collector.closed
+	descs <- collector.failedToCreate
+	descs <- collector.acquisitionTimeouts
+	descs <- collector.acquisitionLatency
+	collector.resultAvailableAfter.Describe(descs)
+	collector.resultConsumedAfter.Describe(descs)
+	collector.retries.Describe(descs)
+	collector.errors.Describe(descs)
+}
+
+// Collect implements prometheus.Collector
+func (collector *Collector) Collect(metrics chan<- prom.Metric) {
+	collector.mutex.Lock()
+	driver := collector.driver
+	collector.mutex.Unlock()
+
+	if driver != nil {
+		for address, pool := range driver.Metrics().ConnectionPools {
+			metrics <- prom.MustNewConstMetric(collector.inUse, prom.GaugeValue, float64(pool.InUse), address)
+			metrics <- prom.MustNewConstMetric(collector.idle, prom.GaugeValue, float64(pool.Idle), address)
+			metrics <- prom.MustNewConstMetric(collector.created, prom.CounterValue, float64(pool.Created), address)
+			metrics <- prom.MustNewConstMetric(collector.closed, prom.CounterValue, float64(pool.Closed), address)
+			metrics <- prom.MustNewConstMetric(collector.failedToCreate, prom.CounterValue, float64(pool.FailedToCreate), address)
+			metrics <- prom.MustNewConstMetric(collector.acquisitionTimeouts, prom.CounterValue, float64(pool.AcquisitionTimeouts), address)
+			metrics <- collector.histogramOf(pool.AcquisitionLatency, address)
+		}
+	}
+
+	collector.resultAvailableAfter.Collect(metrics)
+	collector.resultConsumedAfter.Collect(metrics)
+	collector.retries.Collect(metrics)
+	collector.errors.Collect(metrics)
+}
+
+// histogramOf converts the bucket counts of the driver into the cumulative counts of a
+// Prometheus histogram
+func (collector *Collector) histogramOf(histogram neo4j.LatencyHistogram, address string) prom.Metric {
+	buckets := make(map[float64]uint64, len(histogram.Bounds))
+	var cumulative uint64
+	for i, bound := range histogram.Bounds {
+		cumulative += uint64(histogram.Counts[i])
+		buckets[bound.Seconds()] = cumulative
+	}
+
+	return prom.MustNewConstHistogram(collector.acquisitionLatency, uint64(histogram.Count), histogram.Sum.Seconds(), buckets, address)
+}
+
+// StatementCompleted implements neo4j.Observer
+func (collector *Collector) StatementCompleted(summary neo4j.ResultSummary, err error) {
+	if err != nil {
+		collector.errors.WithLabelValues(Classify(err)).Inc()
+		return
+	}
+
+	collector.resultAvailableAfter.Observe(summary.ResultAvailableAfter().Seconds())
+	collector.resultConsumedAfter.Observe(summary.ResultConsumedAfter().Seconds())
+}
+
+// TransactionRetried implements neo4j.Observer
+func (collector *Collector) TransactionRetried(attempt int, delay time.Duration, err error) {
+	collector.retries.Inc()
+}
+
+// Classify returns the classification an error is counted by
+func Classify(err error) string {
+	switch {
+	case neo4j.IsServiceUnavailable(err):
+		return ClassificationServiceUnavailable
+	case neo4j.IsSessionExpired(err):
+		return ClassificationSessionExpired
+	case neo4j.IsAuthenticationError(err):
+		return ClassificationAuthentication
+	case neo4j.IsSecurityError(err):
+		return ClassificationSecurity
+	case neo4j.IsTransientError(err):
+		return ClassificationTransient
+	case neo4j.IsClientError(err):
+		return ClassificationClient
+	}
+
+	return ClassificationOther
+}
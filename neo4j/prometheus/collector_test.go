@@ -0,0 +1,250 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j"
+	"github.com/neo4j/neo4j-go-driver/neo4j/packstream"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsDriver is a driver that only reports the given metrics
+type metricsDriver struct {
+	neo4j.Driver
+	metrics neo4j.Metrics
+}
+
+func (driver *metricsDriver) Metrics() neo4j.Metrics {
+	return driver.metrics
+}
+
+// serveStatements accepts connections of protocol version 3 on a local port until stop is
+// called, it reports every statement to have been available after availableAfter and consumed
+// after consumedAfter milliseconds
+func serveStatements(t *testing.T, availableAfter, consumedAfter int64) (address string, stop func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveConnection(conn, availableAfter, consumedAfter)
+		}
+	}()
+
+	return listener.Addr().String(), func() { _ = listener.Close() }
+}
+
+func serveConnection(conn net.Conn, availableAfter, consumedAfter int64) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	if _, err := io.ReadFull(reader, make([]byte, 20)); err != nil {
+		return
+	}
+	if _, err := conn.Write([]byte{0x00, 0x00, 0x00, 0x03}); err != nil {
+		return
+	}
+
+	for {
+		var message []byte
+		for {
+			header := make([]byte, 2)
+			if _, err := io.ReadFull(reader, header); err != nil {
+				return
+			}
+			chunk := make([]byte, binary.BigEndian.Uint16(header))
+			if len(chunk) == 0 {
+				break
+			}
+			if _, err := io.ReadFull(reader, chunk); err != nil {
+				return
+			}
+			message = append(message, chunk...)
+		}
+
+		_, signature, err := packstream.NewUnpacker(bytes.NewReader(message)).UnpackStructHeader()
+		if err != nil {
+			return
+		}
+
+		metadata := map[string]interface{}{}
+		switch signature {
+		case 0x01: // HELLO
+			metadata["server"] = "Neo4j/3.5.0"
+		case 0x02: // GOODBYE
+			return
+		case 0x10: // RUN
+			metadata["fields"] = []interface{}{"n"}
+			metadata["t_first"] = availableAfter
+		case 0x3F: // PULL_ALL
+			metadata["type"] = "r"
+			metadata["t_last"] = consumedAfter
+		}
+
+		var buf bytes.Buffer
+		packer := packstream.NewPacker(&buf)
+		if packer.PackStructHeader(1, 0x70) != nil || packer.Pack(metadata) != nil {
+			return
+		}
+		response := append([]byte{byte(buf.Len() >> 8), byte(buf.Len())}, buf.Bytes()...)
+		if _, err := conn.Write(append(response, 0x00, 0x00)); err != nil {
+			return
+		}
+	}
+}
+
+// histogramSum returns the sum of the observations of a histogram
+func histogramSum(t *testing.T, histogram prom.Histogram) float64 {
+	registry := prom.NewPedanticRegistry()
+	require.NoError(t, registry.Register(histogram))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+
+	return families[0].GetMetric()[0].GetHistogram().GetSampleSum()
+}
+
+func TestCollector(t *testing.T) {
+	t.Run("should register as observer of the driver", func(t *testing.T) {
+		collector := NewCollector("")
+		config := &neo4j.Config{}
+
+		collector.Configure(config)
+
+		assert.Equal(t, []neo4j.Observer{collector}, config.Observers)
+	})
+
+	t.Run("should report the connection pools of the monitored driver", func(t *testing.T) {
+		collector := NewCollector("app")
+		collector.Monitor(&metricsDriver{metrics: neo4j.Metrics{ConnectionPools: map[string]neo4j.ConnectionPoolMetrics{
+			"reader:7687": {
+				Address:             "reader:7687",
+				InUse:               2,
+				Idle:                3,
+				Created:             5,
+				Closed:              1,
+				FailedToCreate:      4,
+				AcquisitionTimeouts: 6,
+				AcquisitionLatency: neo4j.LatencyHistogram{
+					Bounds: []time.Duration{time.Millisecond, time.Second},
+					Counts: []int64{2, 1, 1},
+					Count:  4,
+					Sum:    2 * time.Second,
+				},
+			},
+		}}})
+
+		expected := `
+# HELP app_neo4j_connections_in_use Number of connections to the server that are in use.
+# TYPE app_neo4j_connections_in_use gauge
+app_neo4j_connections_in_use{address="reader:7687"} 2
+# HELP app_neo4j_connections_idle Number of idle connections to the server.
+# TYPE app_neo4j_connections_idle gauge
+app_neo4j_connections_idle{address="reader:7687"} 3
+# HELP app_neo4j_connections_failed_to_create_total Number of connections to the server that could not be opened.
+# TYPE app_neo4j_connections_failed_to_create_total counter
+app_neo4j_connections_failed_to_create_total{address="reader:7687"} 4
+# HELP app_neo4j_connection_acquisition_timeouts_total Number of connection acquisitions that timed out because the pool of the server was full.
+# TYPE app_neo4j_connection_acquisition_timeouts_total counter
+app_neo4j_connection_acquisition_timeouts_total{address="reader:7687"} 6
+# HELP app_neo4j_connection_acquisition_duration_seconds Time it took to acquire connections to the server.
+# TYPE app_neo4j_connection_acquisition_duration_seconds histogram
+app_neo4j_connection_acquisition_duration_seconds_bucket{address="reader:7687",le="0.001"} 2
+app_neo4j_connection_acquisition_duration_seconds_bucket{address="reader:7687",le="1"} 3
+app_neo4j_connection_acquisition_duration_seconds_bucket{address="reader:7687",le="+Inf"} 4
+app_neo4j_connection_acquisition_duration_seconds_sum{address="reader:7687"} 2
+app_neo4j_connection_acquisition_duration_seconds_count{address="reader:7687"} 4
+`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected),
+			"app_neo4j_connections_in_use",
+			"app_neo4j_connections_idle",
+			"app_neo4j_connections_failed_to_create_total",
+			"app_neo4j_connection_acquisition_timeouts_total",
+			"app_neo4j_connection_acquisition_duration_seconds")
+		require.NoError(t, err)
+	})
+
+	t.Run("should report the timings of completed statements", func(t *testing.T) {
+		address, stop := serveStatements(t, 250, 1500)
+		defer stop()
+
+		collector := NewCollector("")
+		driver, err := neo4j.NewDriver("bolt://"+address, neo4j.NoAuth(), collector.Configure, func(config *neo4j.Config) {
+			config.Encrypted = false
+		})
+		require.NoError(t, err)
+		defer driver.Close()
+
+		session, err := driver.NewSession(neo4j.SessionConfig{})
+		require.NoError(t, err)
+		defer session.Close()
+		result, err := session.Run("RETURN 1 AS n", nil)
+		require.NoError(t, err)
+		_, err = result.Consume()
+		require.NoError(t, err)
+
+		assert.InDelta(t, 0.25, histogramSum(t, collector.resultAvailableAfter), 1e-9)
+		assert.InDelta(t, 1.5, histogramSum(t, collector.resultConsumedAfter), 1e-9)
+		assert.Equal(t, 0.0, testutil.ToFloat64(collector.errors.WithLabelValues(ClassificationOther)))
+	})
+
+	t.Run("should count failed statements by classification", func(t *testing.T) {
+		collector := NewCollector("")
+
+		collector.StatementCompleted(nil, errors.New("some failure"))
+		collector.StatementCompleted(nil, errors.New("another failure"))
+
+		assert.Equal(t, 2.0, testutil.ToFloat64(collector.errors.WithLabelValues(ClassificationOther)))
+	})
+
+	t.Run("should count retries", func(t *testing.T) {
+		collector := NewCollector("")
+
+		collector.TransactionRetried(1, time.Second, errors.New("some failure"))
+		collector.TransactionRetried(2, 2*time.Second, errors.New("some failure"))
+
+		assert.Equal(t, 2.0, testutil.ToFloat64(collector.retries))
+	})
+
+	t.Run("should be registrable without a monitored driver", func(t *testing.T) {
+		registry := prom.NewPedanticRegistry()
+
+		require.NoError(t, registry.Register(NewCollector("")))
+		_, err := registry.Gather()
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,275 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+)
+
+const scanTag = "neo4j"
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// ScanRecord copies the values of a record into the struct that dest points to. Fields are
+// matched by the name given in their `neo4j:"name"` tag, or by their own name when they are
+// not tagged, and fields tagged with `neo4j:"-"` are left out. A field that matches none of
+// the keys of the record is looked up in the properties of the node or relationship when
+// that is the only value of the record, so that
+//
+//	MATCH (p:Person) RETURN p
+//
+// and
+//
+//	MATCH (p:Person) RETURN p.name AS name
+//
+// can both be scanned into the same struct. Fields without a matching value keep their
+// current value.
+//
+// Values are converted into the type of their field: integers into any integer type they
+// fit in, floats into float types, lists into slices, maps as well as the properties of
+// nodes and relationships into maps and structs, Date and LocalDateTime into time.Time and
+// a Duration without months into time.Duration, counting days as 24 hours. Any other value
+// is only assigned to a field of its own type or an interface it implements. Pointer fields
+// are allocated when the value is not nil.
+func ScanRecord(record Record, dest interface{}) error {
+	if record == nil {
+		return newDriverError("unable to scan a nil record")
+	}
+
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return newDriverError("expected dest to be a non-nil pointer to a struct but it was '%T'", dest)
+	}
+
+	var props map[string]interface{}
+	if values := record.Values(); len(values) == 1 {
+		switch entity := values[0].(type) {
+		case Node:
+			props = entity.Props()
+		case Relationship:
+			props = entity.Props()
+		}
+	}
+
+	return scanStruct(target.Elem(), func(name string) (interface{}, bool) {
+		if value, ok := record.Get(name); ok {
+			return value, true
+		}
+
+		value, ok := props[name]
+		return value, ok
+	}, "")
+}
+
+// scanStruct assigns the values that lookup finds for the fields of target, path names the
+// position of target within the value that is scanned for the use in errors
+func scanStruct(target reflect.Value, lookup func(name string) (interface{}, bool), path string) error {
+	targetType := target.Type()
+
+	for i := 0; i < targetType.NumField(); i++ {
+		field := targetType.Field(i)
+		tag := field.Tag.Get(scanTag)
+
+		if tag == "-" {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct {
+			if err := scanStruct(target.Field(i), lookup, path); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// unexported fields cannot be set
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name := field.Name
+		if tag != "" {
+			name = tag
+		}
+
+		value, ok := lookup(name)
+		if !ok {
+			continue
+		}
+
+		if err := scanValue(target.Field(i), value, path+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// durationNanos returns the nanoseconds of a duration without months, ok is false when they
+// do not fit in a time.Duration
+func durationNanos(duration Duration) (int64, bool) {
+	nanos := big.NewInt(duration.Days())
+	nanos.Mul(nanos, big.NewInt(24*60*60))
+	nanos.Add(nanos, big.NewInt(duration.Seconds()))
+	nanos.Mul(nanos, big.NewInt(int64(time.Second)))
+	nanos.Add(nanos, big.NewInt(int64(duration.Nanos())))
+	if !nanos.IsInt64() {
+		return 0, false
+	}
+
+	return nanos.Int64(), true
+}
+
+func scanValue(target reflect.Value, value interface{}, path string) error {
+	targetType := target.Type()
+
+	if value == nil {
+		target.Set(reflect.Zero(targetType))
+		return nil
+	}
+
+	source := reflect.ValueOf(value)
+	if source.Type().AssignableTo(targetType) {
+		target.Set(source)
+		return nil
+	}
+
+	if duration, ok := value.(Duration); ok && targetType == durationType && duration.Months() == 0 {
+		nanos, ok := durationNanos(duration)
+		if !ok {
+			return newDriverError("unable to scan '%s': value %v overflows %s", path, duration, targetType)
+		}
+		target.SetInt(nanos)
+		return nil
+	}
+
+	switch targetType.Kind() {
+	case reflect.Ptr:
+		elem := reflect.New(targetType.Elem())
+		if err := scanValue(elem.Elem(), value, path); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if i, ok := value.(int64); ok {
+			if target.OverflowInt(i) {
+				return newDriverError("unable to scan '%s': value %d overflows %s", path, i, targetType)
+			}
+			target.SetInt(i)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i, ok := value.(int64); ok {
+			if i < 0 || target.OverflowUint(uint64(i)) {
+				return newDriverError("unable to scan '%s': value %d overflows %s", path, i, targetType)
+			}
+			target.SetUint(uint64(i))
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch f := value.(type) {
+		case float64:
+			if targetType.Kind() == reflect.Float32 && math.Abs(f) > math.MaxFloat32 && !math.IsInf(f, 0) {
+				return newDriverError("unable to scan '%s': value %v overflows %s", path, f, targetType)
+			}
+			target.SetFloat(f)
+			return nil
+		case int64:
+			target.SetFloat(float64(f))
+			return nil
+		}
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			target.SetString(s)
+			return nil
+		}
+	case reflect.Bool:
+		if b, ok := value.(bool); ok {
+			target.SetBool(b)
+			return nil
+		}
+	case reflect.Slice:
+		if list, ok := value.([]interface{}); ok {
+			slice := reflect.MakeSlice(targetType, len(list), len(list))
+			for i, item := range list {
+				if err := scanValue(slice.Index(i), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			target.Set(slice)
+			return nil
+		}
+	case reflect.Map:
+		if props, ok := propertiesOf(value); ok && targetType.Key().Kind() == reflect.String {
+			dict := reflect.MakeMapWithSize(targetType, len(props))
+			for key, item := range props {
+				elem := reflect.New(targetType.Elem()).Elem()
+				if err := scanValue(elem, item, path+"."+key); err != nil {
+					return err
+				}
+				dict.SetMapIndex(reflect.ValueOf(key).Convert(targetType.Key()), elem)
+			}
+			target.Set(dict)
+			return nil
+		}
+	case reflect.Struct:
+		if targetType == timeType {
+			switch temporal := value.(type) {
+			case Date:
+				target.Set(reflect.ValueOf(temporal.Time()))
+				return nil
+			case LocalDateTime:
+				target.Set(reflect.ValueOf(temporal.Time()))
+				return nil
+			}
+			break
+		}
+
+		if props, ok := propertiesOf(value); ok {
+			return scanStruct(target, func(name string) (interface{}, bool) {
+				value, ok := props[name]
+				return value, ok
+			}, path+".")
+		}
+	}
+
+	return newDriverError("unable to scan '%s': value of type %T cannot be converted to %s", path, value, targetType)
+}
+
+// propertiesOf returns the entries of a map or the properties of a node or relationship
+func propertiesOf(value interface{}) (map[string]interface{}, bool) {
+	switch entity := value.(type) {
+	case map[string]interface{}:
+		return entity, true
+	case Node:
+		return entity.Props(), true
+	case Relationship:
+		return entity.Props(), true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"time"
+
+	. "github.com/neo4j/neo4j-go-driver/neo4j/utils/test"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Record Scan", func() {
+	type address struct {
+		City string `neo4j:"city"`
+	}
+
+	type person struct {
+		Name     string           `neo4j:"name"`
+		Age      int              `neo4j:"age"`
+		Score    float32          `neo4j:"score"`
+		Tags     []string         `neo4j:"tags"`
+		Born     time.Time        `neo4j:"born"`
+		Nickname *string          `neo4j:"nickname"`
+		Address  address          `neo4j:"address"`
+		Extra    map[string]int64 `neo4j:"extra"`
+		Ignored  string           `neo4j:"-"`
+		Active   bool
+		ignored  string
+	}
+
+	newRecord := func(keys []string, values ...interface{}) Record {
+		return &neoRecord{keys: keys, values: values}
+	}
+
+	It("should map record keys onto tagged fields", func() {
+		var p person
+		born := DateOf(time.Date(1980, time.May, 4, 0, 0, 0, 0, time.UTC))
+		record := newRecord(
+			[]string{"name", "age", "score", "tags", "born", "nickname", "address", "extra", "Active"},
+			"Alice", int64(42), 1.5, []interface{}{"a", "b"}, born, "Al",
+			map[string]interface{}{"city": "Malmö"}, map[string]interface{}{"x": int64(1)}, true,
+		)
+
+		Expect(ScanRecord(record, &p)).To(Succeed())
+
+		nickname := "Al"
+		Expect(p).To(Equal(person{
+			Name:     "Alice",
+			Age:      42,
+			Score:    1.5,
+			Tags:     []string{"a", "b"},
+			Born:     born.Time(),
+			Nickname: &nickname,
+			Address:  address{City: "Malmö"},
+			Extra:    map[string]int64{"x": 1},
+			Active:   true,
+		}))
+	})
+
+	It("should map properties of a single node", func() {
+		var p person
+		record := newRecord([]string{"p"}, &nodeValue{id: 1, labels: []string{"Person"}, props: map[string]interface{}{"name": "Bob", "age": int64(7)}})
+
+		Expect(ScanRecord(record, &p)).To(Succeed())
+
+		Expect(p.Name).To(Equal("Bob"))
+		Expect(p.Age).To(Equal(7))
+	})
+
+	It("should map relationships and nodes onto nested structs", func() {
+		var knows struct {
+			Since  int64  `neo4j:"since"`
+			Friend person `neo4j:"friend"`
+			Node   Node   `neo4j:"friend"`
+		}
+		friend := &nodeValue{id: 2, props: map[string]interface{}{"name": "Carol"}}
+		record := newRecord([]string{"r", "friend"}, &relationshipValue{id: 1, props: map[string]interface{}{"since": int64(2001)}}, friend)
+
+		Expect(ScanRecord(record, &knows)).To(Succeed())
+
+		Expect(knows.Since).To(BeZero())
+		Expect(knows.Friend.Name).To(Equal("Carol"))
+		Expect(knows.Node).To(BeIdenticalTo(friend))
+	})
+
+	It("should convert temporal values", func() {
+		var temporal struct {
+			Date     Date          `neo4j:"date"`
+			Local    time.Time     `neo4j:"local"`
+			Duration time.Duration `neo4j:"duration"`
+			Raw      Duration      `neo4j:"raw"`
+		}
+		local := LocalDateTimeOf(time.Date(2020, time.January, 2, 3, 4, 5, 6, time.Local))
+		record := newRecord([]string{"date", "local", "duration", "raw"}, DateOf(time.Now()), local, DurationOf(0, 1, 2, 3), DurationOf(1, 0, 0, 0))
+
+		Expect(ScanRecord(record, &temporal)).To(Succeed())
+
+		Expect(temporal.Local).To(Equal(local.Time()))
+		Expect(temporal.Duration).To(Equal(24*time.Hour + 2*time.Second + 3))
+		Expect(temporal.Raw).To(Equal(DurationOf(1, 0, 0, 0)))
+	})
+
+	It("should reset fields for null values", func() {
+		p := person{Name: "Alice", Nickname: new(string)}
+		record := newRecord([]string{"name", "nickname"}, nil, nil)
+
+		Expect(ScanRecord(record, &p)).To(Succeed())
+
+		Expect(p.Name).To(BeEmpty())
+		Expect(p.Nickname).To(BeNil())
+	})
+
+	It("should report the field of a mismatching value", func() {
+		var p person
+		record := newRecord([]string{"name", "tags"}, "Alice", []interface{}{"a", int64(1)})
+
+		Expect(ScanRecord(record, &p)).To(BeGenericError(Equal("unable to scan 'tags[1]': value of type int64 cannot be converted to string")))
+	})
+
+	It("should report values that overflow their field", func() {
+		var small struct {
+			Value int8 `neo4j:"value"`
+		}
+		record := newRecord([]string{"value"}, int64(300))
+
+		Expect(ScanRecord(record, &small)).To(BeGenericError(Equal("unable to scan 'value': value 300 overflows int8")))
+	})
+
+	It("should report durations that overflow time.Duration", func() {
+		var long struct {
+			Duration time.Duration `neo4j:"duration"`
+		}
+		record := newRecord([]string{"duration"}, DurationOf(0, 110000, 0, 0))
+
+		Expect(ScanRecord(record, &long)).To(BeGenericError(Equal("unable to scan 'duration': value P0M110000DT0S overflows time.Duration")))
+	})
+
+	It("should report the path of nested fields", func() {
+		var p person
+		record := newRecord([]string{"address"}, map[string]interface{}{"city": true})
+
+		Expect(ScanRecord(record, &p)).To(BeGenericError(Equal("unable to scan 'address.city': value of type bool cannot be converted to string")))
+	})
+
+	It("should fail when dest is not a pointer to a struct", func() {
+		var p person
+		record := newRecord([]string{"name"}, "Alice")
+
+		Expect(ScanRecord(record, p)).To(BeGenericError(ContainSubstring("expected dest to be a non-nil pointer to a struct")))
+		Expect(ScanRecord(record, (*person)(nil))).To(BeGenericError(ContainSubstring("expected dest to be a non-nil pointer to a struct")))
+	})
+
+	It("should fail to scan a result without current record", func() {
+		var p person
+		result := &neoResult{}
+
+		Expect(result.Scan(&p)).To(BeGenericError(ContainSubstring("there is no current record to scan")))
+	})
+})
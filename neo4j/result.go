@@ -19,19 +19,40 @@
 
 package neo4j
 
+import "context"
+
 // Result provides access to the result of the executing statement.
 type Result interface {
 	// Keys returns the keys available on the result set.
 	Keys() ([]string, error)
 	// Next returns true only if there is a record to be processed.
 	Next() bool
+	// NextContext is like Next but waits for the next record only as long as ctx allows. When ctx
+	// is done first, the statement is terminated on the server and Err returns the error of ctx.
+	NextContext(ctx context.Context) bool
 	// Err returns the latest error that caused this Next to return false.
 	Err() error
 	// Record returns the current record.
 	Record() Record
+	// Scan copies the values of the current record into the struct that dest points to, see
+	// ScanRecord for how values are matched to fields.
+	Scan(dest interface{}) error
 	// Summary returns the summary information about the statement execution.
 	Summary() (ResultSummary, error)
 	// Consume consumes the entire result and returns the summary information
 	// about the statement execution.
 	Consume() (ResultSummary, error)
+	// ConsumeContext is like Consume but gives up as soon as ctx is done, terminating the
+	// statement on the server and returning the error of ctx.
+	ConsumeContext(ctx context.Context) (ResultSummary, error)
+	// Stream sends the records on the returned channel as they are received from the server, so
+	// that they can be processed by multiple goroutines. The record channel is closed once the
+	// result is complete, after which the error channel yields the error that ended the result,
+	// if any, and is closed as well. When ctx is done first, the record channel is closed without
+	// waiting for the server and the error of ctx is sent, while the remaining records are
+	// discarded in the background. An enclosing explicit transaction stays usable as ctx does not
+	// interrupt the connection, the session waits for the discard before its next request. The
+	// result must not be used once Stream is called, nor its session until the record channel is
+	// closed.
+	Stream(ctx context.Context) (<-chan Record, <-chan error)
 }
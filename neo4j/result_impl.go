@@ -20,40 +20,54 @@
 package neo4j
 
 import (
+	"context"
 	"time"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 type neoResult struct {
+	// ctx bounds the time spent waiting for the responses of the statement
+	ctx             context.Context
 	keys            []string
 	records         []Record
 	current         Record
 	summary         *neoResultSummary
 	runner          *statementRunner
 	err             error
-	runHandle       gobolt.RequestHandle
+	runHandle       bolt.RequestHandle
 	runCompleted    bool
-	resultHandle    gobolt.RequestHandle
+	resultHandle    bolt.RequestHandle
 	resultCompleted bool
+	// qid identifies the statement within its transaction when records are fetched in batches
+	qid       int64
+	fetchSize int
+	// discarding is set once the remaining records are of no interest anymore
+	discarding bool
+	// stream is the context of the Stream call the records are sent to, they are of no interest
+	// anymore either once it is done
+	stream context.Context
+	// span covers the statement until the result completes, it is nil for the results of
+	// BEGIN, COMMIT and ROLLBACK
+	span Span
 }
 
 var collectMetadata = func(result *neoResult, metadata map[string]interface{}) {
 	if metadata != nil {
 		if resultAvailabilityTimer, ok := metadata["result_available_after"]; ok {
-			result.summary.resultAvailableAfter = time.Duration(resultAvailabilityTimer.(int64)) / time.Millisecond
+			result.summary.resultAvailableAfter = time.Duration(resultAvailabilityTimer.(int64)) * time.Millisecond
 		}
 
 		if resultAvailabilityTimer, ok := metadata["t_first"]; ok {
-			result.summary.resultAvailableAfter = time.Duration(resultAvailabilityTimer.(int64)) / time.Millisecond
+			result.summary.resultAvailableAfter = time.Duration(resultAvailabilityTimer.(int64)) * time.Millisecond
 		}
 
 		if resultConsumptionTimer, ok := metadata["result_consumed_after"]; ok {
-			result.summary.resultConsumedAfter = time.Duration(resultConsumptionTimer.(int64)) / time.Millisecond
+			result.summary.resultConsumedAfter = time.Duration(resultConsumptionTimer.(int64)) * time.Millisecond
 		}
 
 		if resultConsumptionTimer, ok := metadata["t_last"]; ok {
-			result.summary.resultConsumedAfter = time.Duration(resultConsumptionTimer.(int64)) / time.Millisecond
+			result.summary.resultConsumedAfter = time.Duration(resultConsumptionTimer.(int64)) * time.Millisecond
 		}
 
 		if typeString, ok := metadata["type"]; ok {
@@ -140,6 +154,12 @@ func (result *neoResult) Next() bool {
 	return result.current != nil
 }
 
+func (result *neoResult) NextContext(ctx context.Context) bool {
+	defer result.withContext(ctx)()
+
+	return result.Next()
+}
+
 func (result *neoResult) Err() error {
 	return result.err
 }
@@ -148,6 +168,14 @@ func (result *neoResult) Record() Record {
 	return result.current
 }
 
+func (result *neoResult) Scan(dest interface{}) error {
+	if result.current == nil {
+		return newDriverError("there is no current record to scan, Next has to be called first")
+	}
+
+	return ScanRecord(result.current, dest)
+}
+
 func (result *neoResult) Summary() (ResultSummary, error) {
 	for result.err == nil && !result.resultCompleted {
 		if _, err := result.runner.receive(); err != nil {
@@ -164,7 +192,99 @@ func (result *neoResult) Summary() (ResultSummary, error) {
 	return result.summary, nil
 }
 
+func (result *neoResult) ConsumeContext(ctx context.Context) (ResultSummary, error) {
+	defer result.withContext(ctx)()
+
+	return result.Consume()
+}
+
+// withContext replaces the context the statement was run with and returns a function that
+// restores it
+func (result *neoResult) withContext(ctx context.Context) func() {
+	previous := result.ctx
+	result.ctx = ctx
+
+	return func() {
+		result.ctx = previous
+	}
+}
+
+func (result *neoResult) Stream(ctx context.Context) (<-chan Record, <-chan error) {
+	records := make(chan Record)
+	errs := make(chan error, 1)
+	result.stream = ctx
+
+	go func() {
+		defer close(errs)
+		defer close(records)
+
+		// records are received on another goroutine with the context of the statement, as
+		// interrupting the connection with ctx would also end the transaction the statement is
+		// part of, ctx only ends waiting for them
+		for {
+			receiving := make(chan bool, 1)
+			go func() {
+				receiving <- result.Next()
+			}()
+
+			select {
+			case more := <-receiving:
+				// records received after ctx is done are skipped, so the result may end early
+				if !more && (result.err != nil || ctx.Err() == nil) {
+					if result.err != nil {
+						errs <- result.err
+					}
+					return
+				}
+				receiving = nil
+			case <-ctx.Done():
+			}
+
+			// select picks at random when ctx is done while a consumer is ready as well
+			if receiving == nil && ctx.Err() == nil {
+				select {
+				case records <- result.current:
+					continue
+				case <-ctx.Done():
+				}
+			}
+
+			// nobody is waiting for the remaining records anymore
+			result.discardRemaining(receiving)
+			errs <- ctx.Err()
+			return
+		}
+	}()
+
+	return records, errs
+}
+
+// discards tells whether the records that are still to be received are skipped, it is safe to
+// call while the stream of the result is stopping
+func (result *neoResult) discards() bool {
+	return result.discarding || (result.stream != nil && result.stream.Err() != nil)
+}
+
+// discardRemaining skips the remaining records of the result once the receive that is still
+// under way, if any, returns. The runner waits for that before it sends anything else.
+func (result *neoResult) discardRemaining(receiving <-chan bool) {
+	discarded := make(chan struct{})
+	result.runner.discarded = discarded
+
+	go func() {
+		defer close(discarded)
+
+		if receiving != nil {
+			<-receiving
+		}
+		_, _ = result.Consume()
+	}()
+}
+
 func (result *neoResult) Consume() (ResultSummary, error) {
+	// records that are not yet received are skipped, by the server if it supports that
+	result.discarding = true
+	result.records = nil
 	for result.Next() {
 
 	}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultStream(t *testing.T) {
+	// createResult returns a result that receives a batch of records on every receive and
+	// completes with failure once the batches are exhausted
+	createResult := func(failure error, batches ...[]Record) *neoResult {
+		result := &neoResult{summary: &neoResultSummary{}, runCompleted: true}
+		result.runner = &statementRunner{receiveHandler: func(runner *statementRunner) (*neoResult, error) {
+			if result.ctx != nil && result.ctx.Err() != nil {
+				result.err, result.resultCompleted = result.ctx.Err(), true
+				return nil, result.err
+			}
+
+			if len(batches) == 0 {
+				result.err, result.resultCompleted = failure, true
+				if failure != nil {
+					return nil, failure
+				}
+				return result, nil
+			}
+
+			result.records = append(result.records, batches[0]...)
+			batches = batches[1:]
+			return result, nil
+		}}
+		return result
+	}
+
+	record := func(value int) Record {
+		return &neoRecord{keys: []string{"n"}, values: []interface{}{value}}
+	}
+
+	t.Run("shouldSendAllRecordsAndClose", func(t *testing.T) {
+		result := createResult(nil, []Record{record(1), record(2)}, []Record{record(3)})
+
+		records, errs := result.Stream(context.Background())
+
+		var values []interface{}
+		for record := range records {
+			values = append(values, record.GetByIndex(0))
+		}
+		err, open := <-errs
+
+		assert.Equal(t, []interface{}{1, 2, 3}, values)
+		assert.NoError(t, err)
+		assert.False(t, open)
+	})
+
+	t.Run("shouldSendErrorAfterRecords", func(t *testing.T) {
+		failure := fmt.Errorf("some error")
+		result := createResult(failure, []Record{record(1)})
+
+		records, errs := result.Stream(context.Background())
+
+		var values []interface{}
+		for record := range records {
+			values = append(values, record.GetByIndex(0))
+		}
+
+		assert.Equal(t, []interface{}{1}, values)
+		assert.Equal(t, failure, <-errs)
+	})
+
+	t.Run("shouldFanOutRecords", func(t *testing.T) {
+		result := createResult(nil, []Record{record(1), record(2), record(3)}, []Record{record(4), record(5)})
+
+		records, errs := result.Stream(context.Background())
+
+		var lock sync.Mutex
+		var group sync.WaitGroup
+		sum := 0
+		for i := 0; i < 3; i++ {
+			group.Add(1)
+			go func() {
+				defer group.Done()
+				for record := range records {
+					lock.Lock()
+					sum += record.GetByIndex(0).(int)
+					lock.Unlock()
+				}
+			}()
+		}
+		group.Wait()
+
+		assert.Equal(t, 15, sum)
+		assert.NoError(t, <-errs)
+	})
+
+	t.Run("shouldDiscardRemainingRecordsWhenContextIsDone", func(t *testing.T) {
+		result := createResult(nil, []Record{record(1), record(2)}, []Record{record(3)})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		records, errs := result.Stream(ctx)
+
+		first := <-records
+		cancel()
+		received := 1
+		for range records {
+			received++
+		}
+
+		assert.Equal(t, 1, first.GetByIndex(0))
+		assert.True(t, received < 3)
+		assert.Equal(t, context.Canceled, <-errs)
+		result.runner.awaitDiscard()
+		assert.True(t, result.resultCompleted)
+		assert.Empty(t, result.records)
+	})
+
+	t.Run("shouldLeaveTransactionUsableWhenContextIsDone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		connection := NewMockConnection(ctrl)
+		driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+
+		pullHandle, remaining := bolt.RequestHandle(3), 5
+		connection.EXPECT().Id().Return("conn-1", nil).AnyTimes()
+		connection.EXPECT().RemoteAddress().Return("localhost:7687", nil).AnyTimes()
+		connection.EXPECT().Server().Return("neo4j/3.5.0", nil).AnyTimes()
+		connection.EXPECT().ProtocolVersion().Return(3, nil).AnyTimes()
+		connection.EXPECT().ServerConnectionId().Return("bolt-1", nil).AnyTimes()
+		connection.EXPECT().LastBookmark().Return("", nil).AnyTimes()
+		connection.EXPECT().Close().Return(nil).AnyTimes()
+		connection.EXPECT().Flush().Return(nil).AnyTimes()
+		connection.EXPECT().Fields().Return([]string{"n"}, nil).AnyTimes()
+		connection.EXPECT().Metadata().Return(map[string]interface{}{}, nil).AnyTimes()
+		connection.EXPECT().Data().Return([]interface{}{int64(1)}, nil).AnyTimes()
+		connection.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+		connection.EXPECT().Run("UNWIND range(1, 5) AS n RETURN n", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+		connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(pullHandle, nil)
+		connection.EXPECT().Commit().Return(bolt.RequestHandle(4), nil)
+		// like the connection, a fetch with a done context resets it, which ends the transaction
+		connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, handle bolt.RequestHandle) (bolt.FetchType, error) {
+			if err := ctx.Err(); err != nil {
+				return bolt.FetchTypeError, err
+			}
+			if handle == pullHandle && remaining > 0 {
+				remaining--
+				return bolt.FetchTypeRecord, nil
+			}
+			return bolt.FetchTypeMetadata, nil
+		}).AnyTimes()
+
+		session := newSession(context.Background(), driver, SessionConfig{})
+		tx, err := session.BeginTransaction()
+		require.NoError(t, err)
+		result, err := tx.Run("UNWIND range(1, 5) AS n RETURN n", nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		records, errs := result.Stream(ctx)
+		<-records
+		cancel()
+		for range records {
+		}
+
+		assert.Equal(t, context.Canceled, <-errs)
+		assert.NoError(t, tx.Commit())
+		assert.Equal(t, 0, remaining)
+	})
+
+	t.Run("shouldStopWaitingForServerWhenContextIsDone", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		connection := NewMockConnection(ctrl)
+		driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+
+		pullHandle, discardHandle := bolt.RequestHandle(3), bolt.RequestHandle(4)
+		answer := make(chan struct{})
+		var metadata map[string]interface{}
+		connection.EXPECT().Id().Return("conn-1", nil).AnyTimes()
+		connection.EXPECT().RemoteAddress().Return("localhost:7687", nil).AnyTimes()
+		connection.EXPECT().Server().Return("neo4j/4.0.0", nil).AnyTimes()
+		connection.EXPECT().ProtocolVersion().Return(4, nil).AnyTimes()
+		connection.EXPECT().ServerConnectionId().Return("bolt-1", nil).AnyTimes()
+		connection.EXPECT().LastBookmark().Return("", nil).AnyTimes()
+		connection.EXPECT().Close().Return(nil).AnyTimes()
+		connection.EXPECT().Flush().Return(nil).AnyTimes()
+		connection.EXPECT().Fields().Return([]string{"n"}, nil).AnyTimes()
+		connection.EXPECT().Metadata().DoAndReturn(func() (map[string]interface{}, error) {
+			return metadata, nil
+		}).AnyTimes()
+		connection.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+		connection.EXPECT().Run("UNWIND range(1, 5) AS n RETURN n", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+		connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(pullHandle, nil)
+		// the rest of the records is skipped, without a RESET that would end the transaction
+		connection.EXPECT().Discard(FetchAll, int64(0)).Return(discardHandle, nil)
+		connection.EXPECT().Commit().Return(bolt.RequestHandle(5), nil)
+		connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, handle bolt.RequestHandle) (bolt.FetchType, error) {
+			switch handle {
+			case pullHandle:
+				// the server has not answered the first batch yet
+				<-answer
+				metadata = map[string]interface{}{"has_more": true}
+			case discardHandle:
+				metadata = map[string]interface{}{}
+			default:
+				metadata = map[string]interface{}{"qid": int64(0)}
+			}
+			return bolt.FetchTypeMetadata, nil
+		}).AnyTimes()
+
+		session := newSession(context.Background(), driver, SessionConfig{})
+		tx, err := session.BeginTransaction()
+		require.NoError(t, err)
+		result, err := tx.Run("UNWIND range(1, 5) AS n RETURN n", nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		records, errs := result.Stream(ctx)
+		cancel()
+		for range records {
+		}
+
+		assert.Equal(t, context.Canceled, <-errs)
+		close(answer)
+		assert.NoError(t, tx.Commit())
+	})
+}
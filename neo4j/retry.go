@@ -20,77 +20,93 @@
 package neo4j
 
 import (
-	"math/rand"
-	"strings"
+	"context"
 	"time"
 )
 
 type retryLogic struct {
-	logging           Logging
-	initialRetryDelay time.Duration
-	maxRetryTime      time.Duration
-	delayMultiplier   float64
-	delayJitter       float64
+	log          *logger
+	observers    []Observer
+	policy       RetryPolicy
+	onRetry      func(attempt int, delay time.Duration, err error)
+	maxRetryTime time.Duration
 }
 
-func newRetryLogic(config *Config) *retryLogic {
-	return &retryLogic{
-		logging:           config.Log,
-		initialRetryDelay: 1 * time.Second,
-		maxRetryTime:      config.MaxTransactionRetryTime,
-		delayMultiplier:   2.0,
-		delayJitter:       0.2,
+// newRetryLogic returns the retry logic of a transaction function, the retry policy of the
+// transaction takes precedence over the one of the driver
+func newRetryLogic(config *Config, txConfig TransactionConfig, log *logger) *retryLogic {
+	policy := txConfig.RetryPolicy
+	if policy == nil {
+		policy = config.RetryPolicy
+	}
+	if policy == nil {
+		policy = DefaultRetryPolicy()
 	}
-}
 
-func computeDelayWithJitter(logic *retryLogic, delay time.Duration) time.Duration {
-	jitter := time.Duration(float64(delay) * logic.delayJitter)
-	nextDelay := delay - jitter + time.Duration(2*float64(jitter)*rand.Float64())
-	return nextDelay
+	return &retryLogic{
+		log:          log,
+		observers:    config.Observers,
+		policy:       policy,
+		onRetry:      txConfig.OnRetry,
+		maxRetryTime: config.MaxTransactionRetryTime,
+	}
 }
 
-func (logic *retryLogic) retry(work func() (interface{}, string, error)) (interface{}, error) {
-	var result interface{}
+func (logic *retryLogic) retry(ctx context.Context, work func() (interface{}, string, error)) (interface{}, error) {
+	var errs []error
+	var id string
+	start := time.Now()
+	firstFailure := time.Time{}
 
-	count := 0
-	result = nil
-	id := "unknown"
-	err := error(nil)
-	suppressedErrors := make([]string, 0)
-	startTime := time.Time{}
-	nextDelay := logic.initialRetryDelay
-
-	for true {
-		count++
-
-		result, id, err = work()
+	for attempt := 1; ; attempt++ {
+		result, connectionId, err := work()
 		if err == nil {
 			return result, nil
 		}
+		errs, id = append(errs, err), connectionId
 
-		if isRetriableError(err) {
-			suppressedErrors = append(suppressedErrors, err.Error())
-
-			if startTime.IsZero() {
-				startTime = time.Now()
-			}
+		if !logic.policy.ShouldRetry(err, attempt) {
+			break
+		}
 
-			elapsed := time.Since(startTime)
-			if elapsed < logic.maxRetryTime {
-				delayWithJitter := computeDelayWithJitter(logic, nextDelay)
-				warningf(logic.logging, "[%s]: retryable operation failed to complete [error: %s] and will be retried in %dms", id, err.Error(), delayWithJitter.Nanoseconds()/int64(time.Millisecond))
-				time.Sleep(delayWithJitter)
-				nextDelay = delayWithJitter
-				continue
-			}
+		if firstFailure.IsZero() {
+			firstFailure = time.Now()
+		}
+		if time.Since(firstFailure) >= logic.maxRetryTime {
+			break
 		}
 
-		break
+		delay := logic.policy.Delay(attempt)
+		logic.log.warning("retryable operation failed to complete and will be retried", Field{Key: FieldConnectionId, Value: id},
+			errorField(err), Field{Key: FieldAttempt, Value: attempt}, Field{Key: FieldRetryDelay, Value: delay})
+		for _, observer := range logic.observers {
+			observer.TransactionRetried(attempt, delay, err)
+		}
+		if logic.onRetry != nil {
+			logic.onRetry(attempt, delay, err)
+		}
+		if !sleep(ctx, delay) {
+			// the errors of the attempts so far are kept along with the one of ctx
+			return nil, &RetryExhaustedError{Attempts: len(errs), Elapsed: time.Since(start), Errors: append(errs, ctx.Err()), connectionId: id}
+		}
 	}
 
-	if count == 1 {
-		return nil, err
+	if len(errs) == 1 {
+		return nil, errs[0]
 	}
 
-	return nil, newDriverError("[%s]: retryable operation failed to complete after %d tries, suppressed errors: [%s]", id, count, strings.Join(suppressedErrors, ", "))
+	return nil, &RetryExhaustedError{Attempts: len(errs), Elapsed: time.Since(start), Errors: errs, connectionId: id}
+}
+
+// sleep waits for the given duration and returns false when ctx gets done before
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
 }
@@ -20,11 +20,16 @@
 package neo4j
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 func TestRetryLogic(t *testing.T) {
@@ -49,45 +54,45 @@ func TestRetryLogic(t *testing.T) {
 	errorRetriable := newDatabaseError("TransientError", "Neo.TransientError.Some.Error", "transient error")
 
 	t.Run("should return result from work if no errors", func(t *testing.T) {
-		retryLogic := newRetryLogic(&Config{})
+		retryLogic := newRetryLogic(&Config{}, TransactionConfig{}, nil)
 
-		result, err := retryLogic.retry(mockWork(0, "conn-1", mockResult{12, nil}))
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{12, nil}))
 
 		assert.Equal(t, result, 12)
 		assert.NoError(t, err)
 	})
 
 	t.Run("should return error from work if error is not retriable", func(t *testing.T) {
-		retryLogic := newRetryLogic(&Config{})
+		retryLogic := newRetryLogic(&Config{}, TransactionConfig{}, nil)
 
-		result, err := retryLogic.retry(mockWork(0, "conn-1", mockResult{nil, errorNotRetriable}))
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorNotRetriable}))
 
 		assert.Nil(t, result)
 		assert.Equal(t, err, errorNotRetriable)
 	})
 
 	t.Run("should not return result from work if error", func(t *testing.T) {
-		retryLogic := newRetryLogic(&Config{})
+		retryLogic := newRetryLogic(&Config{}, TransactionConfig{}, nil)
 
-		result, err := retryLogic.retry(mockWork(0, "conn-1", mockResult{12, errorNotRetriable}))
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{12, errorNotRetriable}))
 
 		assert.Nil(t, result)
 		assert.Equal(t, err, errorNotRetriable)
 	})
 
 	t.Run("should retry on retriable error and return result upon successful completion", func(t *testing.T) {
-		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second})
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second}, TransactionConfig{}, nil)
 
-		result, err := retryLogic.retry(mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{12, nil}))
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{12, nil}))
 
 		assert.Equal(t, result, 12)
 		assert.NoError(t, err)
 	})
 
 	t.Run("should return error on successive transient failures", func(t *testing.T) {
-		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second})
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second}, TransactionConfig{}, nil)
 
-		result, err := retryLogic.retry(mockWork(2*time.Second, "conn-1", mockResult{nil, errorRetriable}))
+		result, err := retryLogic.retry(context.Background(), mockWork(2*time.Second, "conn-1", mockResult{nil, errorRetriable}))
 
 		assert.Nil(t, result)
 		assert.Error(t, err)
@@ -96,4 +101,149 @@ func TestRetryLogic(t *testing.T) {
 		assert.Contains(t, err.Error(), fmt.Sprintf(", %s]", errorRetriable.Error()))
 	})
 
+	t.Run("should stop retrying when the context is done", func(t *testing.T) {
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Minute}, TransactionConfig{}, nil)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		result, err := retryLogic.retry(ctx, mockWork(0, "conn-1", mockResult{nil, errorRetriable}))
+
+		assert.Nil(t, result)
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.True(t, time.Since(start) < time.Second)
+	})
+
+	t.Run("should keep the errors of the attempts when the context is done", func(t *testing.T) {
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Minute}, TransactionConfig{RetryPolicy: ConstantRetry(time.Minute)}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		work := mockWork(0, "conn-1", mockResult{nil, errorRetriable})
+		_, err := retryLogic.retry(ctx, func() (interface{}, string, error) {
+			defer cancel()
+			return work()
+		})
+
+		var exhausted *RetryExhaustedError
+		if assert.True(t, errors.As(err, &exhausted)) {
+			assert.Equal(t, 1, exhausted.Attempts)
+			assert.Equal(t, []error{errorRetriable, context.Canceled}, exhausted.Errors)
+		}
+		assert.True(t, errors.Is(err, context.Canceled))
+
+		var dbErr *Neo4jError
+		if assert.True(t, errors.As(err, &dbErr)) {
+			assert.Equal(t, errorRetriable, dbErr)
+		}
+	})
+
+	t.Run("should notify observers about retries", func(t *testing.T) {
+		observer := &recordingObserver{}
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second, Observers: []Observer{observer}}, TransactionConfig{RetryPolicy: ConstantRetry(time.Millisecond)}, nil)
+
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{nil, errorRetriable}, mockResult{12, nil}))
+
+		assert.Equal(t, result, 12)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, observer.retries)
+		assert.Equal(t, []error{errorRetriable, errorRetriable}, observer.errors)
+		for _, delay := range observer.delays {
+			assert.True(t, delay > 0 && delay < 10*time.Millisecond)
+		}
+	})
+
+	t.Run("should stop retrying when the policy says so", func(t *testing.T) {
+		var attempts []int
+		policy := RetryIf(func(err error, attempt int) bool {
+			attempts = append(attempts, attempt)
+			return attempt < 3
+		}, ConstantRetry(time.Millisecond))
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second}, TransactionConfig{RetryPolicy: policy}, nil)
+
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorNotRetriable}))
+
+		assert.Nil(t, result)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to complete after 3 tries")
+		assert.Equal(t, []int{1, 2, 3}, attempts)
+	})
+
+	t.Run("should wait the delays of the policy", func(t *testing.T) {
+		observer := &recordingObserver{}
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second, Observers: []Observer{observer}}, TransactionConfig{RetryPolicy: ExponentialRetry(time.Millisecond, 3, 0)}, nil)
+
+		_, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{nil, errorRetriable}, mockResult{12, nil}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Millisecond, 3 * time.Millisecond}, observer.delays)
+	})
+
+	t.Run("should return the errors of all attempts when retries are exhausted", func(t *testing.T) {
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second}, TransactionConfig{RetryPolicy: MaxAttempts(3, ConstantRetry(time.Millisecond))}, nil)
+
+		start := time.Now()
+		_, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{nil, errorRetriable}, mockResult{nil, errorNotRetriable}))
+
+		var exhausted *RetryExhaustedError
+		if assert.True(t, errors.As(err, &exhausted)) {
+			assert.Equal(t, 3, exhausted.Attempts)
+			assert.Equal(t, []error{errorRetriable, errorRetriable, errorNotRetriable}, exhausted.Errors)
+			assert.True(t, exhausted.Elapsed > 0 && exhausted.Elapsed <= time.Since(start))
+		}
+		assert.True(t, errors.Is(err, errorNotRetriable))
+		assert.Equal(t, errorNotRetriable, errors.Unwrap(err))
+
+		var dbErr *Neo4jError
+		if assert.True(t, errors.As(err, &dbErr)) {
+			assert.Equal(t, errorRetriable, dbErr)
+		}
+	})
+
+	t.Run("should call back before every retry", func(t *testing.T) {
+		var attempts []int
+		var delays []time.Duration
+		var errs []error
+		onRetry := func(attempt int, delay time.Duration, err error) {
+			attempts, delays, errs = append(attempts, attempt), append(delays, delay), append(errs, err)
+		}
+		retryLogic := newRetryLogic(&Config{MaxTransactionRetryTime: 5 * time.Second}, TransactionConfig{RetryPolicy: ConstantRetry(time.Millisecond), OnRetry: onRetry}, nil)
+
+		result, err := retryLogic.retry(context.Background(), mockWork(0, "conn-1", mockResult{nil, errorRetriable}, mockResult{nil, errorRetriable}, mockResult{12, nil}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, result)
+		assert.Equal(t, []int{1, 2}, attempts)
+		assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, delays)
+		assert.Equal(t, []error{errorRetriable, errorRetriable}, errs)
+	})
+
+	t.Run("should prefer the retry policy of the transaction function", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		connection := NewMockConnection(ctrl)
+		driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+		driver.config.RetryPolicy = MaxAttempts(1, DefaultRetryPolicy())
+
+		connection.EXPECT().Id().Return("conn-1", nil).AnyTimes()
+		connection.EXPECT().LastBookmark().Return("", nil).AnyTimes()
+		connection.EXPECT().Close().Return(nil).AnyTimes()
+		connection.EXPECT().Flush().Return(nil).AnyTimes()
+		connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).Return(bolt.FetchTypeMetadata, nil).AnyTimes()
+		connection.EXPECT().Metadata().Return(map[string]interface{}{}, nil).AnyTimes()
+		connection.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil).Times(3)
+		connection.EXPECT().Rollback().Return(bolt.RequestHandle(2), nil).Times(2)
+		connection.EXPECT().Commit().Return(bolt.RequestHandle(3), nil).Times(1)
+
+		work := mockWork(0, "", mockResult{nil, errorRetriable}, mockResult{nil, errorRetriable}, mockResult{12, nil})
+		session := newSession(context.Background(), driver, SessionConfig{})
+		result, err := session.WriteTransaction(func(tx Transaction) (interface{}, error) {
+			result, _, err := work()
+			return result, err
+		}, WithRetryPolicy(ConstantRetry(time.Millisecond)))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 12, result)
+	})
 }
@@ -20,7 +20,9 @@
 package neo4j
 
 import (
-	"github.com/neo4j-drivers/gobolt"
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 type runnerHandler func(*statementRunner) error
@@ -28,12 +30,23 @@ type phaseHandler func(*statementRunner, *neoResult) error
 type resultHandler func(*statementRunner) (*neoResult, error)
 
 type statementRunner struct {
-	driver         *goboltDriver
-	connection     gobolt.Connection
+	driver         *neoDriver
+	connection     bolt.Connection
 	autoClose      bool
 	accessMode     AccessMode
+	database       string
 	lastBookmark   string
 	pendingResults []*neoResult
+	// log adds the fields of the session the runner belongs to
+	log *logger
+	// boltLogger is set on the connections of the runner, it is nil unless the session
+	// overrides the bolt logger of the driver
+	boltLogger BoltLogger
+	// interrupted holds the error of the context that cut short receiving on this runner
+	interrupted error
+	// discarded is closed once the records a stream stopped receiving are discarded, nothing
+	// else can be sent on the connection before
+	discarded chan struct{}
 
 	closeHandler              runnerHandler
 	receiveHandler            resultHandler
@@ -43,21 +56,24 @@ type statementRunner struct {
 	recordsPhaseHandler       phaseHandler
 }
 
-func newRunner(driver *goboltDriver, accessMode AccessMode, autoClose bool) *statementRunner {
+func newRunner(driver *neoDriver, accessMode AccessMode, database string, autoClose bool) *statementRunner {
 	return &statementRunner{
 		driver:     driver,
 		accessMode: accessMode,
+		database:   database,
 		autoClose:  autoClose,
 	}
 }
 
 func (runner *statementRunner) lastSeenBookmark() (string, error) {
+	runner.awaitDiscard()
+
 	if runner.connection != nil {
 		var err error
 		var bookmark string
 
 		if bookmark, err = runner.connection.LastBookmark(); err != nil {
-			runner.driver.config.Log.Errorf("LastBookmark call on connection failed: %v", err)
+			runner.log.error("LastBookmark call on connection failed", errorField(err))
 		}
 
 		return bookmark, err
@@ -66,13 +82,21 @@ func (runner *statementRunner) lastSeenBookmark() (string, error) {
 	return runner.lastBookmark, nil
 }
 
+// awaitDiscard waits for the records of a stream that was stopped to be discarded
+func (runner *statementRunner) awaitDiscard() {
+	if runner.discarded != nil {
+		<-runner.discarded
+		runner.discarded = nil
+	}
+}
+
 func (runner *statementRunner) id() string {
 	var id = "unknown"
 	var err error
 
 	if runner.connection != nil {
 		if id, err = runner.connection.Id(); err != nil {
-			runner.driver.config.Log.Errorf("Id call on connection failed: %v", err)
+			runner.log.error("Id call on connection failed", errorField(err))
 			id = "unknown[failed to get id]"
 		}
 	}
@@ -85,24 +109,19 @@ func (runner *statementRunner) remoteAddress() string {
 
 	if runner.connection != nil {
 		if remoteAddress, err = runner.connection.RemoteAddress(); err != nil {
-			runner.driver.config.Log.Errorf("RemoteAddress call on connection failed: %v", err)
+			runner.log.error("RemoteAddress call on connection failed", errorField(err))
 			remoteAddress = "unknown[failed to get remote address]"
 		}
 	}
 	return remoteAddress
 }
 
-func (runner *statementRunner) version() string {
-	var version = "unknown"
-	var err error
-
-	if runner.connection != nil {
-		if version, err = runner.connection.Server(); err != nil {
-			runner.driver.config.Log.Errorf("Server call on connection failed: %v", err)
-			version = "unknown[failed to get version text]"
-		}
+func (runner *statementRunner) serverInfo() *neoServerInfo {
+	if runner.connection == nil {
+		return &neoServerInfo{address: "unknown", version: "unknown"}
 	}
-	return version
+
+	return newServerInfo(runner.driver, runner.connection)
 }
 
 func (runner *statementRunner) assertConnection() error {
@@ -122,13 +141,16 @@ func (runner *statementRunner) assertNoConnection() error {
 }
 
 // This ensures that we've a connection to run statements against
-func (runner *statementRunner) ensureConnection() error {
+func (runner *statementRunner) ensureConnection(ctx context.Context) error {
 	if runner.connection == nil {
-		connection, err := runner.driver.acquire(runner.accessMode)
+		connection, err := runner.driver.acquire(ctx, runner.accessMode, runner.database)
 		if err != nil {
 			return err
 		}
 
+		if runner.boltLogger != nil {
+			connection.SetBoltLogger(runner.boltLogger)
+		}
 		runner.connection = connection
 	}
 
@@ -137,6 +159,8 @@ func (runner *statementRunner) ensureConnection() error {
 
 // This receives all pending results and closes the connection
 func (runner *statementRunner) receiveAllAndClose() error {
+	runner.awaitDiscard()
+
 	if runner.receiveAllAndCloseHandler != nil {
 		return runner.receiveAllAndCloseHandler(runner)
 	}
@@ -169,7 +193,7 @@ func closeHandler(runner *statementRunner) error {
 		var err error
 
 		if bookmark, err = runner.connection.LastBookmark(); err != nil {
-			runner.driver.config.Log.Errorf("LastBookmark call on connection failed: %v", err)
+			runner.log.error("LastBookmark call on connection failed", errorField(err))
 		} else {
 			runner.lastBookmark = bookmark
 		}
@@ -185,6 +209,8 @@ func closeHandler(runner *statementRunner) error {
 }
 
 func (runner *statementRunner) receiveAll() error {
+	runner.awaitDiscard()
+
 	if runner.receiveAllHandler != nil {
 		return runner.receiveAllHandler(runner)
 	}
@@ -216,12 +242,12 @@ func (runner *statementRunner) handleRunPhase(activeResult *neoResult) error {
 
 func handleRunPhase(runner *statementRunner, activeResult *neoResult) error {
 	if !activeResult.runCompleted {
-		received, err := runner.connection.Fetch(activeResult.runHandle)
+		received, err := runner.connection.FetchContext(activeResult.ctx, activeResult.runHandle)
 		if err != nil {
 			return err
 		}
 
-		if received != gobolt.FetchTypeMetadata {
+		if received != bolt.FetchTypeMetadata {
 			return newDriverError("unexpected response received while waiting for a METADATA")
 		}
 
@@ -237,6 +263,9 @@ func handleRunPhase(runner *statementRunner, activeResult *neoResult) error {
 		}
 
 		collectMetadata(activeResult, metadata)
+		if qid, ok := metadata["qid"].(int64); ok {
+			activeResult.qid = qid
+		}
 		activeResult.runCompleted = true
 	}
 
@@ -253,28 +282,36 @@ func (runner *statementRunner) handleRecordsPhase(activeResult *neoResult) error
 
 func handleRecordsPhase(runner *statementRunner, activeResult *neoResult) error {
 	if !activeResult.resultCompleted {
-		received, err := runner.connection.Fetch(activeResult.resultHandle)
+		received, err := runner.connection.FetchContext(activeResult.ctx, activeResult.resultHandle)
 		if err != nil {
 			return err
 		}
 
 		switch received {
-		case gobolt.FetchTypeMetadata:
+		case bolt.FetchTypeMetadata:
 			metadata, err := runner.connection.Metadata()
 			if err != nil {
 				return err
 			}
 
+			if hasMore, _ := metadata["has_more"].(bool); hasMore {
+				return runner.requestMore(activeResult)
+			}
+
 			collectMetadata(activeResult, metadata)
 			activeResult.resultCompleted = true
-		case gobolt.FetchTypeRecord:
+		case bolt.FetchTypeRecord:
+			if activeResult.discards() {
+				return nil
+			}
+
 			fields, err := runner.connection.Data()
 			if err != nil {
 				return err
 			}
 
 			collectRecord(activeResult, fields)
-		case gobolt.FetchTypeError:
+		case bolt.FetchTypeError:
 			return newDriverError("unable to fetch from connection")
 		}
 	}
@@ -282,13 +319,62 @@ func handleRecordsPhase(runner *statementRunner, activeResult *neoResult) error
 	return nil
 }
 
+// requestMore asks for the next batch of records of a result, or for the remaining ones to be
+// skipped when the result is being discarded
+func (runner *statementRunner) requestMore(activeResult *neoResult) error {
+	var handle bolt.RequestHandle
+	var err error
+
+	if activeResult.discards() {
+		handle, err = runner.connection.Discard(FetchAll, activeResult.qid)
+	} else {
+		handle, err = runner.connection.Pull(activeResult.fetchSize, activeResult.qid)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = runner.connection.Flush(); err != nil {
+		return err
+	}
+
+	activeResult.resultHandle = handle
+	return nil
+}
+
+// receiveOpenStreams receives the pending results that are fetched in batches, as none of
+// them can be continued once another request is sent. Their errors are recorded on the
+// results themselves, only an interruption is returned as it leaves the connection reset.
+func (runner *statementRunner) receiveOpenStreams() error {
+	for _, result := range runner.pendingResults {
+		if result.fetchSize != FetchAll {
+			if err := runner.receiveAll(); err != nil && err == runner.interrupted {
+				return err
+			}
+			break
+		}
+	}
+
+	return nil
+}
+
+// discardPending marks all pending results to have their remaining records skipped rather
+// than received
+func (runner *statementRunner) discardPending() {
+	runner.awaitDiscard()
+
+	for _, result := range runner.pendingResults {
+		result.discarding = true
+	}
+}
+
 func transformError(runner *statementRunner, err error) error {
-	if gobolt.IsWriteError(err) {
+	if bolt.IsWriteError(err) {
 		if runner.accessMode == AccessModeRead {
 			return newDriverError("write queries cannot be performed in read access mode")
 		}
 
-		return newSessionExpiredError("server at %s no longer accepts writes", runner.remoteAddress())
+		return newSessionExpiredError(err, "server at %s no longer accepts writes", runner.remoteAddress())
 	}
 
 	return err
@@ -316,6 +402,10 @@ func receive(runner *statementRunner) (Result, error) {
 	activeResult := runner.pendingResults[0]
 
 	if err = runner.handleRunPhase(activeResult); err != nil {
+		if isInterruption(activeResult, err) {
+			return nil, runner.interrupt(err)
+		}
+
 		// record error on the result and return error
 		activeResult.err = transformError(runner, err)
 		activeResult.runCompleted = true
@@ -324,6 +414,10 @@ func receive(runner *statementRunner) (Result, error) {
 	}
 
 	if err = runner.handleRecordsPhase(activeResult); err != nil {
+		if isInterruption(activeResult, err) {
+			return nil, runner.interrupt(err)
+		}
+
 		// just record the error on the result
 		activeResult.err = transformError(runner, err)
 		activeResult.resultCompleted = true
@@ -331,6 +425,7 @@ func receive(runner *statementRunner) (Result, error) {
 
 	if activeResult.resultCompleted {
 		runner.pendingResults = runner.pendingResults[1:]
+		runner.completed(activeResult)
 	}
 
 	if len(runner.pendingResults) == 0 && runner.autoClose {
@@ -346,19 +441,107 @@ func receive(runner *statementRunner) (Result, error) {
 	return activeResult, nil
 }
 
-func (runner *statementRunner) runStatement(statement *neoStatement, bookmarks []string, txConfig TransactionConfig) (*neoResult, error) {
-	var runHandle, pullAllHandle gobolt.RequestHandle
+// isInterruption checks whether the error was caused by the context of the result being done
+func isInterruption(result *neoResult, err error) bool {
+	return result.ctx != nil && err == result.ctx.Err()
+}
+
+// interrupt fails all pending results with the error of a context that got done while
+// receiving, the connection has already been reset at this point so none of their
+// responses are left to receive
+func (runner *statementRunner) interrupt(err error) error {
+	for _, result := range runner.pendingResults {
+		result.err = err
+		result.runCompleted = true
+		result.resultCompleted = true
+		runner.completed(result)
+	}
+	runner.pendingResults = nil
+	runner.interrupted = err
+
+	if runner.autoClose {
+		if closeErr := runner.close(); closeErr != nil {
+			runner.log.error("unable to close connection after interruption", errorField(closeErr))
+		}
+	}
+
+	return err
+}
+
+// completed reports a result that has been received to the observers of the driver and ends
+// its span, the results of BEGIN, COMMIT and ROLLBACK have no summary and are not reported
+func (runner *statementRunner) completed(result *neoResult) {
+	if result.summary != nil {
+		runner.driver.statementCompleted(result.summary, result.err)
+	}
+
+	if result.span != nil {
+		result.span.SetAttributes(summaryAttributes(result.summary, result.err))
+		endSpan(result.span, result.err)
+	}
+}
+
+func (runner *statementRunner) runStatement(ctx context.Context, statement *neoStatement, bookmarks []string, txConfig TransactionConfig) (*neoResult, error) {
+	runner.awaitDiscard()
+
+	ctx, span := runner.driver.startSpan(ctx, SpanRun, runner.driver.statementAttributes(statement, runner.database))
+
+	result, err := runner.sendStatement(ctx, statement, bookmarks, txConfig)
+	if err != nil {
+		runner.completed(&neoResult{
+			summary: &neoResultSummary{statement: statement, server: runner.serverInfo(), counters: &neoCounters{}},
+			err:     err,
+			span:    span,
+		})
+		return nil, err
+	}
+
+	result.span = span
+	return result, nil
+}
+
+func (runner *statementRunner) sendStatement(ctx context.Context, statement *neoStatement, bookmarks []string, txConfig TransactionConfig) (*neoResult, error) {
+	var runHandle, pullHandle bolt.RequestHandle
 	var err error
 
-	if err = runner.ensureConnection(); err != nil {
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	fetchSize := txConfig.FetchSize
+	if fetchSize == FetchDefault {
+		fetchSize = defaultFetchSize
+	}
+
+	params, err := runner.driver.marshaller.marshalParams(statement.params)
+	if err != nil {
 		return nil, err
 	}
 
-	if runHandle, err = runner.connection.Run(statement.text, statement.params, bookmarks, txConfig.Timeout, txConfig.Metadata); err != nil {
+	// metadata is sent like parameters, so values written as plain values are replaced as well
+	metadata, err := runner.driver.marshaller.marshalParams(txConfig.Metadata)
+	if err != nil {
 		return nil, err
 	}
 
-	if pullAllHandle, err = runner.connection.PullAll(); err != nil {
+	if err = runner.receiveOpenStreams(); err != nil {
+		return nil, err
+	}
+
+	if err = runner.ensureConnection(ctx); err != nil {
+		return nil, err
+	}
+
+	if runner.log.enabled(DEBUG) {
+		runner.log.debug("running statement", Field{Key: FieldStatement, Value: runner.driver.redact(statement)},
+			Field{Key: FieldConnectionId, Value: runner.id()}, Field{Key: FieldDatabase, Value: runner.database})
+	}
+
+	if runHandle, err = runner.connection.Run(statement.text, params, bookmarks, txConfig.Timeout, metadata); err != nil {
+		return nil, err
+	}
+
+	if pullHandle, err = runner.connection.Pull(fetchSize, -1); err != nil {
 		return nil, err
 	}
 
@@ -367,16 +550,16 @@ func (runner *statementRunner) runStatement(statement *neoStatement, bookmarks [
 	}
 
 	result := &neoResult{
+		ctx:          ctx,
 		runner:       runner,
 		runHandle:    runHandle,
-		resultHandle: pullAllHandle,
+		resultHandle: pullHandle,
+		qid:          -1,
+		fetchSize:    fetchSize,
 		summary: &neoResultSummary{
 			statement: statement,
-			server: &neoServerInfo{
-				address: runner.remoteAddress(),
-				version: runner.version(),
-			},
-			counters: &neoCounters{},
+			server:    runner.serverInfo(),
+			counters:  &neoCounters{},
 		},
 	}
 
@@ -385,21 +568,28 @@ func (runner *statementRunner) runStatement(statement *neoStatement, bookmarks [
 	return result, nil
 }
 
-func (runner *statementRunner) beginTransaction(bookmarks []string, txConfig TransactionConfig) (*neoResult, error) {
-	var beginHandle gobolt.RequestHandle
+func (runner *statementRunner) beginTransaction(ctx context.Context, bookmarks []string, txConfig TransactionConfig) (*neoResult, error) {
+	var beginHandle bolt.RequestHandle
 	var err error
 
 	if err = runner.assertNoConnection(); err != nil {
 		return nil, err
 	}
 
-	if err = runner.ensureConnection(); err != nil {
+	runner.interrupted = nil
+
+	metadata, err := runner.driver.marshaller.marshalParams(txConfig.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = runner.ensureConnection(ctx); err != nil {
 		_ = runner.close()
 
 		return nil, err
 	}
 
-	if beginHandle, err = runner.connection.Begin(bookmarks, txConfig.Timeout, txConfig.Metadata); err != nil {
+	if beginHandle, err = runner.connection.Begin(bookmarks, txConfig.Timeout, metadata); err != nil {
 		_ = runner.close()
 
 		return nil, err
@@ -411,21 +601,31 @@ func (runner *statementRunner) beginTransaction(bookmarks []string, txConfig Tra
 		return nil, err
 	}
 
-	beginResult := &neoResult{runner: runner, runCompleted: true, resultHandle: beginHandle}
+	beginResult := &neoResult{ctx: ctx, runner: runner, runCompleted: true, resultHandle: beginHandle}
 
 	runner.pendingResults = append(runner.pendingResults, beginResult)
 
 	return beginResult, nil
 }
 
-func (runner *statementRunner) commitTransaction() (*neoResult, error) {
-	var commitHandle gobolt.RequestHandle
+func (runner *statementRunner) commitTransaction(ctx context.Context) (*neoResult, error) {
+	var commitHandle bolt.RequestHandle
 	var err error
 
+	runner.awaitDiscard()
+
 	if err = runner.assertConnection(); err != nil {
 		return nil, err
 	}
 
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if err = runner.receiveOpenStreams(); err != nil {
+		return nil, err
+	}
+
 	if commitHandle, err = runner.connection.Commit(); err != nil {
 		return nil, err
 	}
@@ -434,21 +634,28 @@ func (runner *statementRunner) commitTransaction() (*neoResult, error) {
 		return nil, err
 	}
 
-	rollbackResult := &neoResult{runner: runner, runCompleted: true, resultHandle: commitHandle}
+	rollbackResult := &neoResult{ctx: ctx, runner: runner, runCompleted: true, resultHandle: commitHandle}
 
 	runner.pendingResults = append(runner.pendingResults, rollbackResult)
 
 	return rollbackResult, nil
 }
 
-func (runner *statementRunner) rollbackTransaction() (*neoResult, error) {
-	var rollbackHandle gobolt.RequestHandle
+func (runner *statementRunner) rollbackTransaction(ctx context.Context) (*neoResult, error) {
+	var rollbackHandle bolt.RequestHandle
 	var err error
 
+	runner.awaitDiscard()
+
 	if err = runner.assertConnection(); err != nil {
 		return nil, err
 	}
 
+	runner.discardPending()
+	if err = runner.receiveOpenStreams(); err != nil {
+		return nil, err
+	}
+
 	if rollbackHandle, err = runner.connection.Rollback(); err != nil {
 		return nil, err
 	}
@@ -457,7 +664,7 @@ func (runner *statementRunner) rollbackTransaction() (*neoResult, error) {
 		return nil, err
 	}
 
-	rollbackResult := &neoResult{runner: runner, runCompleted: true, resultHandle: rollbackHandle}
+	rollbackResult := &neoResult{ctx: ctx, runner: runner, runCompleted: true, resultHandle: rollbackHandle}
 
 	runner.pendingResults = append(runner.pendingResults, rollbackResult)
 
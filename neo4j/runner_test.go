@@ -20,11 +20,12 @@
 package neo4j
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -35,11 +36,13 @@ func TestRunner(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		connection := NewMockConnection(ctrl)
 		connector := MockedConnector(connection)
-		driver := newGoboltWithConnector("bolt://localhost", connector)
-		runner := newRunner(driver, mode, autoClose)
+		driver := newDriverWithConnector("bolt://localhost", connector)
+		runner := newRunner(driver, mode, "", autoClose)
 
 		connection.EXPECT().RemoteAddress().Return("localhost:7687", nil).AnyTimes()
 		connection.EXPECT().Server().Return("neo4j/3.5.0", nil).AnyTimes()
+		connection.EXPECT().ProtocolVersion().Return(3, nil).AnyTimes()
+		connection.EXPECT().ServerConnectionId().Return("bolt-1", nil).AnyTimes()
 
 		return ctrl, connection, runner
 	}
@@ -103,7 +106,7 @@ func TestRunner(t *testing.T) {
 		ctrl, connection, runner := createMocks(t)
 		defer ctrl.Finish()
 
-		err := runner.ensureConnection()
+		err := runner.ensureConnection(context.Background())
 
 		assert.NoError(t, err)
 		assert.Equal(t, runner.connection, connection)
@@ -114,18 +117,35 @@ func TestRunner(t *testing.T) {
 		defer mockCtrl.Finish()
 
 		connector := NewMockConnector(mockCtrl)
-		driver := newGoboltWithConnector("bolt://localhost", connector)
-		runner := newRunner(driver, AccessModeWrite, true)
+		driver := newDriverWithConnector("bolt://localhost", connector)
+		runner := newRunner(driver, AccessModeWrite, "", true)
 
 		failure := fmt.Errorf("an unexpected error")
 
-		connector.EXPECT().Acquire(gobolt.AccessModeWrite).Return(nil, failure)
+		connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeWrite, "").Return(nil, failure)
 
-		err := runner.ensureConnection()
+		err := runner.ensureConnection(context.Background())
 
 		assert.Equal(t, err, failure)
 	})
 
+	t.Run("shouldAcquireConnectionForDatabaseOnEnsureConnection", func(t *testing.T) {
+		mockCtrl := gomock.NewController(t)
+		defer mockCtrl.Finish()
+
+		connector := NewMockConnector(mockCtrl)
+		connection := NewMockConnection(mockCtrl)
+		driver := newDriverWithConnector("neo4j://localhost", connector)
+		runner := newRunner(driver, AccessModeRead, "movies", true)
+
+		connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeRead, "movies").Return(connection, nil)
+
+		err := runner.ensureConnection(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, runner.connection, connection)
+	})
+
 	t.Run("shouldNotFailWhenConnectionIsNilOnClose", func(t *testing.T) {
 		ctrl, _, runner := createMocks(t)
 		defer ctrl.Finish()
@@ -137,7 +157,7 @@ func TestRunner(t *testing.T) {
 		ctrl, connection, runner := createMocks(t)
 		defer ctrl.Finish()
 
-		_ = runner.ensureConnection()
+		_ = runner.ensureConnection(context.Background())
 
 		gomock.InOrder(
 			connection.EXPECT().LastBookmark().Return("a bookmark", nil),
@@ -179,7 +199,7 @@ func TestRunner(t *testing.T) {
 		ctrl, connection, runner := createMocks(t)
 		defer ctrl.Finish()
 
-		_ = runner.ensureConnection()
+		_ = runner.ensureConnection(context.Background())
 
 		runner.lastBookmark = "a bookmark 1"
 		connection.EXPECT().LastBookmark().Return("a bookmark 2", nil)
@@ -272,8 +292,8 @@ func TestRunner(t *testing.T) {
 		txTimeout := 1 * time.Minute
 		txMetadata := map[string]interface{}{"a": 1, "b": true, "c": "something"}
 		txConfig := TransactionConfig{Timeout: txTimeout, Metadata: txMetadata}
-		runHandle := gobolt.RequestHandle(1)
-		pullAllHandle := gobolt.RequestHandle(2)
+		runHandle := bolt.RequestHandle(1)
+		pullHandle := bolt.RequestHandle(2)
 		failure := fmt.Errorf("an unexpected error")
 
 		t.Run("shouldFailWhenEnsureConnectionFails", func(t *testing.T) {
@@ -281,39 +301,77 @@ func TestRunner(t *testing.T) {
 			defer ctrl.Finish()
 
 			connector := NewMockConnector(ctrl)
-			driver := newGoboltWithConnector("bolt://localhost", connector)
-			runner := newRunner(driver, AccessModeWrite, true)
+			driver := newDriverWithConnector("bolt://localhost", connector)
+			runner := newRunner(driver, AccessModeWrite, "", true)
 
-			connector.EXPECT().Acquire(gobolt.AccessModeWrite).Return(nil, failure)
+			connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeWrite, "").Return(nil, failure)
 
-			result, err := runner.runStatement(&statement, bookmarks, txConfig)
+			result, err := runner.runStatement(context.Background(), &statement, bookmarks, txConfig)
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
 		})
 
+		t.Run("shouldFailWhenParamsCannotBeMarshalled", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			connector := NewMockConnector(ctrl)
+			driver := newDriverWithConnector("bolt://localhost", connector)
+			runner := newRunner(driver, AccessModeWrite, "", true)
+
+			connector.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			invalid := neoStatement{text: statementText, params: map[string]interface{}{"x": make(chan int)}}
+			result, err := runner.runStatement(context.Background(), &invalid, bookmarks, txConfig)
+
+			assert.EqualError(t, err, "unable to marshal parameter 'x': values of type chan int are not supported")
+			assert.Nil(t, result)
+		})
+
+		t.Run("shouldRunMarshalledParams", func(t *testing.T) {
+			ctrl, connection, runner := createMocks(t)
+			defer ctrl.Finish()
+
+			type person struct {
+				Name string `neo4j:"name"`
+			}
+			typed := neoStatement{text: statementText, params: map[string]interface{}{"p": &person{Name: "Alice"}}}
+
+			gomock.InOrder(
+				connection.EXPECT().Run(statementText, map[string]interface{}{"p": map[string]interface{}{"name": "Alice"}}, bookmarks, txTimeout, txMetadata).Return(runHandle, nil),
+				connection.EXPECT().Pull(defaultFetchSize, int64(-1)).Return(pullHandle, nil),
+				connection.EXPECT().Flush(),
+			)
+
+			result, err := runner.runStatement(context.Background(), &typed, bookmarks, txConfig)
+
+			assert.NoError(t, err)
+			assert.Equal(t, typed.params, result.summary.statement.Params())
+		})
+
 		t.Run("shouldFailWhenRunFails", func(t *testing.T) {
 			ctrl, connection, runner := createMocks(t)
 			defer ctrl.Finish()
 
 			connection.EXPECT().Run(statementText, statementParams, bookmarks, txTimeout, txMetadata).Return(runHandle, failure)
 
-			result, err := runner.runStatement(&statement, bookmarks, txConfig)
+			result, err := runner.runStatement(context.Background(), &statement, bookmarks, txConfig)
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
 		})
 
-		t.Run("shouldFailWhenPullAllFails", func(t *testing.T) {
+		t.Run("shouldFailWhenPullFails", func(t *testing.T) {
 			ctrl, connection, runner := createMocks(t)
 			defer ctrl.Finish()
 
 			gomock.InOrder(
 				connection.EXPECT().Run(statementText, statementParams, bookmarks, txTimeout, txMetadata).Return(runHandle, nil),
-				connection.EXPECT().PullAll().Return(pullAllHandle, failure),
+				connection.EXPECT().Pull(defaultFetchSize, int64(-1)).Return(pullHandle, failure),
 			)
 
-			result, err := runner.runStatement(&statement, bookmarks, txConfig)
+			result, err := runner.runStatement(context.Background(), &statement, bookmarks, txConfig)
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -325,53 +383,71 @@ func TestRunner(t *testing.T) {
 
 			gomock.InOrder(
 				connection.EXPECT().Run(statementText, statementParams, bookmarks, txTimeout, txMetadata).Return(runHandle, nil),
-				connection.EXPECT().PullAll().Return(pullAllHandle, nil),
+				connection.EXPECT().Pull(defaultFetchSize, int64(-1)).Return(pullHandle, nil),
 				connection.EXPECT().Flush().Return(failure),
 			)
 
-			result, err := runner.runStatement(&statement, bookmarks, txConfig)
+			result, err := runner.runStatement(context.Background(), &statement, bookmarks, txConfig)
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
 		})
 
-		t.Run("shouldInvokeRunPullAllAndFlush", func(t *testing.T) {
+		t.Run("shouldInvokeRunPullAndFlush", func(t *testing.T) {
 			ctrl, connection, runner := createMocks(t)
 			defer ctrl.Finish()
 
 			gomock.InOrder(
 				connection.EXPECT().Run(statementText, statementParams, bookmarks, txTimeout, txMetadata).Return(runHandle, nil),
-				connection.EXPECT().PullAll().Return(pullAllHandle, nil),
+				connection.EXPECT().Pull(defaultFetchSize, int64(-1)).Return(pullHandle, nil),
 				connection.EXPECT().Flush(),
 			)
 
-			result, err := runner.runStatement(&statement, bookmarks, txConfig)
+			result, err := runner.runStatement(context.Background(), &statement, bookmarks, txConfig)
 
 			assert.NoError(t, err)
 
 			assert.NotNil(t, result)
 			assert.Equal(t, result.runHandle, runHandle)
-			assert.Equal(t, result.resultHandle, pullAllHandle)
+			assert.Equal(t, result.resultHandle, pullHandle)
 			assert.Equal(t, result.summary.server.Address(), "localhost:7687")
 			assert.Equal(t, result.summary.server.Version(), "neo4j/3.5.0")
 		})
 
+		t.Run("shouldPullConfiguredFetchSize", func(t *testing.T) {
+			for _, fetchSize := range []int{10, FetchAll} {
+				ctrl, connection, runner := createMocks(t)
+
+				gomock.InOrder(
+					connection.EXPECT().Run(statementText, statementParams, bookmarks, txTimeout, txMetadata).Return(runHandle, nil),
+					connection.EXPECT().Pull(fetchSize, int64(-1)).Return(pullHandle, nil),
+					connection.EXPECT().Flush(),
+				)
+
+				result, err := runner.runStatement(context.Background(), &statement, bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata, FetchSize: fetchSize})
+
+				assert.NoError(t, err)
+				assert.Equal(t, fetchSize, result.fetchSize)
+				ctrl.Finish()
+			}
+		})
+
 	})
 
 	t.Run("beginTransaction", func(t *testing.T) {
 		bookmarks := []string{"bookmark 1", "bookmark 2"}
 		txTimeout := 1 * time.Minute
 		txMetadata := map[string]interface{}{"a": 1, "b": true, "c": "something"}
-		beginHandle := gobolt.RequestHandle(1)
+		beginHandle := bolt.RequestHandle(1)
 		failure := fmt.Errorf("an unexpected error")
 
 		t.Run("shouldFailWhenThereIsActiveConnection", func(t *testing.T) {
 			ctrl, _, runner := createMocks(t)
 			defer ctrl.Finish()
 
-			_ = runner.ensureConnection()
+			_ = runner.ensureConnection(context.Background())
 
-			result, err := runner.beginTransaction(bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
+			result, err := runner.beginTransaction(context.Background(), bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
 
 			assert.EqualError(t, err, "unexpected state: expected no connection bound to this runner")
 			assert.Nil(t, result)
@@ -382,12 +458,12 @@ func TestRunner(t *testing.T) {
 			defer ctrl.Finish()
 
 			connector := NewMockConnector(ctrl)
-			driver := newGoboltWithConnector("bolt://localhost", connector)
-			runner := newRunner(driver, AccessModeWrite, true)
+			driver := newDriverWithConnector("bolt://localhost", connector)
+			runner := newRunner(driver, AccessModeWrite, "", true)
 
-			connector.EXPECT().Acquire(gobolt.AccessModeWrite).Return(nil, failure)
+			connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeWrite, "").Return(nil, failure)
 
-			result, err := runner.beginTransaction(bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
+			result, err := runner.beginTransaction(context.Background(), bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -402,7 +478,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().LastBookmark(),
 				connection.EXPECT().Close())
 
-			result, err := runner.beginTransaction(bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
+			result, err := runner.beginTransaction(context.Background(), bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -418,7 +494,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().LastBookmark(),
 				connection.EXPECT().Close())
 
-			result, err := runner.beginTransaction(bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
+			result, err := runner.beginTransaction(context.Background(), bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -432,23 +508,39 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().Begin(bookmarks, txTimeout, txMetadata).Return(beginHandle, nil),
 				connection.EXPECT().Flush())
 
-			result, err := runner.beginTransaction(bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
+			result, err := runner.beginTransaction(context.Background(), bookmarks, TransactionConfig{Timeout: txTimeout, Metadata: txMetadata})
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
 			assert.Equal(t, result.resultHandle, beginHandle)
 		})
+
+		t.Run("shouldSendMetadataWrittenAsPlainValues", func(t *testing.T) {
+			ctrl, connection, runner := createMocks(t)
+			defer ctrl.Finish()
+			runner.driver.marshaller = newParamMarshaller([]ValueHandler{&decimalHandler{signature: PlainValue}})
+
+			gomock.InOrder(
+				connection.EXPECT().Begin(bookmarks, txTimeout, map[string]interface{}{"amount": "42"}).Return(beginHandle, nil),
+				connection.EXPECT().Flush())
+
+			config := TransactionConfig{Timeout: txTimeout}
+			WithTxMetadata(map[string]interface{}{"amount": decimal{units: 42}})(&config)
+			_, err := runner.beginTransaction(context.Background(), bookmarks, config)
+
+			assert.NoError(t, err)
+		})
 	})
 
 	t.Run("commitTransaction", func(t *testing.T) {
-		commitHandle := gobolt.RequestHandle(1)
+		commitHandle := bolt.RequestHandle(1)
 		failure := fmt.Errorf("an unexpected error")
 
 		t.Run("shouldFailWhenThereIsNoActiveConnection", func(t *testing.T) {
 			ctrl, _, runner := createMocks(t)
 			defer ctrl.Finish()
 
-			result, err := runner.commitTransaction()
+			result, err := runner.commitTransaction(context.Background())
 
 			assert.EqualError(t, err, "unexpected state: expected an active connection bound to this runner")
 			assert.Nil(t, result)
@@ -462,7 +554,7 @@ func TestRunner(t *testing.T) {
 
 			connection.EXPECT().Commit().Return(commitHandle, failure)
 
-			result, err := runner.commitTransaction()
+			result, err := runner.commitTransaction(context.Background())
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -479,7 +571,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().Flush().Return(failure),
 			)
 
-			result, err := runner.commitTransaction()
+			result, err := runner.commitTransaction(context.Background())
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -496,7 +588,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().Flush(),
 			)
 
-			result, err := runner.commitTransaction()
+			result, err := runner.commitTransaction(context.Background())
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
@@ -505,14 +597,14 @@ func TestRunner(t *testing.T) {
 	})
 
 	t.Run("rollbackTransaction", func(t *testing.T) {
-		rollbackHandle := gobolt.RequestHandle(1)
+		rollbackHandle := bolt.RequestHandle(1)
 		failure := fmt.Errorf("an unexpected error")
 
 		t.Run("shouldFailWhenThereIsNoActiveConnection", func(t *testing.T) {
 			ctrl, _, runner := createMocks(t)
 			defer ctrl.Finish()
 
-			result, err := runner.rollbackTransaction()
+			result, err := runner.rollbackTransaction(context.Background())
 
 			assert.EqualError(t, err, "unexpected state: expected an active connection bound to this runner")
 			assert.Nil(t, result)
@@ -526,7 +618,7 @@ func TestRunner(t *testing.T) {
 
 			connection.EXPECT().Rollback().Return(rollbackHandle, failure)
 
-			result, err := runner.rollbackTransaction()
+			result, err := runner.rollbackTransaction(context.Background())
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -543,7 +635,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().Flush().Return(failure),
 			)
 
-			result, err := runner.rollbackTransaction()
+			result, err := runner.rollbackTransaction(context.Background())
 
 			assert.Equal(t, err, failure)
 			assert.Nil(t, result)
@@ -560,7 +652,7 @@ func TestRunner(t *testing.T) {
 				connection.EXPECT().Flush(),
 			)
 
-			result, err := runner.rollbackTransaction()
+			result, err := runner.rollbackTransaction(context.Background())
 
 			assert.NoError(t, err)
 			assert.NotNil(t, result)
@@ -632,7 +724,7 @@ func TestRunner(t *testing.T) {
 			}{
 				{"an ordinary error", fmt.Errorf("an unknown error")},
 				{"a driver error", newDriverError("a driver error")},
-				{"a session expired error", newSessionExpiredError("a session expired error")},
+				{"a session expired error", newSessionExpiredError(nil, "a session expired error")},
 				{"a connector error", newConnectorError(1, 1, "text", "context", "description")},
 			}
 
@@ -666,7 +758,7 @@ func TestRunner(t *testing.T) {
 	}
 
 	createResultWithConn := func(runner *statementRunner) *neoResult {
-		_ = runner.ensureConnection()
+		_ = runner.ensureConnection(context.Background())
 
 		return createResult(runner)
 	}
@@ -683,7 +775,7 @@ func TestRunner(t *testing.T) {
 			result := createResultWithConn(runner)
 			result.runCompleted = true
 
-			connection.EXPECT().Fetch(gomock.Any()).Times(0)
+			connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).Times(0)
 			connection.EXPECT().Fields().Times(0)
 			connection.EXPECT().Metadata().Times(0)
 
@@ -696,7 +788,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchType(0), failure)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchType(0), failure)
 			connection.EXPECT().Fields().Times(0)
 			connection.EXPECT().Metadata().Times(0)
 
@@ -709,7 +801,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchTypeRecord, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchTypeRecord, nil)
 			connection.EXPECT().Fields().Times(0)
 			connection.EXPECT().Metadata().Times(0)
 
@@ -722,7 +814,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchTypeError, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchTypeError, nil)
 			connection.EXPECT().Fields().Times(0)
 			connection.EXPECT().Metadata().Times(0)
 
@@ -735,7 +827,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchTypeMetadata, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchTypeMetadata, nil)
 			connection.EXPECT().Fields().Return(nil, failure)
 			connection.EXPECT().Metadata().Times(0)
 
@@ -748,7 +840,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchTypeMetadata, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchTypeMetadata, nil)
 			connection.EXPECT().Fields().Return([]string{"a"}, nil)
 			connection.EXPECT().Metadata().Return(nil, failure)
 
@@ -758,6 +850,7 @@ func TestRunner(t *testing.T) {
 		t.Run("shouldExecuteRunPhase", func(t *testing.T) {
 			var collectedResult *neoResult
 			var collectedMetadata map[string]interface{}
+			defer func(original func(*neoResult, map[string]interface{})) { collectMetadata = original }(collectMetadata)
 			collectMetadata = func(result *neoResult, metadata map[string]interface{}) {
 				collectedResult = result
 				collectedMetadata = metadata
@@ -768,7 +861,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.runHandle).Return(gobolt.FetchTypeMetadata, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.runHandle).Return(bolt.FetchTypeMetadata, nil)
 			connection.EXPECT().Fields().Return(fields, nil)
 			connection.EXPECT().Metadata().Return(metadata, nil)
 
@@ -793,7 +886,7 @@ func TestRunner(t *testing.T) {
 			result := createResultWithConn(runner)
 			result.resultCompleted = true
 
-			connection.EXPECT().Fetch(gomock.Any()).Times(0)
+			connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).Times(0)
 			connection.EXPECT().Metadata().Times(0)
 			connection.EXPECT().Data().Times(0)
 
@@ -806,7 +899,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchType(0), failure)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchType(0), failure)
 			connection.EXPECT().Metadata().Times(0)
 			connection.EXPECT().Data().Times(0)
 
@@ -819,7 +912,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchTypeError, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeError, nil)
 			connection.EXPECT().Metadata().Times(0)
 			connection.EXPECT().Data().Times(0)
 
@@ -832,7 +925,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchTypeMetadata, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeMetadata, nil)
 			connection.EXPECT().Metadata().Return(nil, failure)
 			connection.EXPECT().Data().Times(0)
 
@@ -842,6 +935,7 @@ func TestRunner(t *testing.T) {
 		t.Run("shouldCompleteRecordPhaseOnMetadata", func(t *testing.T) {
 			var cmResult *neoResult
 			var cmMetadata map[string]interface{}
+			defer func(original func(*neoResult, map[string]interface{})) { collectMetadata = original }(collectMetadata)
 			collectMetadata = func(result *neoResult, metadata map[string]interface{}) {
 				cmResult = result
 				cmMetadata = metadata
@@ -852,7 +946,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchTypeMetadata, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeMetadata, nil)
 			connection.EXPECT().Metadata().Return(metadata, nil)
 
 			assert.NoError(t, runner.handleRecordsPhase(result))
@@ -868,7 +962,7 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchTypeRecord, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeRecord, nil)
 			connection.EXPECT().Data().Return(nil, failure)
 
 			assert.Equal(t, runner.handleRecordsPhase(result), failure)
@@ -877,6 +971,7 @@ func TestRunner(t *testing.T) {
 		t.Run("shouldCollectRecord", func(t *testing.T) {
 			var collectedResult *neoResult
 			var collectedFields []interface{}
+			defer func(original func(*neoResult, []interface{})) { collectRecord = original }(collectRecord)
 			collectRecord = func(result *neoResult, fields []interface{}) {
 				collectedResult = result
 				collectedFields = fields
@@ -887,13 +982,57 @@ func TestRunner(t *testing.T) {
 
 			result := createResultWithConn(runner)
 
-			connection.EXPECT().Fetch(result.resultHandle).Return(gobolt.FetchTypeRecord, nil)
+			connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeRecord, nil)
 			connection.EXPECT().Data().Return(record, nil)
 
 			assert.NoError(t, runner.handleRecordsPhase(result))
 			assert.Equal(t, collectedResult, result)
 			assert.Equal(t, collectedFields, record)
 		})
+		t.Run("shouldPullNextBatchWhenMoreRecordsAreAvailable", func(t *testing.T) {
+			ctrl, connection, runner := createMocks(t)
+			defer ctrl.Finish()
+
+			result := createResultWithConn(runner)
+			result.fetchSize = 10
+			result.qid = 3
+
+			gomock.InOrder(
+				connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeMetadata, nil),
+				connection.EXPECT().Metadata().Return(map[string]interface{}{"has_more": true}, nil),
+				connection.EXPECT().Pull(10, int64(3)).Return(bolt.RequestHandle(5), nil),
+				connection.EXPECT().Flush(),
+			)
+
+			assert.NoError(t, runner.handleRecordsPhase(result))
+			assert.False(t, result.resultCompleted)
+			assert.Equal(t, bolt.RequestHandle(5), result.resultHandle)
+		})
+
+		t.Run("shouldDiscardRemainingRecordsWhenDiscarding", func(t *testing.T) {
+			ctrl, connection, runner := createMocks(t)
+			defer ctrl.Finish()
+
+			result := createResultWithConn(runner)
+			result.fetchSize = 10
+			result.qid = -1
+			result.discarding = true
+
+			gomock.InOrder(
+				connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeRecord, nil),
+				connection.EXPECT().FetchContext(gomock.Any(), result.resultHandle).Return(bolt.FetchTypeMetadata, nil),
+				connection.EXPECT().Metadata().Return(map[string]interface{}{"has_more": true}, nil),
+				connection.EXPECT().Discard(-1, int64(-1)).Return(bolt.RequestHandle(5), nil),
+				connection.EXPECT().Flush(),
+			)
+			connection.EXPECT().Data().Times(0)
+
+			assert.NoError(t, runner.handleRecordsPhase(result))
+			assert.NoError(t, runner.handleRecordsPhase(result))
+			assert.Empty(t, result.records)
+			assert.False(t, result.resultCompleted)
+			assert.Equal(t, bolt.RequestHandle(5), result.resultHandle)
+		})
 	})
 
 	t.Run("receive", func(t *testing.T) {
@@ -995,6 +1134,40 @@ func TestRunner(t *testing.T) {
 			}
 		})
 
+		t.Run("shouldFailAllPendingResultsWhenInterrupted", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			runPhaseOverride := func(runner *statementRunner, result *neoResult) error {
+				return ctx.Err()
+			}
+
+			ctrl, connection, runner := createMocks(t)
+			runner.runPhaseHandler = runPhaseOverride
+			defer ctrl.Finish()
+
+			// the runner is closed right away as nothing is left to receive
+			connection.EXPECT().LastBookmark()
+			connection.EXPECT().Close()
+
+			first := createResultWithConn(runner)
+			first.ctx = ctx
+			second := createResult(runner)
+			runner.pendingResults = append(runner.pendingResults, first, second)
+
+			recvdResult, err := runner.receive()
+
+			assert.Equal(t, context.Canceled, err)
+			assert.Nil(t, recvdResult)
+			assert.Len(t, runner.pendingResults, 0)
+			assert.Equal(t, context.Canceled, runner.interrupted)
+			for _, result := range []*neoResult{first, second} {
+				assert.True(t, result.runCompleted)
+				assert.True(t, result.resultCompleted)
+				assert.Equal(t, context.Canceled, result.err)
+			}
+		})
+
 		t.Run("shouldReturnResult", func(t *testing.T) {
 			runPhaseOverride := func(runner *statementRunner, result *neoResult) error {
 				result.runCompleted = true
@@ -1139,4 +1312,90 @@ func TestRunner(t *testing.T) {
 			assert.Empty(t, runner.pendingResults)
 		})
 	})
+
+	t.Run("observers", func(t *testing.T) {
+		failure := fmt.Errorf("an unexpected error")
+
+		t.Run("shouldReportCompletedStatements", func(t *testing.T) {
+			recordsPhaseOverride := func(runner *statementRunner, result *neoResult) error {
+				result.resultCompleted = true
+				if result.summary != nil && result.summary.statement.Text() == "failing" {
+					return failure
+				}
+				return nil
+			}
+
+			ctrl, _, runner := createMocksWithParams(t, AccessModeWrite, false)
+			defer ctrl.Finish()
+			observer := &recordingObserver{}
+			runner.driver.config.Observers = []Observer{observer}
+			runner.runPhaseHandler = func(runner *statementRunner, result *neoResult) error { return nil }
+			runner.recordsPhaseHandler = recordsPhaseOverride
+
+			succeeding, failing, begin := createResultWithConn(runner), createResult(runner), createResult(runner)
+			succeeding.summary.statement = &neoStatement{text: "succeeding"}
+			failing.summary.statement = &neoStatement{text: "failing"}
+			begin.summary = nil
+			runner.pendingResults = append(runner.pendingResults, succeeding, begin, failing)
+
+			_ = runner.receiveAll()
+
+			assert.Equal(t, []string{"succeeding", "failing"}, observer.statements)
+			assert.Equal(t, []error{nil, failure}, observer.errors)
+		})
+
+		t.Run("shouldReportStatementsThatCouldNotBeSent", func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			connector := NewMockConnector(ctrl)
+			driver := newDriverWithConnector("bolt://localhost", connector)
+			observer := &recordingObserver{}
+			driver.config.Observers = []Observer{observer}
+			runner := newRunner(driver, AccessModeWrite, "", true)
+
+			connector.EXPECT().Acquire(gomock.Any(), bolt.AccessModeWrite, "").Return(nil, failure)
+
+			_, err := runner.runStatement(context.Background(), &neoStatement{text: "RETURN 1"}, nil, TransactionConfig{})
+
+			assert.Equal(t, failure, err)
+			assert.Equal(t, []string{"RETURN 1"}, observer.statements)
+			assert.Equal(t, []error{failure}, observer.errors)
+		})
+
+		t.Run("shouldReportInterruptedStatements", func(t *testing.T) {
+			ctrl, _, runner := createMocksWithParams(t, AccessModeWrite, false)
+			defer ctrl.Finish()
+			observer := &recordingObserver{}
+			runner.driver.config.Observers = []Observer{observer}
+
+			result := createResultWithConn(runner)
+			result.summary.statement = &neoStatement{text: "RETURN 1"}
+			runner.pendingResults = append(runner.pendingResults, result)
+
+			_ = runner.interrupt(context.Canceled)
+
+			assert.Equal(t, []string{"RETURN 1"}, observer.statements)
+			assert.Equal(t, []error{context.Canceled}, observer.errors)
+		})
+	})
+}
+
+// recordingObserver records the notifications it receives
+type recordingObserver struct {
+	statements []string
+	errors     []error
+	retries    []int
+	delays     []time.Duration
+}
+
+func (observer *recordingObserver) StatementCompleted(summary ResultSummary, err error) {
+	observer.statements = append(observer.statements, summary.Statement().Text())
+	observer.errors = append(observer.errors, err)
+}
+
+func (observer *recordingObserver) TransactionRetried(attempt int, delay time.Duration, err error) {
+	observer.retries = append(observer.retries, attempt)
+	observer.delays = append(observer.delays, delay)
+	observer.errors = append(observer.errors, err)
 }
@@ -19,6 +19,8 @@
 
 package neo4j
 
+import "context"
+
 // Session represents a logical connection (which is not tied to a physical connection)
 // to the server
 type Session interface {
@@ -27,14 +29,30 @@ type Session interface {
 	LastBookmark() string
 	// BeginTransaction starts a new explicit transaction on this session
 	BeginTransaction(configurers ...func(*TransactionConfig)) (Transaction, error)
+	// BeginTransactionContext starts a new explicit transaction on this session that is bound to
+	// ctx, the transaction is terminated when ctx is done before it completes
+	BeginTransactionContext(ctx context.Context, configurers ...func(*TransactionConfig)) (Transaction, error)
 	// ReadTransaction executes the given unit of work in a AccessModeRead transaction with
 	// retry logic in place
 	ReadTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error)
+	// ReadTransactionContext is like ReadTransaction but binds the transactions to ctx and stops
+	// retrying once ctx is done, with a RetryExhaustedError that holds the error of ctx as well
+	ReadTransactionContext(ctx context.Context, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error)
 	// WriteTransaction executes the given unit of work in a AccessModeWrite transaction with
 	// retry logic in place
 	WriteTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error)
-	// Run executes an auto-commit statement and returns a result
+	// WriteTransactionContext is like WriteTransaction but binds the transactions to ctx and stops
+	// retrying once ctx is done, with a RetryExhaustedError that holds the error of ctx as well
+	WriteTransactionContext(ctx context.Context, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error)
+	// Run executes an auto-commit statement and returns a result. Parameters can be structs, typed
+	// slices and maps or implement Marshaler besides the values that are natively supported.
 	Run(cypher string, params map[string]interface{}, configurers ...func(*TransactionConfig)) (Result, error)
+	// RunContext executes an auto-commit statement that is bound to ctx and returns a result,
+	// the statement is terminated when ctx is done before all of its records are received
+	RunContext(ctx context.Context, cypher string, params map[string]interface{}, configurers ...func(*TransactionConfig)) (Result, error)
+	// ServerInfo returns the information about the server the session is connected to, or
+	// connects to a server for the access mode and the database of the session when it is not
+	ServerInfo() (ServerInfo, error)
 	// Close closes any open resources and marks this session as unusable
 	Close() error
 }
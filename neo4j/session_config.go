@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *      http://www.apache.org/licenses/LICENSE-2.0
+ *
+ *  Unless required by applicable law or agreed to in writing, software
+ *  distributed under the License is distributed on an "AS IS" BASIS,
+ *  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *  See the License for the specific language governing permissions and
+ *  limitations under the License.
+ */
+package neo4j
+
+const (
+	// FetchAll turns off fetching records in batches, all records of a statement are
+	// received at once.
+	FetchAll = -1
+	// FetchDefault leaves the fetch size to the enclosing configuration, i.e. a session
+	// uses the fetch size of the driver and a transaction the one of its session.
+	FetchDefault = 0
+)
+
+// SessionConfig holds the settings of a session created through Driver.NewSession.
+//
+// To create a session that reads from the 'movies' database:
+//	driver.NewSession(SessionConfig{AccessMode: AccessModeRead, DatabaseName: "movies"})
+type SessionConfig struct {
+	// AccessMode decides whether statements are routed to servers that accept writes or to
+	// servers that serve reads. Defaults to AccessModeWrite.
+	AccessMode AccessMode
+	// Bookmarks are the bookmarks the first transaction of the session waits for before it starts.
+	Bookmarks []string
+	// DatabaseName is the name of the database all work of the session is executed against. When
+	// left empty, the default database of the server is used. Selecting a database requires
+	// Neo4j 4.0 or later.
+	DatabaseName string
+	// FetchSize is the number of records that are requested at once while iterating over the
+	// results of the session, it overrides Config.FetchSize unless left as FetchDefault.
+	FetchSize int
+	// BoltLogger receives the messages exchanged with the server over the connections of the
+	// session, it overrides Config.BoltLogger unless left nil.
+	BoltLogger BoltLogger
+}
+
+func validateFetchSize(fetchSize int) error {
+	if fetchSize < FetchAll {
+		return newDriverError("fetch size cannot be smaller than %d, but was %d", FetchAll, fetchSize)
+	}
+
+	return nil
+}
@@ -20,13 +20,25 @@
 package neo4j
 
 import (
+	"context"
+	"fmt"
 	"sync/atomic"
 )
 
+var sessionCounter int64
+
 type neoSession struct {
-	driver     *goboltDriver
+	// ctx is used for all work that is not given a context explicitly
+	ctx        context.Context
+	driver     *neoDriver
 	accessMode AccessMode
 	bookmarks  []string
+	database   string
+	fetchSize  int
+	// log adds the id of the session to the log messages of its work
+	log *logger
+	// boltLogger overrides the bolt logger of the driver for the connections of the session
+	boltLogger BoltLogger
 
 	lastBookmark string
 
@@ -35,16 +47,26 @@ type neoSession struct {
 	runner *statementRunner
 }
 
-func newSession(driver *goboltDriver, accessMode AccessMode, bookmarks []string) Session {
+func newSession(ctx context.Context, driver *neoDriver, config SessionConfig) Session {
 	// filter out bookmarks with empty string
-	bookmarks = filter(bookmarks, func(s string) bool {
+	bookmarks := filter(config.Bookmarks, func(s string) bool {
 		return len(s) > 0
 	})
 
+	fetchSize := config.FetchSize
+	if fetchSize == FetchDefault {
+		fetchSize = driver.config.FetchSize
+	}
+
 	return &neoSession{
+		ctx:          ctx,
 		driver:       driver,
-		accessMode:   accessMode,
+		accessMode:   config.AccessMode,
 		bookmarks:    bookmarks,
+		database:     config.DatabaseName,
+		fetchSize:    fetchSize,
+		log:          driver.log.with(Field{Key: FieldSessionId, Value: fmt.Sprintf("session-%d", atomic.AddInt64(&sessionCounter, 1))}),
+		boltLogger:   config.BoltLogger,
 		lastBookmark: "",
 		open:         1,
 		tx:           nil,
@@ -87,7 +109,9 @@ func ensureRunner(session *neoSession, mode AccessMode, autoClose bool) error {
 	}
 
 	if session.runner == nil {
-		session.runner = newRunner(session.driver, mode, autoClose)
+		session.runner = newRunner(session.driver, mode, session.database, autoClose)
+		session.runner.log = session.log
+		session.runner.boltLogger = session.boltLogger
 	}
 
 	return nil
@@ -100,6 +124,7 @@ func closeRunner(session *neoSession) error {
 		var err error
 		var bookmark string
 
+		session.runner.discardPending()
 		err = session.runner.receiveAllAndClose()
 
 		if bookmark, err = session.runner.lastSeenBookmark(); err == nil {
@@ -135,19 +160,53 @@ func (session *neoSession) LastBookmark() string {
 }
 
 func (session *neoSession) BeginTransaction(configurers ...func(*TransactionConfig)) (Transaction, error) {
-	return beginTransactionInternal(session, session.accessMode, configurers...)
+	return beginTransactionInternal(session.ctx, session, session.accessMode, configurers...)
+}
+
+func (session *neoSession) BeginTransactionContext(ctx context.Context, configurers ...func(*TransactionConfig)) (Transaction, error) {
+	return beginTransactionInternal(ctx, session, session.accessMode, configurers...)
 }
 
 func (session *neoSession) ReadTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
-	return runTransaction(session, AccessModeRead, work, configurers...)
+	return runTransaction(session.ctx, session, AccessModeRead, work, configurers...)
+}
+
+func (session *neoSession) ReadTransactionContext(ctx context.Context, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
+	return runTransaction(ctx, session, AccessModeRead, work, configurers...)
 }
 
 func (session *neoSession) WriteTransaction(work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
-	return runTransaction(session, AccessModeWrite, work, configurers...)
+	return runTransaction(session.ctx, session, AccessModeWrite, work, configurers...)
+}
+
+func (session *neoSession) WriteTransactionContext(ctx context.Context, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
+	return runTransaction(ctx, session, AccessModeWrite, work, configurers...)
 }
 
 func (session *neoSession) Run(cypher string, params map[string]interface{}, configurers ...func(*TransactionConfig)) (Result, error) {
-	return runStatementOnSession(session, &neoStatement{text: cypher, params: params}, configurers...)
+	return runStatementOnSession(session.ctx, session, &neoStatement{text: cypher, params: params}, configurers...)
+}
+
+func (session *neoSession) RunContext(ctx context.Context, cypher string, params map[string]interface{}, configurers ...func(*TransactionConfig)) (Result, error) {
+	return runStatementOnSession(ctx, session, &neoStatement{text: cypher, params: params}, configurers...)
+}
+
+func (session *neoSession) ServerInfo() (ServerInfo, error) {
+	if err := assertSessionOpen(session); err != nil {
+		return nil, err
+	}
+
+	if session.runner != nil && session.runner.connection != nil {
+		return session.runner.serverInfo(), nil
+	}
+
+	connection, err := session.driver.acquire(session.ctx, session.accessMode, session.database)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Close()
+
+	return newServerInfo(session.driver, connection), nil
 }
 
 func (session *neoSession) Close() error {
@@ -160,14 +219,22 @@ func (session *neoSession) Close() error {
 	return nil
 }
 
-func computeTransactionConfig(configurers ...func(config *TransactionConfig)) TransactionConfig {
-	config := TransactionConfig{Timeout: 0, Metadata: nil}
+func computeTransactionConfig(session *neoSession, configurers ...func(config *TransactionConfig)) (TransactionConfig, error) {
+	config := TransactionConfig{Timeout: 0, Metadata: nil, FetchSize: FetchDefault}
 
 	for _, configurer := range configurers {
 		configurer(&config)
 	}
 
-	return config
+	if err := validateFetchSize(config.FetchSize); err != nil {
+		return config, err
+	}
+
+	if config.FetchSize == FetchDefault {
+		config.FetchSize = session.fetchSize
+	}
+
+	return config, nil
 }
 
 func computeBookmarks(session *neoSession) []string {
@@ -181,7 +248,24 @@ func computeBookmarks(session *neoSession) []string {
 	return computedBookmarks
 }
 
-func beginTransactionInternal(session *neoSession, mode AccessMode, configurers ...func(*TransactionConfig)) (Transaction, error) {
+// beginTransactionInternal begins a transaction whose span covers it until it is committed,
+// rolled back or closed
+func beginTransactionInternal(ctx context.Context, session *neoSession, mode AccessMode, configurers ...func(*TransactionConfig)) (Transaction, error) {
+	ctx, span := session.driver.startSpan(ctx, SpanTransaction, accessModeAttributes(session.database, mode))
+	_, beginSpan := session.driver.startSpan(ctx, SpanBegin, nil)
+
+	transaction, err := beginTransaction(ctx, session, mode, configurers...)
+	endSpan(beginSpan, err)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+
+	transaction.span = span
+	return transaction, nil
+}
+
+func beginTransaction(ctx context.Context, session *neoSession, mode AccessMode, configurers ...func(*TransactionConfig)) (*neoTransaction, error) {
 	if err := ensureReady(session); err != nil {
 		return nil, err
 	}
@@ -190,7 +274,12 @@ func beginTransactionInternal(session *neoSession, mode AccessMode, configurers
 		return nil, err
 	}
 
-	beginResult, err := session.runner.beginTransaction(computeBookmarks(session), computeTransactionConfig(configurers...))
+	txConfig, err := computeTransactionConfig(session, configurers...)
+	if err != nil {
+		return nil, err
+	}
+
+	beginResult, err := session.runner.beginTransaction(ctx, computeBookmarks(session), txConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -201,12 +290,12 @@ func beginTransactionInternal(session *neoSession, mode AccessMode, configurers
 		return nil, err
 	}
 
-	transaction := &neoTransaction{session: session, beginResult: beginResult}
+	transaction := &neoTransaction{ctx: ctx, session: session, beginResult: beginResult, fetchSize: txConfig.FetchSize}
 	session.tx = transaction
 	return transaction, nil
 }
 
-func runStatementOnSession(session *neoSession, statement *neoStatement, configurers ...func(*TransactionConfig)) (Result, error) {
+func runStatementOnSession(ctx context.Context, session *neoSession, statement *neoStatement, configurers ...func(*TransactionConfig)) (Result, error) {
 	if err := statement.validate(); err != nil {
 		return nil, err
 	}
@@ -219,7 +308,12 @@ func runStatementOnSession(session *neoSession, statement *neoStatement, configu
 		return nil, err
 	}
 
-	result, err := session.runner.runStatement(statement, computeBookmarks(session), computeTransactionConfig(configurers...))
+	txConfig, err := computeTransactionConfig(session, configurers...)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := session.runner.runStatement(ctx, statement, computeBookmarks(session), txConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -227,28 +321,46 @@ func runStatementOnSession(session *neoSession, statement *neoStatement, configu
 	return result, nil
 }
 
-func runTransaction(session *neoSession, mode AccessMode, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
-	retry := newRetryLogic(session.driver.configuration())
-
-	result, err := retry.retry(func() (interface{}, string, error) {
-		tx, errWork := beginTransactionInternal(session, mode, configurers...)
-		if errWork != nil {
-			return nil, session.id(), errWork
-		}
-		defer tx.Close()
+func runTransaction(ctx context.Context, session *neoSession, mode AccessMode, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, error) {
+	txConfig, err := computeTransactionConfig(session, configurers...)
+	if err != nil {
+		return nil, err
+	}
 
-		resultWork, errWork := work(tx)
-		if errWork != nil {
-			return nil, session.id(), errWork
-		}
+	retry := newRetryLogic(session.driver.configuration(), txConfig, session.log)
+	ctx, span := session.driver.startSpan(ctx, SpanTransactionFunction, accessModeAttributes(session.database, mode))
 
-		errWork = tx.Commit()
-		if errWork != nil {
-			return nil, session.id(), errWork
-		}
+	attempt := 0
+	result, err := retry.retry(ctx, func() (interface{}, string, error) {
+		attempt++
+		attemptCtx, attemptSpan := session.driver.startSpan(ctx, SpanTransactionAttempt, map[string]interface{}{"neo4j.attempt": attempt})
 
-		return resultWork, "", nil
+		resultWork, id, errWork := attemptTransaction(attemptCtx, session, mode, work, configurers...)
+		endSpan(attemptSpan, errWork)
+		return resultWork, id, errWork
 	})
 
+	endSpan(span, err)
 	return result, err
 }
+
+// attemptTransaction runs the work of a transaction function once in a transaction of its own
+func attemptTransaction(ctx context.Context, session *neoSession, mode AccessMode, work TransactionWork, configurers ...func(*TransactionConfig)) (interface{}, string, error) {
+	tx, errWork := beginTransactionInternal(ctx, session, mode, configurers...)
+	if errWork != nil {
+		return nil, session.id(), errWork
+	}
+	defer tx.Close()
+
+	resultWork, errWork := work(tx)
+	if errWork != nil {
+		return nil, session.id(), errWork
+	}
+
+	errWork = tx.Commit()
+	if errWork != nil {
+		return nil, session.id(), errWork
+	}
+
+	return resultWork, "", nil
+}
@@ -19,17 +19,58 @@
 
 package neo4j
 
+import (
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/utils"
+)
+
 // ServerInfo contains basic information of the server.
 type ServerInfo interface {
 	// Address returns the address of the server.
 	Address() string
-	// Version returns the version of Neo4j running at the server.
+	// Version returns the version of Neo4j running at the server, it is the same as Agent.
 	Version() string
+	// Agent returns the agent string the server identified itself with, e.g. Neo4j/3.5.0.
+	Agent() string
+	// ParsedVersion returns the version of Neo4j parsed from the agent string, which can be
+	// compared to other versions.
+	ParsedVersion() utils.Version
+	// ProtocolVersion returns the version of the Bolt protocol negotiated with the server.
+	ProtocolVersion() int
+	// ConnectionId returns the id the server assigned to the connection, which is empty for
+	// servers that predate Bolt protocol version 3.
+	ConnectionId() string
 }
 
 type neoServerInfo struct {
-	address string
-	version string
+	address         string
+	version         string
+	protocolVersion int
+	connectionId    string
+}
+
+// newServerInfo collects the information about the server of the given connection, failures
+// are logged through the driver
+func newServerInfo(driver *neoDriver, connection bolt.Connection) *neoServerInfo {
+	var err error
+	info := &neoServerInfo{}
+
+	if info.address, err = connection.RemoteAddress(); err != nil {
+		driver.log.error("RemoteAddress call on connection failed", errorField(err))
+		info.address = "unknown[failed to get remote address]"
+	}
+	if info.version, err = connection.Server(); err != nil {
+		driver.log.error("Server call on connection failed", errorField(err))
+		info.version = "unknown[failed to get version text]"
+	}
+	if info.protocolVersion, err = connection.ProtocolVersion(); err != nil {
+		driver.log.error("ProtocolVersion call on connection failed", errorField(err))
+	}
+	if info.connectionId, err = connection.ServerConnectionId(); err != nil {
+		driver.log.error("ServerConnectionId call on connection failed", errorField(err))
+	}
+
+	return info
 }
 
 func (server *neoServerInfo) Address() string {
@@ -39,3 +80,19 @@ func (server *neoServerInfo) Address() string {
 func (server *neoServerInfo) Version() string {
 	return server.version
 }
+
+func (server *neoServerInfo) Agent() string {
+	return server.version
+}
+
+func (server *neoServerInfo) ParsedVersion() utils.Version {
+	return utils.VersionOf(server.version)
+}
+
+func (server *neoServerInfo) ProtocolVersion() int {
+	return server.protocolVersion
+}
+
+func (server *neoServerInfo) ConnectionId() string {
+	return server.connectionId
+}
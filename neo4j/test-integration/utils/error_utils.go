@@ -22,7 +22,7 @@ package utils
 import (
 	"fmt"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 type testDatabaseError struct {
@@ -99,14 +99,14 @@ func (failure *testDriverError) Error() string {
 	return failure.message
 }
 
-func NewDriverErrorForTest(format string, args ...interface{}) gobolt.GenericError {
+func NewDriverErrorForTest(format string, args ...interface{}) bolt.GenericError {
 	return &testDriverError{message: fmt.Sprintf(format, args...)}
 }
 
-func NewDatabaseErrorForTest(classification, code, message string) gobolt.DatabaseError {
+func NewDatabaseErrorForTest(classification, code, message string) bolt.DatabaseError {
 	return &testDatabaseError{code: code, message: message, classification: classification}
 }
 
-func NewConnectorErrorForTest(state int, code int, codeText, context, description string) gobolt.ConnectorError {
+func NewConnectorErrorForTest(state int, code int, codeText, context, description string) bolt.ConnectorError {
 	return &testConnectorError{state: state, code: code, codeText: codeText, context: context, description: description}
 }
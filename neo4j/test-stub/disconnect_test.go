@@ -23,7 +23,7 @@ import (
 	"path"
 	"testing"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j"
 	"github.com/neo4j/neo4j-go-driver/neo4j/test-stub/control"
 	"github.com/stretchr/testify/assert"
 )
@@ -54,7 +54,7 @@ func Test_Disconnect(t *testing.T) {
 
 				summary, err := result.Consume()
 				assert.Error(t, err)
-				assert.True(t, gobolt.IsServiceUnavailable(err))
+				assert.True(t, neo4j.IsServiceUnavailable(err))
 				assert.Nil(t, summary)
 			}
 
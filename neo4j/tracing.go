@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"errors"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+// Names of the spans created by the driver
+const (
+	// SpanRun covers a statement from being sent until its result is consumed or failed
+	SpanRun = "neo4j.run"
+	// SpanTransaction covers an explicit transaction from its beginning until it is
+	// committed, rolled back or closed
+	SpanTransaction = "neo4j.transaction"
+	// SpanBegin covers the beginning of a transaction
+	SpanBegin = "neo4j.begin"
+	// SpanCommit covers the commit of a transaction
+	SpanCommit = "neo4j.commit"
+	// SpanRollback covers the rollback of a transaction
+	SpanRollback = "neo4j.rollback"
+	// SpanTransactionFunction covers a transaction function with all of its attempts
+	SpanTransactionFunction = "neo4j.transaction_function"
+	// SpanTransactionAttempt covers a single attempt of a transaction function
+	SpanTransactionAttempt = "neo4j.transaction_attempt"
+)
+
+// Tracer creates the spans around the work carried out by a driver, e.g. to hand them over
+// to OpenTelemetry. Attribute values are strings, ints, bools or float64s.
+type Tracer interface {
+	// StartSpan starts a span that is a child of the span carried by ctx, if any, and
+	// returns a context that carries the new span.
+	StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttributes adds the attributes to the span.
+	SetAttributes(attributes map[string]interface{})
+	// End ends the span, err is the error the covered work failed with or nil.
+	End(err error)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes map[string]interface{}) {}
+
+func (noopSpan) End(err error) {}
+
+// startSpan starts a span with the configured tracer, it adds the attributes describing the
+// database system and returns ctx as is when no tracer is configured
+func (driver *neoDriver) startSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span) {
+	if driver.config.Tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	if attributes == nil {
+		attributes = make(map[string]interface{})
+	}
+	attributes["db.system"] = "neo4j"
+
+	return driver.config.Tracer.StartSpan(ctx, name, attributes)
+}
+
+// statementAttributes describes a statement run against the given database, the text of the
+// statement is redacted when configured
+func (driver *neoDriver) statementAttributes(statement *neoStatement, database string) map[string]interface{} {
+	attributes := databaseAttributes(database)
+	attributes["db.statement"] = driver.redact(statement)
+	return attributes
+}
+
+// redact returns the text of the statement redacted by the configured function, if any
+func (driver *neoDriver) redact(statement *neoStatement) string {
+	if driver.config.RedactStatement != nil {
+		return driver.config.RedactStatement(statement.text)
+	}
+	return statement.text
+}
+
+// databaseAttributes describes the database work is carried out against
+func databaseAttributes(database string) map[string]interface{} {
+	attributes := map[string]interface{}{}
+	if database != "" {
+		attributes["db.name"] = database
+	}
+	return attributes
+}
+
+// accessModeAttributes describes the database and the access mode of a transaction
+func accessModeAttributes(database string, mode AccessMode) map[string]interface{} {
+	attributes := databaseAttributes(database)
+	attributes["neo4j.access_mode"] = "write"
+	if mode == AccessModeRead {
+		attributes["neo4j.access_mode"] = "read"
+	}
+	return attributes
+}
+
+// summaryAttributes describes the outcome of a statement, the timings and the counters are
+// only known when the statement succeeded
+func summaryAttributes(summary ResultSummary, err error) map[string]interface{} {
+	attributes := map[string]interface{}{"server.address": summary.Server().Address()}
+	if err != nil {
+		return attributes
+	}
+
+	attributes["neo4j.result_available_after_ms"] = summary.ResultAvailableAfter().Milliseconds()
+	attributes["neo4j.result_consumed_after_ms"] = summary.ResultConsumedAfter().Milliseconds()
+
+	counters := summary.Counters()
+	for key, count := range map[string]int{
+		"neo4j.counters.nodes_created":         counters.NodesCreated(),
+		"neo4j.counters.nodes_deleted":         counters.NodesDeleted(),
+		"neo4j.counters.relationships_created": counters.RelationshipsCreated(),
+		"neo4j.counters.relationships_deleted": counters.RelationshipsDeleted(),
+		"neo4j.counters.properties_set":        counters.PropertiesSet(),
+		"neo4j.counters.labels_added":          counters.LabelsAdded(),
+		"neo4j.counters.labels_removed":        counters.LabelsRemoved(),
+		"neo4j.counters.indexes_added":         counters.IndexesAdded(),
+		"neo4j.counters.indexes_removed":       counters.IndexesRemoved(),
+		"neo4j.counters.constraints_added":     counters.ConstraintsAdded(),
+		"neo4j.counters.constraints_removed":   counters.ConstraintsRemoved(),
+	} {
+		// counters that did not change are left out to keep spans small
+		if count > 0 {
+			attributes[key] = count
+		}
+	}
+
+	return attributes
+}
+
+// endSpan ends the span with err, adding the code of errors returned by the server
+func endSpan(span Span, err error) {
+	var dbErr bolt.DatabaseError
+	if errors.As(err, &dbErr) {
+		span.SetAttributes(map[string]interface{}{"neo4j.error.code": dbErr.Code()})
+	}
+
+	span.End(err)
+}
@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+type spanKey struct{}
+
+// recordedSpan is a span created by the recordingTracer
+type recordedSpan struct {
+	name       string
+	parent     *recordedSpan
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (span *recordedSpan) SetAttributes(attributes map[string]interface{}) {
+	for key, value := range attributes {
+		span.attributes[key] = value
+	}
+}
+
+func (span *recordedSpan) End(err error) {
+	span.ended = true
+	span.err = err
+}
+
+// recordingTracer records the spans it starts in the order they were started
+type recordingTracer struct {
+	spans []*recordedSpan
+}
+
+func (tracer *recordingTracer) StartSpan(ctx context.Context, name string, attributes map[string]interface{}) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attributes: map[string]interface{}{}}
+	span.SetAttributes(attributes)
+	tracer.spans = append(tracer.spans, span)
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (tracer *recordingTracer) names() []string {
+	names := make([]string, len(tracer.spans))
+	for i, span := range tracer.spans {
+		names[i] = span.name
+	}
+	return names
+}
+
+func TestTracing(t *testing.T) {
+	failure := fmt.Errorf("an unexpected error")
+
+	createMocks := func(t *testing.T) (*gomock.Controller, *MockConnection, *neoDriver, *recordingTracer) {
+		ctrl := gomock.NewController(t)
+		connection := NewMockConnection(ctrl)
+		driver := newDriverWithConnector("bolt://localhost", MockedConnector(connection))
+		tracer := &recordingTracer{}
+		driver.config.Tracer = tracer
+
+		connection.EXPECT().RemoteAddress().Return("localhost:7687", nil).AnyTimes()
+		connection.EXPECT().Server().Return("neo4j/3.5.0", nil).AnyTimes()
+		connection.EXPECT().ProtocolVersion().Return(3, nil).AnyTimes()
+		connection.EXPECT().ServerConnectionId().Return("bolt-1", nil).AnyTimes()
+		connection.EXPECT().Id().Return("1", nil).AnyTimes()
+		connection.EXPECT().LastBookmark().Return("", nil).AnyTimes()
+		connection.EXPECT().Close().AnyTimes()
+		connection.EXPECT().Flush().AnyTimes()
+		connection.EXPECT().FetchContext(gomock.Any(), gomock.Any()).Return(bolt.FetchTypeMetadata, nil).AnyTimes()
+		connection.EXPECT().Fields().Return([]string{}, nil).AnyTimes()
+
+		return ctrl, connection, driver, tracer
+	}
+
+	t.Run("shouldCoverStatementsUntilTheirResultIsConsumed", func(t *testing.T) {
+		ctrl, connection, driver, tracer := createMocks(t)
+		defer ctrl.Finish()
+
+		connection.EXPECT().Run("CREATE (n {secret: 'x'})", gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+		connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+		connection.EXPECT().Metadata().Return(map[string]interface{}{"t_first": int64(5), "t_last": int64(7), "stats": map[string]interface{}{"nodes-created": int64(1)}}, nil).AnyTimes()
+		driver.config.RedactStatement = func(statement string) string {
+			return strings.Replace(statement, "'x'", "?", -1)
+		}
+
+		session := newSession(context.Background(), driver, SessionConfig{DatabaseName: "movies"})
+		result, err := session.Run("CREATE (n {secret: 'x'})", nil)
+		require.NoError(t, err)
+		require.Len(t, tracer.spans, 1)
+		span := tracer.spans[0]
+		assert.Equal(t, SpanRun, span.name)
+		assert.False(t, span.ended)
+
+		_, err = result.Consume()
+		require.NoError(t, err)
+
+		assert.True(t, span.ended)
+		assert.NoError(t, span.err)
+		assert.Equal(t, "neo4j", span.attributes["db.system"])
+		assert.Equal(t, "CREATE (n {secret: ?})", span.attributes["db.statement"])
+		assert.Equal(t, "movies", span.attributes["db.name"])
+		assert.Equal(t, "localhost:7687", span.attributes["server.address"])
+		assert.Equal(t, int64(5), span.attributes["neo4j.result_available_after_ms"])
+		assert.Equal(t, int64(7), span.attributes["neo4j.result_consumed_after_ms"])
+		assert.Equal(t, 1, span.attributes["neo4j.counters.nodes_created"])
+		assert.NotContains(t, span.attributes, "neo4j.counters.nodes_deleted")
+	})
+
+	t.Run("shouldEndStatementsThatCouldNotBeSent", func(t *testing.T) {
+		ctrl, connection, driver, tracer := createMocks(t)
+		defer ctrl.Finish()
+
+		connection.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(0), failure)
+
+		session := newSession(context.Background(), driver, SessionConfig{})
+		_, err := session.Run("RETURN 1", nil)
+		require.Equal(t, failure, err)
+
+		require.Len(t, tracer.spans, 1)
+		assert.True(t, tracer.spans[0].ended)
+		assert.Equal(t, failure, tracer.spans[0].err)
+		assert.NotContains(t, tracer.spans[0].attributes, "db.name")
+	})
+
+	t.Run("shouldAddTheCodesOfDatabaseErrors", func(t *testing.T) {
+		span := &recordedSpan{attributes: map[string]interface{}{}}
+		dbErr := newDatabaseError("ClientError", "Neo.ClientError.Statement.SyntaxError", "invalid syntax")
+
+		endSpan(span, dbErr)
+
+		assert.Equal(t, "Neo.ClientError.Statement.SyntaxError", span.attributes["neo4j.error.code"])
+		assert.Equal(t, dbErr, span.err)
+	})
+
+	t.Run("shouldNestTheSpansOfTransactionFunctions", func(t *testing.T) {
+		ctrl, connection, driver, tracer := createMocks(t)
+		defer ctrl.Finish()
+
+		connection.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+		connection.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(2), nil)
+		connection.EXPECT().Pull(gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(3), nil)
+		connection.EXPECT().Commit().Return(bolt.RequestHandle(4), nil)
+		connection.EXPECT().Metadata().Return(map[string]interface{}{}, nil).AnyTimes()
+
+		session := newSession(context.Background(), driver, SessionConfig{})
+		_, err := session.WriteTransaction(func(tx Transaction) (interface{}, error) {
+			return tx.Run("RETURN 1", nil)
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{SpanTransactionFunction, SpanTransactionAttempt, SpanTransaction, SpanBegin, SpanRun, SpanCommit}, tracer.names())
+		function, attempt, transaction, begin, run, commit := tracer.spans[0], tracer.spans[1], tracer.spans[2], tracer.spans[3], tracer.spans[4], tracer.spans[5]
+		assert.Nil(t, function.parent)
+		assert.Equal(t, "write", function.attributes["neo4j.access_mode"])
+		assert.Equal(t, function, attempt.parent)
+		assert.Equal(t, 1, attempt.attributes["neo4j.attempt"])
+		assert.Equal(t, attempt, transaction.parent)
+		assert.Equal(t, transaction, begin.parent)
+		assert.Equal(t, transaction, run.parent)
+		assert.Equal(t, transaction, commit.parent)
+		for _, span := range tracer.spans {
+			assert.True(t, span.ended, span.name)
+			assert.NoError(t, span.err, span.name)
+		}
+	})
+
+	t.Run("shouldEndTheSpansOfFailedTransactionFunctions", func(t *testing.T) {
+		ctrl, connection, driver, tracer := createMocks(t)
+		defer ctrl.Finish()
+
+		connection.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Any()).Return(bolt.RequestHandle(1), nil)
+		connection.EXPECT().Rollback().Return(bolt.RequestHandle(2), nil)
+		connection.EXPECT().Metadata().Return(map[string]interface{}{}, nil).AnyTimes()
+
+		session := newSession(context.Background(), driver, SessionConfig{AccessMode: AccessModeRead})
+		_, err := session.ReadTransaction(func(tx Transaction) (interface{}, error) {
+			return nil, failure
+		})
+		require.Equal(t, failure, err)
+
+		assert.Equal(t, []string{SpanTransactionFunction, SpanTransactionAttempt, SpanTransaction, SpanBegin, SpanRollback}, tracer.names())
+		assert.Equal(t, "read", tracer.spans[0].attributes["neo4j.access_mode"])
+		assert.Equal(t, failure, tracer.spans[0].err)
+		assert.Equal(t, failure, tracer.spans[1].err)
+		for _, span := range tracer.spans {
+			assert.True(t, span.ended, span.name)
+		}
+	})
+}
@@ -19,10 +19,15 @@
 
 package neo4j
 
+import "context"
+
 // Transaction represents a transaction in the Neo4j database
 type Transaction interface {
 	// Run executes a statement on this transaction and returns a result
 	Run(cypher string, params map[string]interface{}) (Result, error)
+	// RunContext executes a statement on this transaction and returns a result whose records are
+	// only waited for as long as ctx allows. When ctx is done first, the transaction is terminated.
+	RunContext(ctx context.Context, cypher string, params map[string]interface{}) (Result, error)
 	// Commit commits the transaction
 	Commit() error
 	// Rollback rolls back the transaction
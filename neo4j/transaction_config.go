@@ -29,6 +29,16 @@ type TransactionConfig struct {
 	Timeout time.Duration
 	// Metadata is the configured transaction metadata that will be attached to the underlying transaction.
 	Metadata map[string]interface{}
+	// FetchSize is the number of records that are requested at once for the statements run in the transaction,
+	// it overrides the fetch size of the session unless left as FetchDefault.
+	FetchSize int
+	// RetryPolicy decides whether and when a transaction function is attempted again after it failed, it
+	// overrides the retry policy of the driver unless left nil. It only applies to transaction functions.
+	RetryPolicy RetryPolicy
+	// OnRetry is called before a transaction function is attempted again, with the attempt that failed counted
+	// from 1, the delay until the next attempt and the error of the failed attempt. It only applies to
+	// transaction functions.
+	OnRetry func(attempt int, delay time.Duration, err error)
 }
 
 // WithTxTimeout returns a transaction configuration function that applies a timeout to a transaction.
@@ -68,3 +78,39 @@ func WithTxMetadata(metadata map[string]interface{}) func(*TransactionConfig) {
 		config.Metadata = metadata
 	}
 }
+
+// WithFetchSize returns a transaction configuration function that sets the number of records that are requested
+// at once for the statements run in a transaction, FetchAll receives all records at once.
+//
+// To stream a large result of an auto-commit transaction in batches of 100 records:
+//	session.Run("MATCH (n) RETURN n", nil, WithFetchSize(100))
+//
+// To receive all records of the statements of an explicit transaction at once:
+//	session.BeginTransaction(WithFetchSize(FetchAll))
+func WithFetchSize(fetchSize int) func(*TransactionConfig) {
+	return func(config *TransactionConfig) {
+		config.FetchSize = fetchSize
+	}
+}
+
+// WithRetryPolicy returns a transaction configuration function that sets the policy deciding whether and when a
+// transaction function is attempted again after it failed.
+//
+// To retry a write transaction function at most 3 times:
+//	session.WriteTransaction(DoWork, WithRetryPolicy(MaxAttempts(3, DefaultRetryPolicy())))
+func WithRetryPolicy(policy RetryPolicy) func(*TransactionConfig) {
+	return func(config *TransactionConfig) {
+		config.RetryPolicy = policy
+	}
+}
+
+// WithOnRetry returns a transaction configuration function that sets a callback which is called before a
+// transaction function is attempted again, e.g. to log or count the retries of a particular transaction function.
+//
+// To count the retries of a write transaction function:
+//	session.WriteTransaction(DoWork, WithOnRetry(func(attempt int, delay time.Duration, err error) { retries++ }))
+func WithOnRetry(onRetry func(attempt int, delay time.Duration, err error)) func(*TransactionConfig) {
+	return func(config *TransactionConfig) {
+		config.OnRetry = onRetry
+	}
+}
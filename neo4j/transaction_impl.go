@@ -19,10 +19,17 @@
 
 package neo4j
 
+import "context"
+
 type neoTransaction struct {
+	ctx            context.Context
 	session        *neoSession
 	outcomeApplied bool
 	beginResult    Result
+	// fetchSize applies to all statements run in the transaction
+	fetchSize int
+	// span covers the transaction until it is committed, rolled back or closed
+	span Span
 }
 
 // TransactionWork represents a unit of work that will be executed against the provided
@@ -41,12 +48,38 @@ func ensureTxState(transaction *neoTransaction) error {
 	return nil
 }
 
+// ensureNotInterrupted fails when the transaction has been terminated by a context that got done
+func ensureNotInterrupted(transaction *neoTransaction) error {
+	return transaction.session.runner.interrupted
+}
+
+// end ends the span of the transaction unless it has already ended
+func (transaction *neoTransaction) end(err error) {
+	if transaction.span != nil {
+		endSpan(transaction.span, err)
+		transaction.span = nil
+	}
+}
+
 func (transaction *neoTransaction) Commit() error {
+	_, span := transaction.session.driver.startSpan(transaction.ctx, SpanCommit, nil)
+	err := commitTransaction(transaction)
+	endSpan(span, err)
+	transaction.end(err)
+
+	return err
+}
+
+func commitTransaction(transaction *neoTransaction) error {
 	if err := ensureTxState(transaction); err != nil {
 		return err
 	}
 
-	commit, err := transaction.session.runner.commitTransaction()
+	if err := ensureNotInterrupted(transaction); err != nil {
+		return err
+	}
+
+	commit, err := transaction.session.runner.commitTransaction(transaction.ctx)
 	if err != nil {
 		return err
 	}
@@ -62,17 +95,40 @@ func (transaction *neoTransaction) Commit() error {
 }
 
 func (transaction *neoTransaction) Rollback() error {
+	_, span := transaction.session.driver.startSpan(transaction.ctx, SpanRollback, nil)
+	err := rollbackTransaction(transaction)
+	endSpan(span, err)
+	transaction.end(err)
+
+	return err
+}
+
+func rollbackTransaction(transaction *neoTransaction) error {
 	if err := ensureTxState(transaction); err != nil {
 		return err
 	}
 
-	rollback, err := transaction.session.runner.rollbackTransaction()
+	// the server has already rolled back a transaction that was terminated
+	if ensureNotInterrupted(transaction) != nil {
+		transaction.outcomeApplied = true
+
+		return nil
+	}
+
+	rollback, err := transaction.session.runner.rollbackTransaction(transaction.ctx)
 	if err != nil {
+		if ensureNotInterrupted(transaction) != nil {
+			transaction.outcomeApplied = true
+
+			return nil
+		}
+
 		return err
 	}
 
+	// an interrupted rollback still leaves the transaction rolled back by the reset
 	_, err = rollback.Consume()
-	if err != nil {
+	if err != nil && ensureNotInterrupted(transaction) == nil {
 		return err
 	}
 
@@ -89,19 +145,25 @@ func (transaction *neoTransaction) Close() error {
 	}
 
 	if err := closeRunner(transaction.session); err != nil {
+		transaction.end(err)
 		return err
 	}
 
 	transaction.session.tx = nil
+	transaction.end(nil)
 
 	return nil
 }
 
 func (transaction *neoTransaction) Run(cypher string, params map[string]interface{}) (Result, error) {
-	return runStatementOnTransaction(transaction, &neoStatement{text: cypher, params: params})
+	return runStatementOnTransaction(transaction.ctx, transaction, &neoStatement{text: cypher, params: params})
+}
+
+func (transaction *neoTransaction) RunContext(ctx context.Context, cypher string, params map[string]interface{}) (Result, error) {
+	return runStatementOnTransaction(ctx, transaction, &neoStatement{text: cypher, params: params})
 }
 
-func runStatementOnTransaction(transaction *neoTransaction, statement *neoStatement) (Result, error) {
+func runStatementOnTransaction(ctx context.Context, transaction *neoTransaction, statement *neoStatement) (Result, error) {
 	if err := statement.validate(); err != nil {
 		return nil, err
 	}
@@ -110,7 +172,11 @@ func runStatementOnTransaction(transaction *neoTransaction, statement *neoStatem
 		return nil, err
 	}
 
-	result, err := transaction.session.runner.runStatement(statement, nil, TransactionConfig{})
+	if err := ensureNotInterrupted(transaction); err != nil {
+		return nil, err
+	}
+
+	result, err := transaction.session.runner.runStatement(ctx, statement, nil, TransactionConfig{FetchSize: transaction.fetchSize})
 	if err != nil {
 		return nil, err
 	}
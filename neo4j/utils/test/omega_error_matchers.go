@@ -22,7 +22,7 @@ package test
 import (
 	"fmt"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 	"github.com/onsi/gomega"
 	"github.com/onsi/gomega/types"
 )
@@ -118,7 +118,7 @@ type connectorErrorMatcher struct {
 }
 
 func (matcher *databaseErrorMatcher) Match(actual interface{}) (success bool, err error) {
-	databaseError, ok := actual.(gobolt.DatabaseError)
+	databaseError, ok := actual.(bolt.DatabaseError)
 	if !ok {
 		return false, nil
 	}
@@ -139,7 +139,7 @@ func (matcher *databaseErrorMatcher) Match(actual interface{}) (success bool, er
 }
 
 func (matcher *databaseErrorMatcher) FailureMessage(actual interface{}) (message string) {
-	databaseError, ok := actual.(gobolt.DatabaseError)
+	databaseError, ok := actual.(bolt.DatabaseError)
 	if !ok {
 		return fmt.Sprintf("Expected\n\t%#v\nto be a DatabaseError", actual)
 	}
@@ -160,7 +160,7 @@ func (matcher *databaseErrorMatcher) FailureMessage(actual interface{}) (message
 }
 
 func (matcher *databaseErrorMatcher) NegatedFailureMessage(actual interface{}) (message string) {
-	databaseError, ok := actual.(gobolt.DatabaseError)
+	databaseError, ok := actual.(bolt.DatabaseError)
 	if !ok {
 		return fmt.Sprintf("Expected\n\t%#v\nnot to be a DatabaseError", actual)
 	}
@@ -186,7 +186,7 @@ func (matcher *serviceUnavailableErrorMatcher) Match(actual interface{}) (succes
 		return false, nil
 	}
 
-	return gobolt.IsServiceUnavailable(err), nil
+	return bolt.IsServiceUnavailable(err), nil
 }
 
 func (matcher *serviceUnavailableErrorMatcher) FailureMessage(actual interface{}) (message string) {
@@ -208,7 +208,7 @@ func (matcher *serviceUnavailableErrorMatcher) NegatedFailureMessage(actual inte
 }
 
 func (matcher *connectorErrorMatcher) Match(actual interface{}) (success bool, err error) {
-	connectorError, ok := actual.(gobolt.ConnectorError)
+	connectorError, ok := actual.(bolt.ConnectorError)
 	if !ok {
 		return false, nil
 	}
@@ -229,7 +229,7 @@ func (matcher *connectorErrorMatcher) Match(actual interface{}) (success bool, e
 }
 
 func (matcher *connectorErrorMatcher) FailureMessage(actual interface{}) (message string) {
-	connectorError, ok := actual.(gobolt.ConnectorError)
+	connectorError, ok := actual.(bolt.ConnectorError)
 	if !ok {
 		return fmt.Sprintf("Expected\n\t%#v\nto be a ConnectorError", actual)
 	}
@@ -250,7 +250,7 @@ func (matcher *connectorErrorMatcher) FailureMessage(actual interface{}) (messag
 }
 
 func (matcher *connectorErrorMatcher) NegatedFailureMessage(actual interface{}) (message string) {
-	connectorError, ok := actual.(gobolt.ConnectorError)
+	connectorError, ok := actual.(bolt.ConnectorError)
 	if !ok {
 		return fmt.Sprintf("Expected\n\t%#v\nnot to be a ConnectorError", actual)
 	}
@@ -271,7 +271,7 @@ func (matcher *connectorErrorMatcher) NegatedFailureMessage(actual interface{})
 }
 
 func (matcher *genericErrorMatcher) Match(actual interface{}) (success bool, err error) {
-	genericError, ok := actual.(gobolt.GenericError)
+	genericError, ok := actual.(bolt.GenericError)
 	if !ok {
 		return false, nil
 	}
@@ -284,7 +284,7 @@ func (matcher *genericErrorMatcher) Match(actual interface{}) (success bool, err
 }
 
 func (matcher *genericErrorMatcher) FailureMessage(actual interface{}) (message string) {
-	genericError, ok := actual.(gobolt.GenericError)
+	genericError, ok := actual.(bolt.GenericError)
 	if !ok {
 		return fmt.Sprintf("Expected\n\t%#v\nto be a GenericError", actual)
 	}
@@ -297,7 +297,7 @@ func (matcher *genericErrorMatcher) FailureMessage(actual interface{}) (message
 }
 
 func (matcher *genericErrorMatcher) NegatedFailureMessage(actual interface{}) (message string) {
-	genericError, ok := actual.(gobolt.GenericError)
+	genericError, ok := actual.(bolt.GenericError)
 	if ok {
 		return fmt.Sprintf("Expected\n\t%#v\nnot to be a GenericError", actual)
 	}
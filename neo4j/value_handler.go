@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) "Neo4j"
+ * Neo4j Sweden AB [http://neo4j.com]
+ *
+ * This file is part of Neo4j.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package neo4j
+
+import (
+	"reflect"
+
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
+)
+
+// PlainValue can be returned by ValueHandler.Write in place of a struct signature to send its
+// only field as it is instead of as a struct, e.g. to send a decimal type as a string. Plain
+// values are supported in parameters and transaction metadata.
+const PlainValue int16 = -1
+
+// ValueHandler converts between Go values and the PackStream structs they are exchanged as
+// with the server. Handlers are registered through Config.ValueHandlers, where they take
+// precedence over the handlers of the driver for the same struct signatures and Go types.
+type ValueHandler interface {
+	// ReadableStructs returns the struct signatures this handler reads.
+	ReadableStructs() []int16
+	// WritableTypes returns the Go types this handler writes.
+	WritableTypes() []reflect.Type
+	// Read converts the fields of a received struct with the given signature into a Go value.
+	Read(signature int16, values []interface{}) (interface{}, error)
+	// Write converts a Go value into the signature and fields of the struct it is sent as, or
+	// into PlainValue and a single field.
+	Write(value interface{}) (int16, []interface{}, error)
+}
+
+// valueHandlers returns the handlers of the graph, spatial and temporal types followed by the
+// provided custom handlers
+func valueHandlers(custom []ValueHandler) []bolt.ValueHandler {
+	handlers := []bolt.ValueHandler{
+		&nodeValueHandler{},
+		&relationshipValueHandler{},
+		&pathValueHandler{},
+		&pointValueHandler{},
+		&dateValueHandler{},
+		&localTimeValueHandler{},
+		&offsetTimeValueHandler{},
+		&localDateTimeValueHandler{},
+		&dateTimeValueHandler{},
+		&durationValueHandler{},
+	}
+
+	for _, handler := range custom {
+		handlers = append(handlers, handler)
+	}
+
+	return handlers
+}
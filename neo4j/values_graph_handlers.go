@@ -22,7 +22,7 @@ package neo4j
 import (
 	"reflect"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 type nodeValueHandler struct {
@@ -44,7 +44,7 @@ func (handler *nodeValueHandler) WritableTypes() []reflect.Type {
 
 func (handler *nodeValueHandler) Read(signature int16, values []interface{}) (interface{}, error) {
 	if len(values) != 3 {
-		return nil, gobolt.NewValueHandlerError("expected node struct to have %d fields but received %d", 3, len(values))
+		return nil, bolt.NewValueHandlerError("expected node struct to have %d fields but received %d", 3, len(values))
 	}
 
 	idValue := values[0].(int64)
@@ -65,7 +65,7 @@ func (handler *nodeValueHandler) Read(signature int16, values []interface{}) (in
 }
 
 func (handler *nodeValueHandler) Write(value interface{}) (int16, []interface{}, error) {
-	return 0, nil, gobolt.NewValueHandlerError("Write is not supported for node values")
+	return 0, nil, bolt.NewValueHandlerError("Write is not supported for node values")
 }
 
 func (handler *relationshipValueHandler) ReadableStructs() []int16 {
@@ -79,7 +79,7 @@ func (handler *relationshipValueHandler) WritableTypes() []reflect.Type {
 func (handler *relationshipValueHandler) Read(signature int16, values []interface{}) (interface{}, error) {
 	if signature == 'R' {
 		if len(values) != 5 {
-			return nil, gobolt.NewValueHandlerError("expected relationship struct to have %d fields but received %d", 5, len(values))
+			return nil, bolt.NewValueHandlerError("expected relationship struct to have %d fields but received %d", 5, len(values))
 		}
 
 		idValue := values[0].(int64)
@@ -98,7 +98,7 @@ func (handler *relationshipValueHandler) Read(signature int16, values []interfac
 	}
 
 	if len(values) != 3 {
-		return nil, gobolt.NewValueHandlerError("expected unbound relationship struct to have %d fields but received %d", 3, len(values))
+		return nil, bolt.NewValueHandlerError("expected unbound relationship struct to have %d fields but received %d", 3, len(values))
 	}
 
 	idValue := values[0].(int64)
@@ -115,7 +115,7 @@ func (handler *relationshipValueHandler) Read(signature int16, values []interfac
 }
 
 func (handler *relationshipValueHandler) Write(value interface{}) (int16, []interface{}, error) {
-	return 0, nil, gobolt.NewValueHandlerError("Write is not supported for relationship values")
+	return 0, nil, bolt.NewValueHandlerError("Write is not supported for relationship values")
 }
 
 func (handler *pathValueHandler) ReadableStructs() []int16 {
@@ -128,7 +128,7 @@ func (handler *pathValueHandler) WritableTypes() []reflect.Type {
 
 func (handler *pathValueHandler) Read(signature int16, values []interface{}) (interface{}, error) {
 	if len(values) != 3 {
-		return nil, gobolt.NewValueHandlerError("expected path struct to have %d fields but received %d", 3, len(values))
+		return nil, bolt.NewValueHandlerError("expected path struct to have %d fields but received %d", 3, len(values))
 	}
 
 	uniqueNodesValue := values[0].([]interface{})
@@ -180,5 +180,5 @@ func (handler *pathValueHandler) Read(signature int16, values []interface{}) (in
 }
 
 func (handler *pathValueHandler) Write(value interface{}) (int16, []interface{}, error) {
-	return 0, nil, gobolt.NewValueHandlerError("Write is not supported for path values")
+	return 0, nil, bolt.NewValueHandlerError("Write is not supported for path values")
 }
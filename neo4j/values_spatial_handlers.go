@@ -23,7 +23,7 @@ import (
 	"math"
 	"reflect"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 const (
@@ -54,7 +54,7 @@ func (handler *pointValueHandler) Read(signature int16, values []interface{}) (i
 	switch signature {
 	case point2DSignature:
 		if len(values) != point2DSize {
-			return nil, gobolt.NewValueHandlerError("expected Point2D struct to have %d fields but received %d", point2DSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected Point2D struct to have %d fields but received %d", point2DSize, len(values))
 		}
 
 		dimension = 2
@@ -64,7 +64,7 @@ func (handler *pointValueHandler) Read(signature int16, values []interface{}) (i
 		z = math.NaN()
 	case point3DSignature:
 		if len(values) != point3DSize {
-			return nil, gobolt.NewValueHandlerError("expected Point3D struct to have %d fields but received %d", point3DSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected Point3D struct to have %d fields but received %d", point3DSize, len(values))
 		}
 
 		dimension = 3
@@ -73,7 +73,7 @@ func (handler *pointValueHandler) Read(signature int16, values []interface{}) (i
 		y = values[2].(float64)
 		z = values[3].(float64)
 	default:
-		return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to PointValueHandler: %#x", signature)
+		return nil, bolt.NewValueHandlerError("unexpected struct signature provided to PointValueHandler: %#x", signature)
 	}
 
 	return &Point{
@@ -113,5 +113,5 @@ func (handler *pointValueHandler) Write(value interface{}) (int16, []interface{}
 		}
 	}
 
-	return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by PointValueHandler", value)
+	return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by PointValueHandler", value)
 }
@@ -245,6 +245,19 @@ func DurationOf(months int64, days int64, seconds int64, nanos int) Duration {
 	return Duration{months, days, seconds, nanos}
 }
 
+// durationOfGoDuration converts a time.Duration into a Duration with whole seconds and
+// non-negative nanoseconds
+func durationOfGoDuration(of time.Duration) Duration {
+	seconds := int64(of / time.Second)
+	nanos := int(of % time.Second)
+	if nanos < 0 {
+		seconds--
+		nanos += int(time.Second)
+	}
+
+	return DurationOf(0, 0, seconds, nanos)
+}
+
 // Months returns the number of months in this duration.
 func (duration Duration) Months() int64 {
 	return duration.months
@@ -21,9 +21,10 @@ package neo4j
 
 import (
 	"reflect"
+	"sync"
 	"time"
 
-	"github.com/neo4j-drivers/gobolt"
+	"github.com/neo4j/neo4j-go-driver/neo4j/internal/bolt"
 )
 
 const (
@@ -42,6 +43,10 @@ const (
 	dateTimeSize                int   = 3
 )
 
+// zones caches the locations of zone ids as loading them from the zone database is expensive,
+// zone ids that are not known are cached as nil
+var zones sync.Map
+
 type dateTimeValueHandler struct {
 }
 
@@ -72,13 +77,13 @@ func (handler *dateValueHandler) Read(signature int16, values []interface{}) (in
 	switch signature {
 	case dateSignature:
 		if len(values) != dateSize {
-			return nil, gobolt.NewValueHandlerError("expected date struct to have %d fields but received %d", dateSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected date struct to have %d fields but received %d", dateSize, len(values))
 		}
 		epochDays := values[0].(int64)
 		return Date{epochDays}, nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to dateTimeValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to dateTimeValueHandler: %#x", signature)
 }
 
 func (handler *dateValueHandler) Write(value interface{}) (int16, []interface{}, error) {
@@ -86,7 +91,7 @@ func (handler *dateValueHandler) Write(value interface{}) (int16, []interface{},
 	var ok bool
 
 	if date, ok = value.(Date); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by dateValueHandler", value)
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by dateValueHandler", value)
 	}
 
 	return dateSignature, []interface{}{date.epochDays}, nil
@@ -104,13 +109,13 @@ func (handler *localTimeValueHandler) Read(signature int16, values []interface{}
 	switch signature {
 	case localTimeSignature:
 		if len(values) != localTimeSize {
-			return nil, gobolt.NewValueHandlerError("expected local time struct to have %d fields but received %d", localTimeSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected local time struct to have %d fields but received %d", localTimeSize, len(values))
 		}
 		nanosOfDay := values[0].(int64)
 		return LocalTime{time.Duration(nanosOfDay)}, nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to localTimeValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to localTimeValueHandler: %#x", signature)
 }
 
 func (handler *localTimeValueHandler) Write(value interface{}) (int16, []interface{}, error) {
@@ -118,7 +123,7 @@ func (handler *localTimeValueHandler) Write(value interface{}) (int16, []interfa
 	var ok bool
 
 	if localTime, ok = value.(LocalTime); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by localTimeValueHandler", value)
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by localTimeValueHandler", value)
 	}
 
 	return localTimeSignature, []interface{}{int64(localTime.nanosOfDay)}, nil
@@ -136,14 +141,14 @@ func (handler *offsetTimeValueHandler) Read(signature int16, values []interface{
 	switch signature {
 	case offsetTimeSignature:
 		if len(values) != offsetTimeSize {
-			return nil, gobolt.NewValueHandlerError("expected offset time struct to have %d fields but received %d", offsetTimeSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected offset time struct to have %d fields but received %d", offsetTimeSize, len(values))
 		}
 		nanosOfDay := values[0].(int64)
 		offset := values[1].(int64)
 		return OffsetTime{time.Duration(nanosOfDay), int(offset)}, nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to offsetTimeValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to offsetTimeValueHandler: %#x", signature)
 }
 
 func (handler *offsetTimeValueHandler) Write(value interface{}) (int16, []interface{}, error) {
@@ -151,7 +156,7 @@ func (handler *offsetTimeValueHandler) Write(value interface{}) (int16, []interf
 	var ok bool
 
 	if offsetTime, ok = value.(OffsetTime); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by offsetTimeValueHandler", value)
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by offsetTimeValueHandler", value)
 	}
 
 	return offsetTimeSignature, []interface{}{int64(offsetTime.nanosOfDay), offsetTime.offset}, nil
@@ -162,14 +167,14 @@ func (handler *durationValueHandler) ReadableStructs() []int16 {
 }
 
 func (handler *durationValueHandler) WritableTypes() []reflect.Type {
-	return []reflect.Type{reflect.TypeOf(Duration{})}
+	return []reflect.Type{reflect.TypeOf(Duration{}), reflect.TypeOf(time.Duration(0))}
 }
 
 func (handler *durationValueHandler) Read(signature int16, values []interface{}) (interface{}, error) {
 	switch signature {
 	case durationSignature:
 		if len(values) != durationSize {
-			return nil, gobolt.NewValueHandlerError("expected duration struct to have %d fields but received %d", durationSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected duration struct to have %d fields but received %d", durationSize, len(values))
 		}
 		months := values[0].(int64)
 		days := values[1].(int64)
@@ -178,15 +183,19 @@ func (handler *durationValueHandler) Read(signature int16, values []interface{})
 		return Duration{months, days, seconds, int(nanos)}, nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to durationValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to durationValueHandler: %#x", signature)
 }
 
 func (handler *durationValueHandler) Write(value interface{}) (int16, []interface{}, error) {
 	var duration Duration
-	var ok bool
 
-	if duration, ok = value.(Duration); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by durationValueHandler", value)
+	switch v := value.(type) {
+	case Duration:
+		duration = v
+	case time.Duration:
+		duration = durationOfGoDuration(v)
+	default:
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by durationValueHandler", value)
 	}
 
 	return durationSignature, []interface{}{duration.months, duration.days, duration.seconds, duration.nanos}, nil
@@ -204,7 +213,7 @@ func (handler *localDateTimeValueHandler) Read(signature int16, values []interfa
 	switch signature {
 	case localDateTimeSignature:
 		if len(values) != localDateTimeSize {
-			return nil, gobolt.NewValueHandlerError("expected local date time struct to have %d fields but received %d", localDateTimeSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected local date time struct to have %d fields but received %d", localDateTimeSize, len(values))
 		}
 
 		sec := values[0].(int64)
@@ -213,7 +222,7 @@ func (handler *localDateTimeValueHandler) Read(signature int16, values []interfa
 		return LocalDateTime{sec, int(nsec)}, nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to localDateTimeValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to localDateTimeValueHandler: %#x", signature)
 }
 
 func (handler *localDateTimeValueHandler) Write(value interface{}) (int16, []interface{}, error) {
@@ -221,7 +230,7 @@ func (handler *localDateTimeValueHandler) Write(value interface{}) (int16, []int
 	var ok bool
 
 	if localDateTime, ok = value.(LocalDateTime); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by localDateTimeValueHandler", value)
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by localDateTimeValueHandler", value)
 	}
 
 	return localDateTimeSignature, []interface{}{
@@ -242,15 +251,15 @@ func (handler *dateTimeValueHandler) Read(signature int16, values []interface{})
 	switch signature {
 	case dateTimeWithZoneIdSignature:
 		if len(values) != dateTimeSize {
-			return nil, gobolt.NewValueHandlerError("expected date time with zone id struct to have %d fields but received %d", dateTimeSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected date time with zone id struct to have %d fields but received %d", dateTimeSize, len(values))
 		}
 
 		sec := values[0].(int64)
 		nsec := values[1].(int64)
 		zone := values[2].(string)
-		location, err := time.LoadLocation(zone)
-		if err != nil {
-			return nil, gobolt.NewValueHandlerError("Unable to load time zone '%s'", zone)
+		location := loadZone(zone)
+		if location == nil {
+			return nil, bolt.NewValueHandlerError("Unable to load time zone '%s'", zone)
 		}
 
 		utcTime := epochUtc.Add(time.Duration(sec)*time.Second + time.Duration(nsec))
@@ -258,7 +267,7 @@ func (handler *dateTimeValueHandler) Read(signature int16, values []interface{})
 		return time.Date(utcTime.Year(), utcTime.Month(), utcTime.Day(), utcTime.Hour(), utcTime.Minute(), utcTime.Second(), utcTime.Nanosecond(), location), nil
 	case dateTimeWithOffsetSignature:
 		if len(values) != dateTimeSize {
-			return nil, gobolt.NewValueHandlerError("expected date time with offset struct to have %d fields but received %d", dateTimeSize, len(values))
+			return nil, bolt.NewValueHandlerError("expected date time with offset struct to have %d fields but received %d", dateTimeSize, len(values))
 		}
 
 		sec := values[0].(int64)
@@ -271,7 +280,7 @@ func (handler *dateTimeValueHandler) Read(signature int16, values []interface{})
 		return time.Date(utcTime.Year(), utcTime.Month(), utcTime.Day(), utcTime.Hour(), utcTime.Minute(), utcTime.Second(), utcTime.Nanosecond(), location), nil
 	}
 
-	return nil, gobolt.NewValueHandlerError("unexpected struct signature provided to dateTimeValueHandler: %#x", signature)
+	return nil, bolt.NewValueHandlerError("unexpected struct signature provided to dateTimeValueHandler: %#x", signature)
 }
 
 func (handler *dateTimeValueHandler) Write(value interface{}) (int16, []interface{}, error) {
@@ -279,16 +288,17 @@ func (handler *dateTimeValueHandler) Write(value interface{}) (int16, []interfac
 	var ok bool
 
 	if dateTime, ok = value.(time.Time); !ok {
-		return 0, nil, gobolt.NewValueHandlerError("passed in value %v is not supported by dateTimeValueHandler", value)
+		return 0, nil, bolt.NewValueHandlerError("passed in value %v is not supported by dateTimeValueHandler", value)
 	}
 
-	location := dateTime.Location()
-	zoneId, offset := dateTime.Zone()
+	zoneId := dateTime.Location().String()
+	_, offset := dateTime.Zone()
 	utcTime := time.Date(dateTime.Year(), dateTime.Month(), dateTime.Day(), dateTime.Hour(), dateTime.Minute(), dateTime.Second(), dateTime.Nanosecond(), time.UTC)
 	sec := utcTime.Unix()
 	nsec := utcTime.Nanosecond()
 
-	if zoneId == "Offset" {
+	// the local zone and fixed zones can only be sent by their offset
+	if !isZoneId(zoneId) {
 		// with offset seconds
 		return dateTimeWithOffsetSignature, []interface{}{
 			sec,
@@ -301,7 +311,33 @@ func (handler *dateTimeValueHandler) Write(value interface{}) (int16, []interfac
 	return dateTimeWithZoneIdSignature, []interface{}{
 		sec,
 		nsec,
-		location.String(),
+		zoneId,
 	}, nil
+}
+
+// isZoneId checks whether the name of a location is a zone id the server knows as well, fixed
+// zones created with an offset only, e.g. by parsing RFC 3339, have an empty name or one that
+// is not known to the zone database
+func isZoneId(name string) bool {
+	if name == "" || name == "Local" {
+		return false
+	}
+
+	location := loadZone(name)
+	return location != nil && location.String() == name
+}
+
+// loadZone returns the location of a zone id, or nil when the zone database does not know it
+func loadZone(zoneId string) *time.Location {
+	if cached, ok := zones.Load(zoneId); ok {
+		return cached.(*time.Location)
+	}
+
+	location, err := time.LoadLocation(zoneId)
+	if err != nil {
+		location = nil
+	}
 
+	zones.Store(zoneId, location)
+	return location
 }
@@ -336,4 +336,87 @@ var _ = Describe("Temporal Types", func() {
 			Entry("P-10M5DT-1.999999500S", -10, 5, -2, 500, "P-10M5DT-1.999999500S"),
 			Entry("P-10M-5DT-1.999999500S", -10, -5, -2, 500, "P-10M-5DT-1.999999500S"))
 	})
+
+	Context("Value handlers", func() {
+		dateTimeHandler := &dateTimeValueHandler{}
+		durationHandler := &durationValueHandler{}
+
+		It("should write time.Time in a known zone with its zone id", func() {
+			stockholm, err := time.LoadLocation("Europe/Stockholm")
+			Expect(err).To(BeNil())
+			value := time.Date(2019, time.July, 1, 12, 0, 0, 5, stockholm)
+
+			signature, fields, err := dateTimeHandler.Write(value)
+
+			Expect(err).To(BeNil())
+			Expect(signature).To(Equal(dateTimeWithZoneIdSignature))
+			Expect(fields).To(Equal([]interface{}{time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC).Unix(), 5, "Europe/Stockholm"}))
+		})
+
+		It("should write time.Time in a fixed or the local zone with its offset", func() {
+			for _, location := range []*time.Location{time.FixedZone("Offset", 3600), time.FixedZone("+02:00", 7200), time.Local} {
+				value := time.Date(2019, time.July, 1, 12, 0, 0, 0, location)
+				_, offset := value.Zone()
+
+				signature, fields, err := dateTimeHandler.Write(value)
+
+				Expect(err).To(BeNil())
+				Expect(signature).To(Equal(dateTimeWithOffsetSignature))
+				Expect(fields).To(Equal([]interface{}{time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC).Unix(), 0, offset}))
+			}
+		})
+
+		It("should write time.Time with an unnamed offset with its offset", func() {
+			parsed, err := time.Parse(time.RFC3339, "2020-01-01T00:00:00+02:00")
+			Expect(err).To(BeNil())
+
+			for _, value := range []time.Time{parsed, time.Date(2020, time.January, 1, 0, 0, 0, 0, time.FixedZone("", -5400))} {
+				_, offset := value.Zone()
+
+				signature, fields, err := dateTimeHandler.Write(value)
+
+				Expect(err).To(BeNil())
+				Expect(signature).To(Equal(dateTimeWithOffsetSignature))
+				Expect(fields).To(Equal([]interface{}{time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC).Unix(), 0, offset}))
+			}
+		})
+
+		It("should read time.Time with the location of its zone id", func() {
+			seconds := time.Date(2019, time.July, 1, 12, 0, 0, 0, time.UTC).Unix()
+
+			value, err := dateTimeHandler.Read(dateTimeWithZoneIdSignature, []interface{}{seconds, int64(5), "Europe/Stockholm"})
+
+			Expect(err).To(BeNil())
+			Expect(value.(time.Time).Location().String()).To(Equal("Europe/Stockholm"))
+			Expect(value.(time.Time).Format(time.RFC3339Nano)).To(Equal("2019-07-01T12:00:00.000000005+02:00"))
+		})
+
+		It("should fail to read time.Time with an unknown zone id", func() {
+			_, err := dateTimeHandler.Read(dateTimeWithZoneIdSignature, []interface{}{int64(0), int64(0), "Nowhere/Special"})
+
+			Expect(err).NotTo(BeNil())
+		})
+
+		It("should read time.Time with an offset into a fixed zone", func() {
+			value, err := dateTimeHandler.Read(dateTimeWithOffsetSignature, []interface{}{int64(0), int64(0), int64(-3600)})
+
+			Expect(err).To(BeNil())
+			name, offset := value.(time.Time).Zone()
+			Expect(name).To(Equal("Offset"))
+			Expect(offset).To(Equal(-3600))
+		})
+
+		DescribeTable("should write time.Duration as Duration",
+			func(value time.Duration, seconds int64, nanos int) {
+				signature, fields, err := durationHandler.Write(value)
+
+				Expect(err).To(BeNil())
+				Expect(signature).To(Equal(durationSignature))
+				Expect(fields).To(Equal([]interface{}{int64(0), int64(0), seconds, nanos}))
+			},
+			Entry("zero", time.Duration(0), int64(0), 0),
+			Entry("1.5s", 1500*time.Millisecond, int64(1), 500000000),
+			Entry("-1.5s", -1500*time.Millisecond, int64(-2), 500000000),
+			Entry("2h", 2*time.Hour, int64(7200), 0))
+	})
 })